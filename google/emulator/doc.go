@@ -0,0 +1,28 @@
+// Package emulator provides an in-process emulator of the Google Sheets API v4 and the Google Visualization
+// query endpoint, so that the stores can be tested end-to-end without accessing the real Google Sheets.
+//
+// Only the subset used by this library is emulated: spreadsheet get and batchUpdate (adding and deleting sheets),
+// values append, update, batchUpdate and batchClear, and the query endpoint. The formulas used by the stores
+// (e.g. ROW, VLOOKUP, MATCH and SORT) are evaluated, other functions evaluate to "#NAME?".
+// The query language supports select, where, group by, order by, limit, offset, label, format and options,
+// with the count, sum, avg, min, max, upper and lower functions.
+//
+// Dates and locale specific formatting are not emulated, so the emulator is not a replacement for the
+// integration tests against the real Google Sheets.
+//
+// Usage:
+//
+//	srv := emulator.NewServer()
+//	defer srv.Close()
+//
+//	store, err := freedb.NewGoogleSheetRowStoreWithContext(
+//		ctx,
+//		srv,
+//		"spreadsheet_id",
+//		"sheet_name",
+//		freedb.GoogleSheetRowStoreConfig{
+//			Columns:   []string{"name", "age"},
+//			Endpoints: freedb.Endpoints{API: srv.URL(), Query: srv.URL()},
+//		},
+//	)
+package emulator
@@ -0,0 +1,411 @@
+package emulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+
+	"github.com/FreeLeh/GoFreeDB/internal/google/memory"
+	"google.golang.org/api/sheets/v4"
+)
+
+const (
+	apiPathPrefix   = "/v4/spreadsheets"
+	queryPathPrefix = "/spreadsheets/d/"
+	queryPathSuffix = "/gviz/tq"
+
+	insertDataInsertRows   = "INSERT_ROWS"
+	defaultResponseHandler = "google.visualization.Query.setResponse"
+)
+
+// Server is an in-process HTTP server emulating the subset of the Google Sheets API v4 and the
+// Google Visualization query endpoint used by this library.
+//
+// Spreadsheets are kept in memory and are created on their first use, so any spreadsheet ID can be used
+// without creating it first. Each new spreadsheet contains a single "Sheet1" sheet.
+//
+// Server implements the AuthClient interface expected by the store constructors,
+// so it can be passed in place of the real authentication client.
+type Server struct {
+	backend *memory.Backend
+	server  *httptest.Server
+}
+
+// NewServer starts a new emulator server. Call Close once the server is not needed anymore.
+func NewServer() *Server {
+	s := &Server{backend: memory.NewBackend()}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// URL returns the base URL of the server. It can be used as both the API and the query endpoint.
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// HTTPClient returns an HTTP client configured to talk to the server.
+func (s *Server) HTTPClient() *http.Client {
+	return s.server.Client()
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Values returns the computed values of the given A1 notation range, e.g. "Sheet1!A1:C10".
+// Formulas are evaluated and the values are not formatted, just like UNFORMATTED_VALUE in the API.
+// The trailing empty rows and columns are not included.
+func (s *Server) Values(spreadsheetID string, a1Range string) ([][]interface{}, error) {
+	return s.backend.Values(spreadsheetID, a1Range)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	switch {
+	case strings.HasPrefix(path, queryPathPrefix) && strings.HasSuffix(path, queryPathSuffix):
+		id := strings.TrimSuffix(strings.TrimPrefix(path, queryPathPrefix), queryPathSuffix)
+		s.handleQuery(w, r, id)
+	case strings.HasPrefix(path, apiPathPrefix):
+		resp, err := s.handleAPI(r, strings.TrimPrefix(path, apiPathPrefix))
+		if err != nil {
+			writeAPIError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+	default:
+		writeAPIError(w, errNotFound(r))
+	}
+}
+
+func errNotFound(r *http.Request) error {
+	return &memory.Error{
+		Code:    http.StatusNotFound,
+		Status:  "NOT_FOUND",
+		Message: fmt.Sprintf("unknown method: %s %s", r.Method, r.URL.Path),
+	}
+}
+
+func (s *Server) handleAPI(r *http.Request, path string) (interface{}, error) {
+	if path == "" {
+		if r.Method != http.MethodPost {
+			return nil, errNotFound(r)
+		}
+		return s.createSpreadsheet(r)
+	}
+
+	path = strings.TrimPrefix(path, "/")
+	id, rest := path, ""
+	if idx := strings.IndexAny(path, "/:"); idx != -1 {
+		id, rest = path[:idx], path[idx:]
+	}
+
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		return toSpreadsheet(s.backend.Spreadsheet(id)), nil
+	case rest == ":batchUpdate" && r.Method == http.MethodPost:
+		return s.batchUpdateSpreadsheet(r, id)
+	case rest == "/values:batchUpdate" && r.Method == http.MethodPost:
+		return s.batchUpdateValues(r, id)
+	case rest == "/values:batchClear" && r.Method == http.MethodPost:
+		return s.batchClearValues(r, id)
+	case strings.HasPrefix(rest, "/values/"):
+		a1Range := strings.TrimPrefix(rest, "/values/")
+		switch {
+		case strings.HasSuffix(a1Range, ":append") && r.Method == http.MethodPost:
+			return s.appendValues(r, id, strings.TrimSuffix(a1Range, ":append"))
+		case r.Method == http.MethodPut:
+			return s.updateValues(r, id, a1Range)
+		case r.Method == http.MethodGet:
+			return s.getValues(r, id, a1Range)
+		}
+	}
+	return nil, errNotFound(r)
+}
+
+func (s *Server) createSpreadsheet(r *http.Request) (interface{}, error) {
+	var req sheets.Spreadsheet
+	if err := decodeJSON(r, &req); err != nil {
+		return nil, err
+	}
+
+	title := ""
+	if req.Properties != nil {
+		title = req.Properties.Title
+	}
+	return toSpreadsheet(s.backend.CreateSpreadsheet(title)), nil
+}
+
+func (s *Server) batchUpdateSpreadsheet(r *http.Request, id string) (interface{}, error) {
+	var req sheets.BatchUpdateSpreadsheetRequest
+	if err := decodeJSON(r, &req); err != nil {
+		return nil, err
+	}
+
+	requests := make([]memory.SheetRequest, len(req.Requests))
+	for i, request := range req.Requests {
+		switch {
+		case request.AddSheet != nil && request.AddSheet.Properties != nil:
+			props := request.AddSheet.Properties
+			add := &memory.SheetProperties{Title: props.Title}
+			if props.GridProperties != nil {
+				add.RowCount, add.ColumnCount = props.GridProperties.RowCount, props.GridProperties.ColumnCount
+			}
+			requests[i].AddSheet = add
+		case request.DeleteSheet != nil:
+			requests[i].DeleteSheetID = &request.DeleteSheet.SheetId
+		}
+	}
+
+	added, err := s.backend.BatchUpdateSheets(id, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &sheets.BatchUpdateSpreadsheetResponse{SpreadsheetId: id}
+	for i, request := range requests {
+		reply := &sheets.Response{}
+		if request.AddSheet != nil {
+			reply.AddSheet = &sheets.AddSheetResponse{Properties: toSheetProperties(added[i])}
+		}
+		resp.Replies = append(resp.Replies, reply)
+	}
+	return resp, nil
+}
+
+func (s *Server) appendValues(r *http.Request, id string, a1Range string) (interface{}, error) {
+	var req sheets.ValueRange
+	if err := decodeJSON(r, &req); err != nil {
+		return nil, err
+	}
+
+	params := r.URL.Query()
+	result, err := s.backend.Append(
+		id,
+		a1Range,
+		req.Values,
+		params.Get("valueInputOption"),
+		params.Get("insertDataOption") == insertDataInsertRows,
+		params.Get("responseValueRenderOption"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sheets.AppendValuesResponse{
+		SpreadsheetId: id,
+		Updates:       toUpdateValuesResponse(id, result, params.Get("includeValuesInResponse") == "true"),
+	}, nil
+}
+
+func (s *Server) updateValues(r *http.Request, id string, a1Range string) (interface{}, error) {
+	var req sheets.ValueRange
+	if err := decodeJSON(r, &req); err != nil {
+		return nil, err
+	}
+
+	params := r.URL.Query()
+	result, err := s.backend.Update(
+		id,
+		a1Range,
+		req.Values,
+		params.Get("valueInputOption"),
+		params.Get("responseValueRenderOption"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return toUpdateValuesResponse(id, result, params.Get("includeValuesInResponse") == "true"), nil
+}
+
+func (s *Server) batchUpdateValues(r *http.Request, id string) (interface{}, error) {
+	var req sheets.BatchUpdateValuesRequest
+	if err := decodeJSON(r, &req); err != nil {
+		return nil, err
+	}
+
+	data := make([]memory.ValueRange, len(req.Data))
+	for i, d := range req.Data {
+		data[i] = memory.ValueRange{Range: d.Range, Values: d.Values}
+	}
+
+	results, err := s.backend.BatchUpdate(id, data, req.ValueInputOption, req.ResponseValueRenderOption)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &sheets.BatchUpdateValuesResponse{SpreadsheetId: id}
+	for _, result := range results {
+		resp.Responses = append(resp.Responses, toUpdateValuesResponse(id, result, req.IncludeValuesInResponse))
+		resp.TotalUpdatedRows += result.Rows
+		resp.TotalUpdatedColumns += result.Columns
+		resp.TotalUpdatedCells += result.Cells
+		resp.TotalUpdatedSheets++
+	}
+	return resp, nil
+}
+
+func (s *Server) batchClearValues(r *http.Request, id string) (interface{}, error) {
+	var req sheets.BatchClearValuesRequest
+	if err := decodeJSON(r, &req); err != nil {
+		return nil, err
+	}
+
+	cleared, err := s.backend.Clear(id, req.Ranges)
+	if err != nil {
+		return nil, err
+	}
+	return &sheets.BatchClearValuesResponse{SpreadsheetId: id, ClearedRanges: cleared}, nil
+}
+
+func (s *Server) getValues(r *http.Request, id string, a1Range string) (interface{}, error) {
+	resolved, values, err := s.backend.Get(id, a1Range, r.URL.Query().Get("valueRenderOption"))
+	if err != nil {
+		return nil, err
+	}
+	return &sheets.ValueRange{MajorDimension: "ROWS", Range: resolved, Values: values}, nil
+}
+
+func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request, spreadsheetID string) {
+	params := r.URL.Query()
+
+	handler := defaultResponseHandler
+	for _, part := range strings.Split(params.Get("tqx"), ";") {
+		if strings.HasPrefix(part, "responseHandler:") {
+			handler = strings.TrimPrefix(part, "responseHandler:")
+		}
+	}
+
+	headers, _ := strconv.Atoi(params.Get("headers"))
+	resp := map[string]interface{}{"version": "0.6", "reqId": "0"}
+
+	// Just like the real endpoint, the query errors are reported in the body with the HTTP status 200.
+	result, err := s.backend.Query(spreadsheetID, params.Get("sheet"), params.Get("tq"), headers)
+	if qErr, ok := err.(*memory.QueryError); ok {
+		resp["status"] = "error"
+		resp["errors"] = []map[string]string{{
+			"reason":           qErr.Reason,
+			"message":          qErr.Message,
+			"detailed_message": qErr.DetailedMessage,
+		}}
+	} else if err != nil {
+		writeAPIError(w, err)
+		return
+	} else {
+		resp["status"] = "ok"
+		resp["table"] = toQueryTable(result, headers)
+	}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/javascript; charset=utf-8")
+	_, _ = fmt.Fprintf(w, "/*O_o*/\n%s(%s);", handler, body)
+}
+
+func toQueryTable(result *memory.QueryResult, headers int) map[string]interface{} {
+	cols := make([]map[string]interface{}, len(result.Columns))
+	for i, col := range result.Columns {
+		cols[i] = map[string]interface{}{"id": col.ID, "label": col.Label, "type": col.Type}
+		if col.Pattern != "" {
+			cols[i]["pattern"] = col.Pattern
+		}
+	}
+
+	rows := make([]map[string]interface{}, len(result.Rows))
+	for i, row := range result.Rows {
+		cells := make([]interface{}, len(row))
+		for j, c := range row {
+			if c == nil {
+				continue
+			}
+
+			cell := map[string]interface{}{}
+			if c.Value != nil {
+				cell["v"] = c.Value
+			}
+			if c.Formatted != "" {
+				cell["f"] = c.Formatted
+			}
+			cells[j] = cell
+		}
+		rows[i] = map[string]interface{}{"c": cells}
+	}
+	return map[string]interface{}{"cols": cols, "rows": rows, "parsedNumHeaders": headers}
+}
+
+func toUpdateValuesResponse(id string, result memory.UpdateResult, includeValues bool) *sheets.UpdateValuesResponse {
+	resp := &sheets.UpdateValuesResponse{
+		SpreadsheetId:  id,
+		UpdatedRange:   result.Range,
+		UpdatedRows:    result.Rows,
+		UpdatedColumns: result.Columns,
+		UpdatedCells:   result.Cells,
+	}
+	if includeValues {
+		resp.UpdatedData = &sheets.ValueRange{MajorDimension: "ROWS", Range: result.Range, Values: result.Values}
+	}
+	return resp
+}
+
+func toSpreadsheet(props memory.SpreadsheetProperties) *sheets.Spreadsheet {
+	result := &sheets.Spreadsheet{
+		SpreadsheetId: props.ID,
+		Properties:    &sheets.SpreadsheetProperties{Title: props.Title},
+	}
+	for _, sh := range props.Sheets {
+		result.Sheets = append(result.Sheets, &sheets.Sheet{Properties: toSheetProperties(sh)})
+	}
+	return result
+}
+
+func toSheetProperties(props memory.SheetProperties) *sheets.SheetProperties {
+	return &sheets.SheetProperties{
+		SheetId:   props.ID,
+		Title:     props.Title,
+		Index:     props.Index,
+		SheetType: "GRID",
+		GridProperties: &sheets.GridProperties{
+			RowCount:    props.RowCount,
+			ColumnCount: props.ColumnCount,
+		},
+	}
+}
+
+func decodeJSON(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return &memory.Error{
+			Code:    http.StatusBadRequest,
+			Status:  "INVALID_ARGUMENT",
+			Message: fmt.Sprintf("Invalid JSON payload received: %s", err),
+		}
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeAPIError(w http.ResponseWriter, err error) {
+	apiErr, ok := err.(*memory.Error)
+	if !ok {
+		apiErr = &memory.Error{Code: http.StatusInternalServerError, Status: "INTERNAL", Message: err.Error()}
+	}
+
+	writeJSON(w, apiErr.Code, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    apiErr.Code,
+			"message": apiErr.Message,
+			"status":  apiErr.Status,
+		},
+	})
+}
@@ -0,0 +1,117 @@
+package emulator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestWrapper(t *testing.T, srv *Server) *sheets.Wrapper {
+	wrapper, err := sheets.NewWrapperWithConfig(srv, sheets.WrapperConfig{
+		ValueRenderOption: sheets.ValueRenderUnformatted,
+		Endpoints:         sheets.Endpoints{API: srv.URL(), Query: srv.URL()},
+	})
+	assert.Nil(t, err)
+	return wrapper
+}
+
+func TestServer_Sheets(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	ctx := context.Background()
+	wrapper := newTestWrapper(t, srv)
+
+	id, err := wrapper.CreateSpreadsheet(ctx, "title")
+	assert.Nil(t, err)
+
+	assert.Nil(t, wrapper.CreateSheetWithGridSize(ctx, id, "sheet2", sheets.GridSize{ColumnCount: 3}))
+	assert.ErrorIs(t, wrapper.CreateSheet(ctx, id, "sheet2"), sheets.ErrConflict)
+
+	nameToID, err := wrapper.GetSheetNameToID(ctx, id)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int64{"Sheet1": 0, "sheet2": 1}, nameToID)
+
+	assert.Nil(t, wrapper.DeleteSheets(ctx, id, []int64{1}))
+	assert.ErrorIs(t, wrapper.DeleteSheets(ctx, id, []int64{1}), sheets.ErrSheetNotFound)
+
+	nameToID, err = wrapper.GetSheetNameToID(ctx, id)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int64{"Sheet1": 0}, nameToID)
+}
+
+func TestServer_Values(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	ctx := context.Background()
+	wrapper := newTestWrapper(t, srv)
+
+	inserted, err := wrapper.InsertRows(ctx, "id", "Sheet1!A1:C5", [][]interface{}{{"k1", "'10", "=ROW()"}, {"k2", 20, true}})
+	assert.Nil(t, err)
+	assert.Equal(t, "Sheet1!A1:C2", inserted.UpdatedRange.Original)
+	assert.Equal(t, [][]interface{}{{"k1", "10", float64(1)}, {"k2", float64(20), true}}, inserted.InsertedValues)
+
+	inserted, err = wrapper.OverwriteRows(ctx, "id", "Sheet1!A1:C1", [][]interface{}{{"k3"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "Sheet1!A3", inserted.UpdatedRange.Original)
+
+	updated, err := wrapper.UpdateRows(ctx, "id", "Sheet1!D1", [][]interface{}{{"=VLOOKUP(\"K2\", A1:C3, 2, FALSE)"}})
+	assert.Nil(t, err)
+	assert.Equal(t, [][]interface{}{{float64(20)}}, updated.UpdatedValues)
+
+	_, err = wrapper.UpdateRows(ctx, "id", "Sheet1!D1", [][]interface{}{{"a", "b"}})
+	assert.NotNil(t, err)
+
+	_, err = wrapper.UpdateRows(ctx, "id", "unknown!A1", [][]interface{}{{"a"}})
+	assert.ErrorIs(t, err, sheets.ErrInvalidRange)
+
+	_, err = wrapper.BatchUpdateRowsWithOption(ctx, "id", []sheets.BatchUpdateRowsRequest{
+		{A1Range: "Sheet1!B1", Values: [][]interface{}{{"=raw"}}},
+		{A1Range: "Sheet1!B3", Values: [][]interface{}{{"30"}}},
+	}, sheets.ValueInputRaw)
+	assert.Nil(t, err)
+
+	cleared, err := wrapper.Clear(ctx, "id", []string{"Sheet1!A2:D2", "Sheet1!D1"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Sheet1!A2:D2", "Sheet1!D1"}, cleared)
+
+	values, err := srv.Values("id", "Sheet1")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]interface{}{{"k1", "=raw", float64(1)}, {}, {"k3", "30"}}, values)
+}
+
+func TestServer_QueryRows(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	ctx := context.Background()
+	wrapper := newTestWrapper(t, srv)
+
+	_, err := wrapper.InsertRows(ctx, "id", "Sheet1", [][]interface{}{
+		{"name", "age"},
+		{"a", 10},
+		{"b", 20},
+		{"c", 20},
+	})
+	assert.Nil(t, err)
+
+	result, err := wrapper.QueryRows(ctx, "id", "Sheet1", "select A, B where B >= 20 order by A desc", true)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]interface{}{{"c", float64(20)}, {"b", float64(20)}}, result.Rows)
+	assert.Equal(t, "name", result.Columns[0].Label)
+	assert.Equal(t, "number", result.Columns[1].Type)
+
+	result, err = wrapper.QueryRows(ctx, "id", "Sheet1", "select B, count(A) group by B label count(A) 'total'", true)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]interface{}{{float64(10), float64(1)}, {float64(20), float64(2)}}, result.Rows)
+	assert.Equal(t, "total", result.Columns[1].Label)
+
+	_, err = wrapper.QueryRows(ctx, "id", "Sheet1", "select Z", true)
+	assert.ErrorIs(t, err, sheets.ErrInvalidQuery)
+
+	_, err = wrapper.QueryRows(ctx, "id", "unknown", "select A", true)
+	assert.ErrorIs(t, err, sheets.ErrSheetNotFound)
+}
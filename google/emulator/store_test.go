@@ -0,0 +1,196 @@
+package emulator_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	freedb "github.com/FreeLeh/GoFreeDB"
+	"github.com/FreeLeh/GoFreeDB/google/emulator"
+	"github.com/FreeLeh/GoFreeDB/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type person struct {
+	Name string `json:"name" db:"name"`
+	Age  int64  `json:"age" db:"age"`
+	DOB  string `json:"dob" db:"dob"`
+}
+
+func newEndpoints(srv *emulator.Server) freedb.Endpoints {
+	return freedb.Endpoints{API: srv.URL(), Query: srv.URL()}
+}
+
+func TestRowStore(t *testing.T) {
+	srv := emulator.NewServer()
+	defer srv.Close()
+
+	ctx := context.Background()
+	db, err := freedb.NewGoogleSheetRowStoreWithContext(
+		ctx,
+		srv,
+		"spreadsheet",
+		"people",
+		freedb.GoogleSheetRowStoreConfig{
+			Columns:   []string{"name", "age", "dob"},
+			Endpoints: newEndpoints(srv),
+		},
+	)
+	assert.Nil(t, err)
+
+	count, err := db.Count().Exec(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), count)
+
+	var out []person
+	assert.Nil(t, db.Select(&out, "name", "age").Offset(10).Limit(10).Exec(ctx))
+	assert.Empty(t, out)
+
+	assert.Nil(t, db.Insert(
+		person{"name1", 10, "1999-01-01"},
+		person{"name2", 11, "2000-01-01"},
+	).Exec(ctx))
+	assert.Nil(t, db.Insert(person{"name3", 9007199254740992, "2001-01-01"}).Exec(ctx))
+
+	assert.Nil(t, db.Update(map[string]interface{}{"name": "name4"}).Where("age = ?", 10).Exec(ctx))
+
+	err = db.Select(&out, "name", "age", "dob").
+		Where("name = ? OR name = ?", "name2", "name3").
+		OrderBy([]freedb.ColumnOrderBy{{Column: "name", OrderBy: freedb.OrderByDesc}}).
+		Limit(2).
+		Exec(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []person{
+		{"name3", 9007199254740992, "2001-01-01"},
+		{"name2", 11, "2000-01-01"},
+	}, out)
+
+	count, err = db.Count().Where("name = ? OR name = ?", "name2", "name3").Exec(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), count)
+
+	assert.Nil(t, db.Delete().Where("name = ?", "name4").Exec(ctx))
+
+	count, err = db.Count().Exec(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), count)
+
+	var names []person
+	err = db.Select(&names, "name").Where("age > ?", 100).Exec(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []person{{Name: "name3"}}, names)
+
+	err = db.Select(&names, "name").Where("unknown = ?", 1).Exec(ctx)
+	assert.NotNil(t, err)
+}
+
+func TestRowStore_Formula(t *testing.T) {
+	srv := emulator.NewServer()
+	defer srv.Close()
+
+	type model struct {
+		Value string `db:"value"`
+	}
+
+	ctx := context.Background()
+	db, err := freedb.NewGoogleSheetRowStoreWithContext(
+		ctx,
+		srv,
+		"spreadsheet",
+		"formula",
+		freedb.GoogleSheetRowStoreConfig{
+			Columns:            []string{"value"},
+			ColumnsWithFormula: []string{"value"},
+			Endpoints:          newEndpoints(srv),
+		},
+	)
+	assert.Nil(t, err)
+
+	assert.Nil(t, db.Insert(model{Value: "=ROW()-1"}, model{Value: "=ROW()*10"}).Exec(ctx))
+
+	values, err := srv.Values("spreadsheet", "formula!B2:B3")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]interface{}{{float64(1)}, {float64(30)}}, values)
+}
+
+func TestKVStore(t *testing.T) {
+	for _, mode := range []freedb.KVMode{freedb.KVModeDefault, freedb.KVModeAppendOnly} {
+		srv := emulator.NewServer()
+
+		ctx := context.Background()
+		kv, err := freedb.NewGoogleSheetKVStoreWithContext(
+			ctx,
+			srv,
+			"spreadsheet",
+			"kv",
+			freedb.GoogleSheetKVStoreConfig{Mode: mode, Endpoints: newEndpoints(srv)},
+		)
+		assert.Nil(t, err)
+
+		_, err = kv.Get(ctx, "k1")
+		assert.ErrorIs(t, err, models.ErrKeyNotFound)
+
+		assert.Nil(t, kv.Set(ctx, "k1", []byte("test")))
+		assert.Nil(t, kv.Set(ctx, "k2", []byte("other")))
+		// The append only mode picks the latest value based on the millisecond timestamp.
+		time.Sleep(2 * time.Millisecond)
+		assert.Nil(t, kv.Set(ctx, "k1", []byte("updated")))
+
+		value, err := kv.Get(ctx, "k1")
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("updated"), value)
+
+		value, err = kv.Get(ctx, "k2")
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("other"), value)
+
+		time.Sleep(2 * time.Millisecond)
+		assert.Nil(t, kv.Delete(ctx, "k1"))
+		_, err = kv.Get(ctx, "k1")
+		assert.ErrorIs(t, err, models.ErrKeyNotFound)
+
+		assert.Nil(t, kv.Close(ctx))
+		srv.Close()
+	}
+}
+
+func TestKVStoreV2(t *testing.T) {
+	for _, mode := range []freedb.KVMode{freedb.KVModeDefault, freedb.KVModeAppendOnly} {
+		srv := emulator.NewServer()
+
+		ctx := context.Background()
+		kv, err := freedb.NewGoogleSheetKVStoreV2WithContext(
+			ctx,
+			srv,
+			"spreadsheet",
+			"kv_v2",
+			freedb.GoogleSheetKVStoreV2Config{Mode: mode, Endpoints: newEndpoints(srv)},
+		)
+		assert.Nil(t, err)
+
+		_, err = kv.Get(ctx, "k1")
+		assert.ErrorIs(t, err, models.ErrKeyNotFound)
+
+		assert.Nil(t, kv.Set(ctx, "k1", []byte("test")))
+		assert.Nil(t, kv.Set(ctx, "k2", []byte("other")))
+		// The append only mode picks the latest value based on the millisecond timestamp.
+		time.Sleep(2 * time.Millisecond)
+		assert.Nil(t, kv.Set(ctx, "k1", []byte("updated")))
+
+		value, err := kv.Get(ctx, "k1")
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("updated"), value)
+
+		value, err = kv.Get(ctx, "k2")
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("other"), value)
+
+		time.Sleep(2 * time.Millisecond)
+		assert.Nil(t, kv.Delete(ctx, "k1"))
+		_, err = kv.Get(ctx, "k1")
+		assert.ErrorIs(t, err, models.ErrKeyNotFound)
+
+		assert.Nil(t, kv.Close(ctx))
+		srv.Close()
+	}
+}
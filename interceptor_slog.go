@@ -0,0 +1,7 @@
+//go:build go1.21
+
+package freedb
+
+import "github.com/FreeLeh/GoFreeDB/internal/google/store"
+
+var NewSlogInterceptor = store.NewSlogInterceptor
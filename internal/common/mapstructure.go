@@ -1,12 +1,40 @@
 package common
 
-import "github.com/mitchellh/mapstructure"
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
 
-func MapStructureDecode(input interface{}, output interface{}) error {
+	"github.com/mitchellh/mapstructure"
+)
+
+var (
+	bigIntType   = reflect.TypeOf(big.Int{})
+	bigFloatType = reflect.TypeOf(big.Float{})
+	bigRatType   = reflect.TypeOf(big.Rat{})
+)
+
+// IsBigNumberType returns true if the type is big.Int, big.Float or big.Rat (not a pointer to them).
+func IsBigNumberType(t reflect.Type) bool {
+	switch t {
+	case bigIntType, bigFloatType, bigRatType:
+		return true
+	default:
+		return false
+	}
+}
+
+func MapStructureDecode(input interface{}, output interface{}, hooks ...mapstructure.DecodeHookFunc) error {
 	config := &mapstructure.DecoderConfig{
 		Result:  output,
 		TagName: "db",
 	}
+	if len(hooks) > 0 {
+		config.DecodeHook = mapstructure.ComposeDecodeHookFunc(hooks...)
+	}
 
 	decoder, err := mapstructure.NewDecoder(config)
 	if err != nil {
@@ -15,3 +43,92 @@ func MapStructureDecode(input interface{}, output interface{}) error {
 
 	return decoder.Decode(input)
 }
+
+// LosslessNumberDecodeHook parses the values written by EncodeLosslessNumber back into their Go types.
+//
+// Large integers and decimals are stored as strings, while the smaller ones are stored as numbers.
+// This hook converts both representations into the integer and math/big field types.
+func LosslessNumberDecodeHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if to.Kind() == reflect.Ptr {
+		to = to.Elem()
+	}
+
+	switch data.(type) {
+	case string, float64:
+	default:
+		return data, nil
+	}
+
+	switch to {
+	case bigIntType:
+		return decodeBigInt(data)
+	case bigFloatType:
+		return decodeBigFloat(data)
+	case bigRatType:
+		return decodeBigRat(data)
+	}
+
+	switch to.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if s, ok := data.(string); ok {
+			return strconv.ParseInt(strings.TrimSpace(s), 10, to.Bits())
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if s, ok := data.(string); ok {
+			return strconv.ParseUint(strings.TrimSpace(s), 10, to.Bits())
+		}
+	}
+	return data, nil
+}
+
+func decodeBigInt(data interface{}) (*big.Int, error) {
+	switch converted := data.(type) {
+	case string:
+		result, ok := new(big.Int).SetString(strings.TrimSpace(converted), 10)
+		if !ok {
+			return nil, fmt.Errorf("cannot parse %q as a big integer", converted)
+		}
+		return result, nil
+	case float64:
+		if converted != math.Trunc(converted) {
+			return nil, fmt.Errorf("cannot convert non-integer number %v into a big integer", converted)
+		}
+		result, _ := big.NewFloat(converted).Int(nil)
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unsupported big integer value type: %T", data)
+	}
+}
+
+func decodeBigFloat(data interface{}) (*big.Float, error) {
+	switch converted := data.(type) {
+	case string:
+		result, ok := new(big.Float).SetString(strings.TrimSpace(converted))
+		if !ok {
+			return nil, fmt.Errorf("cannot parse %q as a big float", converted)
+		}
+		return result, nil
+	case float64:
+		return big.NewFloat(converted), nil
+	default:
+		return nil, fmt.Errorf("unsupported big float value type: %T", data)
+	}
+}
+
+func decodeBigRat(data interface{}) (*big.Rat, error) {
+	switch converted := data.(type) {
+	case string:
+		result, ok := new(big.Rat).SetString(strings.TrimSpace(converted))
+		if !ok {
+			return nil, fmt.Errorf("cannot parse %q as a big rational number", converted)
+		}
+		return result, nil
+	case float64:
+		// Use the shortest decimal representation of the float instead of its exact binary value.
+		// Otherwise, a value like 0.1 will be converted into 3602879701896397/36028797018963968.
+		result, _ := new(big.Rat).SetString(strconv.FormatFloat(converted, 'f', -1, 64))
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unsupported big rational number value type: %T", data)
+	}
+}
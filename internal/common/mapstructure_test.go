@@ -0,0 +1,74 @@
+package common
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapStructureDecode_LosslessNumberDecodeHook(t *testing.T) {
+	type output struct {
+		ID     int64      `db:"id"`
+		Unsafe uint64     `db:"unsafe"`
+		Big    *big.Int   `db:"big"`
+		Amount *big.Rat   `db:"amount"`
+		Ratio  *big.Rat   `db:"ratio"`
+		Float  *big.Float `db:"float"`
+	}
+
+	input := []map[string]interface{}{
+		{
+			"id":     "9007199254740993",
+			"unsafe": "18446744073709551615",
+			"big":    "123456789012345678901234567890",
+			"amount": "19.99",
+			"ratio":  0.5,
+			"float":  2.5,
+		},
+		{
+			"id":     float64(10),
+			"unsafe": float64(11),
+			"big":    float64(12),
+			"amount": "1/3",
+			"ratio":  "0.1",
+			"float":  "0.1",
+		},
+	}
+
+	var out []output
+	assert.Nil(t, MapStructureDecode(input, &out, LosslessNumberDecodeHook))
+	assert.Len(t, out, 2)
+
+	assert.Equal(t, int64(9007199254740993), out[0].ID)
+	assert.Equal(t, uint64(18446744073709551615), out[0].Unsafe)
+	assert.Equal(t, "123456789012345678901234567890", out[0].Big.String())
+	assert.Equal(t, 0, big.NewRat(1999, 100).Cmp(out[0].Amount))
+	assert.Equal(t, 0, big.NewRat(1, 2).Cmp(out[0].Ratio))
+	assert.Equal(t, "2.5", out[0].Float.Text('g', -1))
+
+	assert.Equal(t, int64(10), out[1].ID)
+	assert.Equal(t, uint64(11), out[1].Unsafe)
+	assert.Equal(t, "12", out[1].Big.String())
+	assert.Equal(t, 0, big.NewRat(1, 3).Cmp(out[1].Amount))
+	assert.Equal(t, 0, big.NewRat(1, 10).Cmp(out[1].Ratio))
+
+	t.Run("invalid_string", func(t *testing.T) {
+		var out []output
+		assert.NotNil(t, MapStructureDecode([]map[string]interface{}{{"big": "abc"}}, &out, LosslessNumberDecodeHook))
+	})
+
+	t.Run("without_hook", func(t *testing.T) {
+		var out []output
+		assert.NotNil(t, MapStructureDecode([]map[string]interface{}{{"id": "9007199254740993"}}, &out))
+	})
+}
+
+func TestIsBigNumberType(t *testing.T) {
+	assert.True(t, IsBigNumberType(reflect.TypeOf(big.Int{})))
+	assert.True(t, IsBigNumberType(reflect.TypeOf(big.Float{})))
+	assert.True(t, IsBigNumberType(reflect.TypeOf(big.Rat{})))
+	assert.False(t, IsBigNumberType(reflect.TypeOf(&big.Int{})))
+	assert.False(t, IsBigNumberType(reflect.TypeOf(int64(0))))
+}
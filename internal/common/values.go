@@ -3,8 +3,12 @@ package common
 import (
 	"errors"
 	"fmt"
+	"math/big"
+	"strconv"
 )
 
+const maxRatDecimalDigits = 64
+
 func EscapeValue(value interface{}) interface{} {
 	// This is to ensure that string value will always be a string representation in Google Sheets.
 	// Without this, "1" may be converted automatically into an integer.
@@ -38,3 +42,123 @@ func isIEEE754SafeInteger(value int64) error {
 	}
 	return errors.New("integer provided is not within the IEEE 754 safe integer boundary of [-(2^53), 2^53], the integer may have a precision lost")
 }
+
+// EncodeLosslessNumber converts numbers that cannot be represented exactly by a Google Sheets number cell
+// into an escaped string, so that no precision is lost when the value is written.
+//
+// Integers within the IEEE 754 safe integer boundary and math/big values that can be represented exactly as a
+// float64 are kept (or converted) as numbers, so that numeric comparisons still work for them.
+// The second return value indicates whether the given value is a number handled by this function.
+func EncodeLosslessNumber(value interface{}) (interface{}, bool) {
+	converted, ok := LosslessNumber(value)
+	if !ok {
+		return value, false
+	}
+	return EscapeValue(converted), true
+}
+
+// LosslessNumber works just like EncodeLosslessNumber, but the string representation is not escaped.
+// This is useful for building query arguments that are compared against the stored values.
+func LosslessNumber(value interface{}) (interface{}, bool) {
+	switch converted := value.(type) {
+	case int:
+		return losslessInt(big.NewInt(int64(converted))), true
+	case int64:
+		return losslessInt(big.NewInt(converted)), true
+	case uint:
+		return losslessInt(new(big.Int).SetUint64(uint64(converted))), true
+	case uint64:
+		return losslessInt(new(big.Int).SetUint64(converted)), true
+	case *big.Int:
+		if converted == nil {
+			return nil, true
+		}
+		return losslessInt(converted), true
+	case *big.Float:
+		if converted == nil {
+			return nil, true
+		}
+		if f, accuracy := converted.Float64(); accuracy == big.Exact {
+			return f, true
+		}
+		return converted.Text('g', -1), true
+	case *big.Rat:
+		if converted == nil {
+			return nil, true
+		}
+		if f, exact := converted.Float64(); exact {
+			return f, true
+		}
+		return formatRat(converted), true
+	default:
+		return value, false
+	}
+}
+
+// NumberString returns the exact decimal representation of the given integer, float or math/big value,
+// so that the same number always has the same string representation regardless of its type.
+// The second return value indicates whether the given value is a non-nil number handled by this function.
+func NumberString(value interface{}) (string, bool) {
+	switch converted := value.(type) {
+	case int:
+		return strconv.FormatInt(int64(converted), 10), true
+	case int8:
+		return strconv.FormatInt(int64(converted), 10), true
+	case int16:
+		return strconv.FormatInt(int64(converted), 10), true
+	case int32:
+		return strconv.FormatInt(int64(converted), 10), true
+	case int64:
+		return strconv.FormatInt(converted, 10), true
+	case uint:
+		return strconv.FormatUint(uint64(converted), 10), true
+	case uint8:
+		return strconv.FormatUint(uint64(converted), 10), true
+	case uint16:
+		return strconv.FormatUint(uint64(converted), 10), true
+	case uint32:
+		return strconv.FormatUint(uint64(converted), 10), true
+	case uint64:
+		return strconv.FormatUint(converted, 10), true
+	case float32:
+		return strconv.FormatFloat(float64(converted), 'f', -1, 32), true
+	case float64:
+		return strconv.FormatFloat(converted, 'f', -1, 64), true
+	case *big.Int:
+		if converted == nil {
+			return "", false
+		}
+		return converted.String(), true
+	case *big.Float:
+		if converted == nil {
+			return "", false
+		}
+		return converted.Text('f', -1), true
+	case *big.Rat:
+		if converted == nil {
+			return "", false
+		}
+		return formatRat(converted), true
+	default:
+		return "", false
+	}
+}
+
+func losslessInt(value *big.Int) interface{} {
+	if value.IsInt64() && isIEEE754SafeInteger(value.Int64()) == nil {
+		return value.Int64()
+	}
+	return value.String()
+}
+
+// formatRat returns the shortest exact decimal representation of the given rational number.
+// If the number does not have a finite decimal representation (e.g. 1/3), the fraction form is returned instead.
+func formatRat(value *big.Rat) string {
+	for prec := 0; prec <= maxRatDecimalDigits; prec++ {
+		s := value.FloatString(prec)
+		if parsed, ok := new(big.Rat).SetString(s); ok && parsed.Cmp(value) == 0 {
+			return s
+		}
+	}
+	return value.RatString()
+}
@@ -1,8 +1,10 @@
 package common
 
 import (
-	"github.com/stretchr/testify/assert"
+	"math/big"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestEscapeValue(t *testing.T) {
@@ -33,3 +35,77 @@ func TestCheckIEEE754SafeInteger(t *testing.T) {
 	assert.Nil(t, CheckIEEE754SafeInteger(true))
 	assert.Nil(t, CheckIEEE754SafeInteger([]byte("something")))
 }
+
+func TestEncodeLosslessNumber(t *testing.T) {
+	tc := []struct {
+		name     string
+		input    interface{}
+		expected interface{}
+		ok       bool
+	}{
+		{name: "safe_int64", input: int64(9007199254740992), expected: int64(9007199254740992), ok: true},
+		{name: "unsafe_int64", input: int64(9007199254740993), expected: "'9007199254740993", ok: true},
+		{name: "unsafe_uint64", input: uint64(18446744073709551615), expected: "'18446744073709551615", ok: true},
+		{name: "safe_big_int", input: big.NewInt(10), expected: int64(10), ok: true},
+		{name: "unsafe_big_int", input: mustBigInt("123456789012345678901234567890"), expected: "'123456789012345678901234567890", ok: true},
+		{name: "exact_big_rat", input: big.NewRat(3, 2), expected: 1.5, ok: true},
+		{name: "decimal_big_rat", input: big.NewRat(1999, 100), expected: "'19.99", ok: true},
+		{name: "fraction_big_rat", input: big.NewRat(1, 3), expected: "'1/3", ok: true},
+		{name: "exact_big_float", input: big.NewFloat(2.5), expected: 2.5, ok: true},
+		{name: "nil_big_int", input: (*big.Int)(nil), expected: nil, ok: true},
+		{name: "string", input: "blah", expected: "blah", ok: false},
+		{name: "float64", input: 1.5, expected: 1.5, ok: false},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			result, ok := EncodeLosslessNumber(c.input)
+			assert.Equal(t, c.expected, result)
+			assert.Equal(t, c.ok, ok)
+		})
+	}
+
+	t.Run("inexact_big_float", func(t *testing.T) {
+		f, _ := new(big.Float).SetPrec(200).SetString("0.1")
+		result, ok := EncodeLosslessNumber(f)
+		assert.True(t, ok)
+		assert.Equal(t, "'0.1", result)
+	})
+}
+
+func mustBigInt(s string) *big.Int {
+	result, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid big integer: " + s)
+	}
+	return result
+}
+
+func TestNumberString(t *testing.T) {
+	tc := []struct {
+		name     string
+		input    interface{}
+		expected string
+		ok       bool
+	}{
+		{name: "int", input: 1, expected: "1", ok: true},
+		{name: "negative_int8", input: int8(-8), expected: "-8", ok: true},
+		{name: "max_uint64", input: uint64(18446744073709551615), expected: "18446744073709551615", ok: true},
+		{name: "float64", input: 1.5, expected: "1.5", ok: true},
+		{name: "large_float64", input: 1e21, expected: "1000000000000000000000", ok: true},
+		{name: "big_int", input: mustBigInt("123456789012345678901234567890"), expected: "123456789012345678901234567890", ok: true},
+		{name: "big_float", input: big.NewFloat(2.5), expected: "2.5", ok: true},
+		{name: "big_rat", input: big.NewRat(1999, 100), expected: "19.99", ok: true},
+		{name: "nil_big_int", input: (*big.Int)(nil), expected: "", ok: false},
+		{name: "string", input: "1", expected: "", ok: false},
+		{name: "bool", input: true, expected: "", ok: false},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			result, ok := NumberString(c.input)
+			assert.Equal(t, c.expected, result)
+			assert.Equal(t, c.ok, ok)
+		})
+	}
+}
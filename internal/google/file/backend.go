@@ -0,0 +1,224 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/FreeLeh/GoFreeDB/internal/google/memory"
+)
+
+// Format defines the file format used for storing each sheet.
+type Format string
+
+const (
+	// FormatCSV stores each sheet as a CSV file.
+	FormatCSV Format = "csv"
+
+	// FormatXLSX stores each sheet as an XLSX workbook containing a single worksheet.
+	FormatXLSX Format = "xlsx"
+)
+
+// Backend stores spreadsheets as local files, so that the stores can be used without Google credentials.
+//
+// Each spreadsheet is a directory inside the root directory, while each sheet is a file inside the spreadsheet
+// directory, e.g. "<dir>/<spreadsheet_id>/<sheet_name>.csv". The cells are stored as user entered values:
+// formulas start with "=" and strings that look like a number, a boolean or a formula are prefixed with an
+// apostrophe, just like in the Google Sheets UI.
+//
+// The spreadsheets are loaded into a memory.Backend on their first use and the files are rewritten after every
+// write operation. Spreadsheets are created on their first use, just like in memory.Backend.
+// It is safe for concurrent use within a process, but the files must not be modified while they are in use.
+type Backend struct {
+	mu     sync.Mutex
+	dir    string
+	format Format
+	memory *memory.Backend
+	loaded map[string]bool
+}
+
+// NewBackend creates a backend storing the spreadsheets inside the given directory.
+// The directory is created if it does not exist yet.
+func NewBackend(dir string, format Format) (*Backend, error) {
+	if format != FormatCSV && format != FormatXLSX {
+		return nil, fmt.Errorf("unsupported file format: %q", format)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating directory %s: %w", dir, err)
+	}
+
+	return &Backend{
+		dir:    dir,
+		format: format,
+		memory: memory.NewBackend(),
+		loaded: make(map[string]bool),
+	}, nil
+}
+
+// createSpreadsheet creates a new spreadsheet directory named after the title.
+func (b *Backend) createSpreadsheet(title string) (string, error) {
+	base := title
+	if base == "" {
+		base = "spreadsheet"
+	}
+
+	id := base
+	for i := 2; ; i++ {
+		_, err := os.Stat(b.spreadsheetDir(id))
+		if errors.Is(err, os.ErrNotExist) && !b.loaded[id] {
+			break
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("error checking spreadsheet %s: %w", id, err)
+		}
+		id = base + "-" + strconv.Itoa(i)
+	}
+
+	if err := b.memory.Restore(id, nil); err != nil {
+		return "", err
+	}
+	b.loaded[id] = true
+	return id, b.save(id)
+}
+
+// load reads the spreadsheet files into the memory backend, unless it has been loaded before.
+func (b *Backend) load(spreadsheetID string) error {
+	if b.loaded[spreadsheetID] {
+		return nil
+	}
+
+	entries, err := os.ReadDir(b.spreadsheetDir(spreadsheetID))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error reading spreadsheet %s: %w", spreadsheetID, err)
+	}
+
+	var sheets []memory.SheetSnapshot
+	for _, entry := range entries {
+		title, ok := b.sheetTitle(entry.Name())
+		if entry.IsDir() || !ok {
+			continue
+		}
+
+		values, err := b.readSheet(filepath.Join(b.spreadsheetDir(spreadsheetID), entry.Name()))
+		if err != nil {
+			return fmt.Errorf("error reading sheet %s: %w", title, err)
+		}
+		sheets = append(sheets, memory.SheetSnapshot{Title: title, Values: values})
+	}
+	sort.Slice(sheets, func(i, j int) bool { return sheets[i].Title < sheets[j].Title })
+
+	if err := b.memory.Restore(spreadsheetID, sheets); err != nil {
+		return err
+	}
+	b.loaded[spreadsheetID] = true
+	return nil
+}
+
+// save writes every sheet of the spreadsheet into its file and removes the files of the deleted sheets.
+func (b *Backend) save(spreadsheetID string) error {
+	dir := b.spreadsheetDir(spreadsheetID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("error creating spreadsheet %s: %w", spreadsheetID, err)
+	}
+
+	names := make(map[string]bool)
+	for _, sheet := range b.memory.Snapshot(spreadsheetID) {
+		name := b.sheetFileName(sheet.Title)
+		names[name] = true
+
+		if err := b.writeSheet(filepath.Join(dir, name), sheet.Values); err != nil {
+			return fmt.Errorf("error writing sheet %s: %w", sheet.Title, err)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("error reading spreadsheet %s: %w", spreadsheetID, err)
+	}
+	for _, entry := range entries {
+		if _, ok := b.sheetTitle(entry.Name()); !ok || entry.IsDir() || names[entry.Name()] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return fmt.Errorf("error removing deleted sheet file %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+func (b *Backend) readSheet(path string) ([][]interface{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if b.format == FormatXLSX {
+		stat, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+		return readXLSX(f, stat.Size())
+	}
+	return readCSV(f)
+}
+
+// writeSheet writes the values into a temporary file first, so that a failed write does not corrupt the sheet.
+func (b *Backend) writeSheet(path string, values [][]interface{}) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if b.format == FormatXLSX {
+		err = writeXLSX(tmp, values)
+	} else {
+		err = writeCSV(tmp, values)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (b *Backend) spreadsheetDir(spreadsheetID string) string {
+	return filepath.Join(b.dir, escapeName(spreadsheetID))
+}
+
+func (b *Backend) sheetFileName(title string) string {
+	return escapeName(title) + "." + string(b.format)
+}
+
+// sheetTitle returns the sheet title stored in the given file name, if it is a sheet file.
+func (b *Backend) sheetTitle(name string) (string, bool) {
+	escaped := strings.TrimSuffix(name, "."+string(b.format))
+	if escaped == name || strings.HasPrefix(name, ".") {
+		return "", false
+	}
+
+	title, err := url.PathUnescape(escaped)
+	if err != nil {
+		return "", false
+	}
+	return title, true
+}
+
+// escapeName converts a spreadsheet ID or a sheet title into a file name.
+// A leading dot is escaped as well, so that the name is neither hidden nor "." or "..".
+func escapeName(name string) string {
+	escaped := url.PathEscape(name)
+	if strings.HasPrefix(escaped, ".") {
+		escaped = "%2E" + escaped[1:]
+	}
+	return escaped
+}
@@ -0,0 +1,68 @@
+package file
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// readCSV reads the values of a CSV file. Every value is returned as a user entered string.
+func readCSV(r io.Reader) ([][]interface{}, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([][]interface{}, len(records))
+	for i, record := range records {
+		values[i] = make([]interface{}, len(record))
+		for j, field := range record {
+			values[i][j] = field
+		}
+	}
+	return values, nil
+}
+
+// writeCSV writes the user entered values into a CSV file.
+// The rows are padded with empty fields, so that every row has the same number of fields.
+func writeCSV(w io.Writer, values [][]interface{}) error {
+	width := 0
+	for _, row := range values {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+
+	writer := csv.NewWriter(w)
+	for _, row := range values {
+		record := make([]string, width)
+		for i, value := range row {
+			record[i] = formatCSVValue(value)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatCSVValue(value interface{}) string {
+	switch converted := value.(type) {
+	case string:
+		return converted
+	case float64:
+		return strconv.FormatFloat(converted, 'f', -1, 64)
+	case bool:
+		if converted {
+			return "TRUE"
+		}
+		return "FALSE"
+	default:
+		return fmt.Sprint(converted)
+	}
+}
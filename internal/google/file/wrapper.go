@@ -0,0 +1,177 @@
+package file
+
+import (
+	"context"
+
+	"github.com/FreeLeh/GoFreeDB/internal/google/memory"
+	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
+)
+
+// Wrapper provides the same methods as sheets.Wrapper, but the data is stored in local files through a Backend.
+// The spreadsheet files are rewritten after every successful write operation.
+type Wrapper struct {
+	backend *Backend
+	memory  *memory.Wrapper
+}
+
+// NewWrapper creates a Wrapper rendering the returned values based on the given render option.
+// An empty render option uses sheets.ValueRenderFormatted, just like sheets.Wrapper.
+func NewWrapper(backend *Backend, renderOption sheets.ValueRenderOption) *Wrapper {
+	return &Wrapper{backend: backend, memory: memory.NewWrapper(backend.memory, renderOption)}
+}
+
+func (w *Wrapper) CreateSpreadsheet(ctx context.Context, title string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	w.backend.mu.Lock()
+	defer w.backend.mu.Unlock()
+
+	return w.backend.createSpreadsheet(title)
+}
+
+func (w *Wrapper) CreateSheet(ctx context.Context, spreadsheetID string, sheetName string) error {
+	return w.write(spreadsheetID, func() error {
+		return w.memory.CreateSheet(ctx, spreadsheetID, sheetName)
+	})
+}
+
+func (w *Wrapper) CreateSheetWithGridSize(
+	ctx context.Context,
+	spreadsheetID string,
+	sheetName string,
+	size sheets.GridSize,
+) error {
+	return w.write(spreadsheetID, func() error {
+		return w.memory.CreateSheetWithGridSize(ctx, spreadsheetID, sheetName, size)
+	})
+}
+
+func (w *Wrapper) GetSheetNameToID(ctx context.Context, spreadsheetID string) (map[string]int64, error) {
+	var result map[string]int64
+	err := w.read(spreadsheetID, func() (err error) {
+		result, err = w.memory.GetSheetNameToID(ctx, spreadsheetID)
+		return err
+	})
+	return result, err
+}
+
+func (w *Wrapper) DeleteSheets(ctx context.Context, spreadsheetID string, sheetIDs []int64) error {
+	return w.write(spreadsheetID, func() error {
+		return w.memory.DeleteSheets(ctx, spreadsheetID, sheetIDs)
+	})
+}
+
+func (w *Wrapper) InsertRows(
+	ctx context.Context,
+	spreadsheetID string,
+	a1Range string,
+	values [][]interface{},
+) (sheets.InsertRowsResult, error) {
+	var result sheets.InsertRowsResult
+	err := w.write(spreadsheetID, func() (err error) {
+		result, err = w.memory.InsertRows(ctx, spreadsheetID, a1Range, values)
+		return err
+	})
+	return result, err
+}
+
+func (w *Wrapper) OverwriteRows(
+	ctx context.Context,
+	spreadsheetID string,
+	a1Range string,
+	values [][]interface{},
+) (sheets.InsertRowsResult, error) {
+	var result sheets.InsertRowsResult
+	err := w.write(spreadsheetID, func() (err error) {
+		result, err = w.memory.OverwriteRows(ctx, spreadsheetID, a1Range, values)
+		return err
+	})
+	return result, err
+}
+
+func (w *Wrapper) UpdateRows(
+	ctx context.Context,
+	spreadsheetID string,
+	a1Range string,
+	values [][]interface{},
+) (sheets.UpdateRowsResult, error) {
+	var result sheets.UpdateRowsResult
+	err := w.write(spreadsheetID, func() (err error) {
+		result, err = w.memory.UpdateRows(ctx, spreadsheetID, a1Range, values)
+		return err
+	})
+	return result, err
+}
+
+func (w *Wrapper) BatchUpdateRows(
+	ctx context.Context,
+	spreadsheetID string,
+	requests []sheets.BatchUpdateRowsRequest,
+) (sheets.BatchUpdateRowsResult, error) {
+	return w.BatchUpdateRowsWithOption(ctx, spreadsheetID, requests, sheets.ValueInputUserEntered)
+}
+
+func (w *Wrapper) BatchUpdateRowsWithOption(
+	ctx context.Context,
+	spreadsheetID string,
+	requests []sheets.BatchUpdateRowsRequest,
+	option sheets.ValueInputOption,
+) (sheets.BatchUpdateRowsResult, error) {
+	var result sheets.BatchUpdateRowsResult
+	err := w.write(spreadsheetID, func() (err error) {
+		result, err = w.memory.BatchUpdateRowsWithOption(ctx, spreadsheetID, requests, option)
+		return err
+	})
+	return result, err
+}
+
+func (w *Wrapper) QueryRows(
+	ctx context.Context,
+	spreadsheetID string,
+	sheetName string,
+	query string,
+	skipHeader bool,
+) (sheets.QueryRowsResult, error) {
+	var result sheets.QueryRowsResult
+	err := w.read(spreadsheetID, func() (err error) {
+		result, err = w.memory.QueryRows(ctx, spreadsheetID, sheetName, query, skipHeader)
+		return err
+	})
+	return result, err
+}
+
+func (w *Wrapper) Clear(ctx context.Context, spreadsheetID string, ranges []string) ([]string, error) {
+	var result []string
+	err := w.write(spreadsheetID, func() (err error) {
+		result, err = w.memory.Clear(ctx, spreadsheetID, ranges)
+		return err
+	})
+	return result, err
+}
+
+// read runs the operation after loading the spreadsheet files.
+func (w *Wrapper) read(spreadsheetID string, op func() error) error {
+	w.backend.mu.Lock()
+	defer w.backend.mu.Unlock()
+
+	if err := w.backend.load(spreadsheetID); err != nil {
+		return err
+	}
+	return op()
+}
+
+// write runs the operation after loading the spreadsheet files and saves the spreadsheet if it succeeds.
+func (w *Wrapper) write(spreadsheetID string, op func() error) error {
+	w.backend.mu.Lock()
+	defer w.backend.mu.Unlock()
+
+	if err := w.backend.load(spreadsheetID); err != nil {
+		return err
+	}
+	if err := op(); err != nil {
+		return err
+	}
+	return w.backend.save(spreadsheetID)
+}
@@ -0,0 +1,84 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapper_Persistence(t *testing.T) {
+	for _, format := range []Format{FormatCSV, FormatXLSX} {
+		t.Run(string(format), func(t *testing.T) {
+			ctx := context.Background()
+			dir := t.TempDir()
+
+			backend, err := NewBackend(dir, format)
+			assert.Nil(t, err)
+			w := NewWrapper(backend, sheets.ValueRenderUnformatted)
+
+			assert.Nil(t, w.CreateSheet(ctx, "spreadsheet", "data/1"))
+			_, err = w.OverwriteRows(ctx, "spreadsheet", "'data/1'!A1:D", [][]interface{}{
+				{"name", "age", "active", "total"},
+				{"'10", 10, true, "=B2*2"},
+				{"a,\"b\"\nc", 9007199254740992, false, "=B3+1"},
+			})
+			assert.Nil(t, err)
+
+			_, err = os.Stat(filepath.Join(dir, "spreadsheet", "data%2F1."+string(format)))
+			assert.Nil(t, err)
+
+			// A new backend reads the files written by the previous one.
+			backend, err = NewBackend(dir, format)
+			assert.Nil(t, err)
+			w = NewWrapper(backend, sheets.ValueRenderUnformatted)
+
+			result, err := w.QueryRows(ctx, "spreadsheet", "data/1", "select A, B, C, D order by B", true)
+			assert.Nil(t, err)
+			assert.Equal(t, [][]interface{}{
+				{"10", float64(10), true, float64(20)},
+				{"a,\"b\"\nc", float64(9007199254740992), false, float64(9007199254740993)},
+			}, result.Rows)
+
+			nameToID, err := w.GetSheetNameToID(ctx, "spreadsheet")
+			assert.Nil(t, err)
+			assert.Nil(t, w.DeleteSheets(ctx, "spreadsheet", []int64{nameToID["data/1"]}))
+
+			_, err = os.Stat(filepath.Join(dir, "spreadsheet", "data%2F1."+string(format)))
+			assert.ErrorIs(t, err, os.ErrNotExist)
+		})
+	}
+}
+
+func TestWrapper_CreateSpreadsheet(t *testing.T) {
+	ctx := context.Background()
+	backend, err := NewBackend(t.TempDir(), FormatCSV)
+	assert.Nil(t, err)
+	w := NewWrapper(backend, "")
+
+	id, err := w.CreateSpreadsheet(ctx, "title")
+	assert.Nil(t, err)
+	assert.Equal(t, "title", id)
+
+	id, err = w.CreateSpreadsheet(ctx, "title")
+	assert.Nil(t, err)
+	assert.Equal(t, "title-2", id)
+
+	nameToID, err := w.GetSheetNameToID(ctx, id)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int64{"Sheet1": 0}, nameToID)
+}
+
+func TestNewBackend_InvalidFormat(t *testing.T) {
+	_, err := NewBackend(t.TempDir(), Format("json"))
+	assert.NotNil(t, err)
+}
+
+func TestEscapeName(t *testing.T) {
+	assert.Equal(t, "sheet", escapeName("sheet"))
+	assert.Equal(t, "a%2Fb", escapeName("a/b"))
+	assert.Equal(t, "%2E.", escapeName(".."))
+}
@@ -0,0 +1,343 @@
+package file
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/FreeLeh/GoFreeDB/internal/common"
+)
+
+const (
+	xlsxWorkbookPath     = "xl/workbook.xml"
+	xlsxWorkbookRelsPath = "xl/_rels/workbook.xml.rels"
+	xlsxWorksheetPath    = "xl/worksheets/sheet1.xml"
+	xlsxSharedStrings    = "xl/sharedStrings.xml"
+)
+
+// The static parts of an XLSX workbook containing a single worksheet.
+// The formulas are recalculated when the workbook is opened, as their values are not stored.
+var xlsxStaticParts = []struct {
+	name    string
+	content string
+}{
+	{
+		name: "[Content_Types].xml",
+		content: `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ` +
+			`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ` +
+			`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`,
+	},
+	{
+		name: "_rels/.rels",
+		content: `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Target="xl/workbook.xml" ` +
+			`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument"/>` +
+			`</Relationships>`,
+	},
+	{
+		name: xlsxWorkbookPath,
+		content: `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>` +
+			`<calcPr fullCalcOnLoad="1"/>` +
+			`</workbook>`,
+	},
+	{
+		name: xlsxWorkbookRelsPath,
+		content: `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Target="worksheets/sheet1.xml" ` +
+			`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet"/>` +
+			`</Relationships>`,
+	},
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	var b strings.Builder
+	b.WriteString(t.Text)
+	for _, run := range t.Runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+type xlsxSharedStringTable struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Ref   int `xml:"r,attr"`
+		Cells []struct {
+			Ref     string    `xml:"r,attr"`
+			Type    string    `xml:"t,attr"`
+			Formula string    `xml:"f"`
+			Value   string    `xml:"v"`
+			Inline  *xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSX reads the values of the first worksheet in an XLSX workbook as user entered values.
+// Only the cell values and formulas are read, the formatting is ignored.
+func readXLSX(r io.ReaderAt, size int64) ([][]interface{}, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	var sharedStrings xlsxSharedStringTable
+	if err := decodeXLSXPart(files, xlsxSharedStrings, &sharedStrings); err != nil && !errors.Is(err, errXLSXPartNotFound) {
+		return nil, err
+	}
+
+	var worksheet xlsxWorksheet
+	if err := decodeXLSXPart(files, firstWorksheetPath(files), &worksheet); err != nil {
+		return nil, err
+	}
+
+	var values [][]interface{}
+	for i, row := range worksheet.Rows {
+		rowIdx := i
+		if row.Ref > 0 {
+			rowIdx = row.Ref - 1
+		}
+		for len(values) <= rowIdx {
+			values = append(values, nil)
+		}
+
+		for j, c := range row.Cells {
+			colIdx := j
+			if c.Ref != "" {
+				if colIdx, err = columnIndex(c.Ref); err != nil {
+					return nil, err
+				}
+			}
+			for len(values[rowIdx]) <= colIdx {
+				values[rowIdx] = append(values[rowIdx], "")
+			}
+
+			value, err := xlsxCellValue(c.Type, c.Formula, c.Value, c.Inline, sharedStrings)
+			if err != nil {
+				return nil, fmt.Errorf("error reading cell %s: %w", c.Ref, err)
+			}
+			values[rowIdx][colIdx] = value
+		}
+	}
+	return values, nil
+}
+
+func xlsxCellValue(
+	cellType string,
+	formula string,
+	value string,
+	inline *xlsxText,
+	sharedStrings xlsxSharedStringTable,
+) (interface{}, error) {
+	if formula != "" {
+		return "=" + formula, nil
+	}
+
+	switch cellType {
+	case "s":
+		idx, err := strconv.Atoi(value)
+		if err != nil || idx < 0 || idx >= len(sharedStrings.Items) {
+			return nil, fmt.Errorf("invalid shared string index: %s", value)
+		}
+		return escapeString(sharedStrings.Items[idx].String()), nil
+	case "inlineStr":
+		if inline == nil {
+			return "", nil
+		}
+		return escapeString(inline.String()), nil
+	case "b":
+		return value == "1", nil
+	case "str", "e":
+		return escapeString(value), nil
+	default:
+		if value == "" {
+			return "", nil
+		}
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return escapeString(value), nil
+		}
+		return f, nil
+	}
+}
+
+// escapeString prefixes a non-empty string with an apostrophe, so that it is not parsed as a number or a formula.
+func escapeString(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "'" + value
+}
+
+// firstWorksheetPath returns the path of the first worksheet in the workbook.
+func firstWorksheetPath(files map[string]*zip.File) string {
+	var workbook xlsxWorkbook
+	var rels xlsxRelationships
+	if decodeXLSXPart(files, xlsxWorkbookPath, &workbook) != nil || len(workbook.Sheets) == 0 ||
+		decodeXLSXPart(files, xlsxWorkbookRelsPath, &rels) != nil {
+		return xlsxWorksheetPath
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].RelID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/")
+		}
+		return path.Join(path.Dir(xlsxWorkbookPath), rel.Target)
+	}
+	return xlsxWorksheetPath
+}
+
+var errXLSXPartNotFound = errors.New("xlsx part not found")
+
+func decodeXLSXPart(files map[string]*zip.File, name string, v interface{}) error {
+	f, ok := files[name]
+	if !ok {
+		return errXLSXPartNotFound
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("error decoding %s: %w", name, err)
+	}
+	return nil
+}
+
+// writeXLSX writes the user entered values into an XLSX workbook containing a single worksheet.
+func writeXLSX(w io.Writer, values [][]interface{}) error {
+	archive := zip.NewWriter(w)
+	for _, part := range xlsxStaticParts {
+		if err := writeXLSXPart(archive, part.name, []byte(part.content)); err != nil {
+			return err
+		}
+	}
+
+	var sheet bytes.Buffer
+	sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for i, row := range values {
+		if len(row) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&sheet, `<row r="%d">`, i+1)
+		for j, value := range row {
+			writeXLSXCell(&sheet, common.GenerateColumnName(j)+strconv.Itoa(i+1), value)
+		}
+		sheet.WriteString(`</row>`)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+
+	if err := writeXLSXPart(archive, xlsxWorksheetPath, sheet.Bytes()); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+func writeXLSXCell(b *bytes.Buffer, ref string, value interface{}) {
+	switch converted := value.(type) {
+	case float64:
+		fmt.Fprintf(b, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(converted, 'g', -1, 64))
+	case bool:
+		v := "0"
+		if converted {
+			v = "1"
+		}
+		fmt.Fprintf(b, `<c r="%s" t="b"><v>%s</v></c>`, ref, v)
+	case string:
+		switch {
+		case converted == "":
+			return
+		case strings.HasPrefix(converted, "="):
+			fmt.Fprintf(b, `<c r="%s"><f>%s</f></c>`, ref, escapeXML(converted[1:]))
+		default:
+			fmt.Fprintf(
+				b,
+				`<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`,
+				ref,
+				escapeXML(strings.TrimPrefix(converted, "'")),
+			)
+		}
+	}
+}
+
+func writeXLSXPart(archive *zip.Writer, name string, content []byte) error {
+	part, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write([]byte(xml.Header)); err != nil {
+		return err
+	}
+	_, err = part.Write(content)
+	return err
+}
+
+func escapeXML(value string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
+
+// columnIndex returns the 0-based column index of a cell reference, e.g. 27 for "AB12".
+func columnIndex(ref string) (int, error) {
+	idx := 0
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		idx = idx*26 + int(r-'A'+1)
+		n++
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("invalid cell reference: %s", ref)
+	}
+	return idx - 1, nil
+}
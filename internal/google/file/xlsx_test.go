@@ -0,0 +1,62 @@
+package file
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadXLSX_SharedStrings(t *testing.T) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	parts := map[string]string{
+		xlsxWorkbookPath: `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="People" sheetId="3" r:id="rId7"/></sheets></workbook>`,
+		xlsxWorkbookRelsPath: `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId7" Target="/xl/worksheets/people.xml"/></Relationships>`,
+		xlsxSharedStrings: `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<si><t>name</t></si><si><r><t>Ali</t></r><r><t>ce</t></r></si></sst>`,
+		"xl/worksheets/people.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="str"><f>UPPER(A1)</f><v>NAME</v></c></row>` +
+			`<row r="3"><c r="A3" t="s"><v>1</v></c><c r="B3"><v>1.5</v></c><c r="C3" t="b"><v>1</v></c></row>` +
+			`</sheetData></worksheet>`,
+	}
+	for name, content := range parts {
+		part, err := archive.Create(name)
+		assert.Nil(t, err)
+		_, err = part.Write([]byte(content))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, archive.Close())
+
+	values, err := readXLSX(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.Nil(t, err)
+	assert.Equal(t, [][]interface{}{
+		{"'name", "", "=UPPER(A1)"},
+		nil,
+		{"'Alice", 1.5, true},
+	}, values)
+}
+
+func TestWriteXLSX(t *testing.T) {
+	values := [][]interface{}{
+		{"'=not a formula", "", float64(-2.5)},
+		{},
+		{"<tag>", "=SUM(1, 2)", false},
+	}
+
+	var buf bytes.Buffer
+	assert.Nil(t, writeXLSX(&buf, values))
+
+	result, err := readXLSX(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.Nil(t, err)
+	assert.Equal(t, [][]interface{}{
+		{"'=not a formula", "", float64(-2.5)},
+		nil,
+		{"'<tag>", "=SUM(1, 2)", false},
+	}, result)
+}
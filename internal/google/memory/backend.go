@@ -0,0 +1,501 @@
+package memory
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// Backend keeps spreadsheets in memory and implements the subset of the Google Sheets API operations used by
+// the stores, including the formulas and the query language.
+//
+// Spreadsheets are created on their first use, so any spreadsheet ID can be used without creating it first.
+// Each new spreadsheet contains a single "Sheet1" sheet. It is safe for concurrent use.
+type Backend struct {
+	mu           sync.Mutex
+	spreadsheets map[string]*spreadsheet
+	nextID       int
+}
+
+// NewBackend creates an empty in-memory backend.
+func NewBackend() *Backend {
+	return &Backend{spreadsheets: make(map[string]*spreadsheet)}
+}
+
+// Error is an error returned in the same shape as the Google Sheets API errors.
+type Error struct {
+	// Code is the HTTP status code, e.g. 400.
+	Code int
+
+	// Status is the canonical error status, e.g. "INVALID_ARGUMENT".
+	Status string
+
+	// Message is the error message, e.g. "Unable to parse range: Sheet2!A1".
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.Code, e.Status, e.Message)
+}
+
+func errInvalidRange(value string) error {
+	return errInvalidArgument("Unable to parse range: %s", value)
+}
+
+func errInvalidArgument(format string, args ...interface{}) error {
+	return &Error{Code: http.StatusBadRequest, Status: "INVALID_ARGUMENT", Message: fmt.Sprintf(format, args...)}
+}
+
+// SheetProperties describes a sheet inside a spreadsheet.
+type SheetProperties struct {
+	ID          int64
+	Title       string
+	Index       int64
+	RowCount    int64
+	ColumnCount int64
+}
+
+// SpreadsheetProperties describes a spreadsheet and its sheets.
+type SpreadsheetProperties struct {
+	ID     string
+	Title  string
+	Sheets []SheetProperties
+}
+
+// SheetRequest is either a request to add a sheet or to delete a sheet.
+type SheetRequest struct {
+	// AddSheet adds a sheet with the given title and grid size. A zero grid size uses the default size.
+	AddSheet *SheetProperties
+
+	// DeleteSheetID deletes the sheet with the given ID.
+	DeleteSheetID *int64
+}
+
+// UpdateResult describes the cells written by an append or update operation.
+type UpdateResult struct {
+	Range   string
+	Rows    int64
+	Columns int64
+	Cells   int64
+	// Values contains the written values rendered based on the requested render option.
+	Values [][]interface{}
+}
+
+// ValueRange is a list of values to be written into the given A1 notation range.
+type ValueRange struct {
+	Range  string
+	Values [][]interface{}
+}
+
+// CreateSpreadsheet creates a new spreadsheet with a generated ID.
+func (b *Backend) CreateSpreadsheet(title string) SpreadsheetProperties {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	id := "memory-spreadsheet-" + strconv.Itoa(b.nextID)
+
+	ss := newSpreadsheet(id, title)
+	b.spreadsheets[id] = ss
+	return ss.properties()
+}
+
+// Spreadsheet returns the properties of the spreadsheet.
+func (b *Backend) Spreadsheet(spreadsheetID string) SpreadsheetProperties {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.getSpreadsheet(spreadsheetID).properties()
+}
+
+// BatchUpdateSheets adds and deletes sheets atomically, i.e. nothing is changed if any request fails.
+// It returns the properties of the added sheets, in the same order as the requests.
+// The deleted sheets have an empty SheetProperties in the result.
+func (b *Backend) BatchUpdateSheets(spreadsheetID string, requests []SheetRequest) ([]SheetProperties, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ss := b.getSpreadsheet(spreadsheetID)
+
+	titles := make(map[string]bool)
+	for _, sh := range ss.sheets {
+		titles[sh.title] = true
+	}
+	for _, request := range requests {
+		switch {
+		case request.AddSheet != nil:
+			title := request.AddSheet.Title
+			if title == "" {
+				return nil, errInvalidArgument("Invalid requests.addSheet: the sheet title must not be empty")
+			}
+			if titles[title] {
+				return nil, errInvalidArgument(
+					"Invalid requests.addSheet: A sheet with the name \"%s\" already exists. Please enter another name.",
+					title,
+				)
+			}
+			titles[title] = true
+		case request.DeleteSheetID != nil:
+			if !ss.hasSheet(*request.DeleteSheetID) {
+				return nil, errInvalidArgument("Invalid requests.deleteSheet: No grid with id: %d", *request.DeleteSheetID)
+			}
+		default:
+			return nil, errInvalidArgument("Unsupported request, only addSheet and deleteSheet are supported")
+		}
+	}
+
+	result := make([]SheetProperties, len(requests))
+	for i, request := range requests {
+		if request.DeleteSheetID != nil {
+			ss.deleteSheet(*request.DeleteSheetID)
+			continue
+		}
+
+		props := request.AddSheet
+		sh := ss.addSheet(props.Title, int(props.RowCount), int(props.ColumnCount))
+		result[i] = sh.properties(len(ss.sheets) - 1)
+	}
+	return result, nil
+}
+
+// Append appends the values after the last row containing any value within the range columns.
+// If insertRows is true, new rows are inserted for the values instead of overwriting the rows below.
+func (b *Backend) Append(
+	spreadsheetID string,
+	a1Range string,
+	values [][]interface{},
+	inputOption string,
+	insertRows bool,
+	renderOption string,
+) (UpdateResult, error) {
+	if err := validateInputOption(inputOption); err != nil {
+		return UpdateResult{}, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	rng, err := parseA1Range(a1Range)
+	if err != nil {
+		return UpdateResult{}, err
+	}
+	ss := b.getSpreadsheet(spreadsheetID)
+	sh, rng, err := ss.resolve(rng)
+	if err != nil {
+		return UpdateResult{}, err
+	}
+
+	target := sh.lastRow(rng.startCol, rng.endCol) + 1
+	if target < rng.startRow {
+		target = rng.startRow
+	}
+	if insertRows {
+		sh.insertRows(target, len(values))
+	}
+
+	written := ss.writeValues(sh, target, rng.startCol, values, inputOption)
+	return ss.updateResult(sh, written, renderOption), nil
+}
+
+// Update writes the values into the range. Just like the real API, the values must fit into the range.
+func (b *Backend) Update(
+	spreadsheetID string,
+	a1Range string,
+	values [][]interface{},
+	inputOption string,
+	renderOption string,
+) (UpdateResult, error) {
+	results, err := b.BatchUpdate(spreadsheetID, []ValueRange{{Range: a1Range, Values: values}}, inputOption, renderOption)
+	if err != nil {
+		return UpdateResult{}, err
+	}
+	return results[0], nil
+}
+
+// BatchUpdate writes the values into multiple ranges. Nothing is written if any range is invalid.
+func (b *Backend) BatchUpdate(
+	spreadsheetID string,
+	data []ValueRange,
+	inputOption string,
+	renderOption string,
+) ([]UpdateResult, error) {
+	if err := validateInputOption(inputOption); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ss := b.getSpreadsheet(spreadsheetID)
+	sheets := make([]*sheet, len(data))
+	ranges := make([]gridRange, len(data))
+	for i, d := range data {
+		rng, err := parseA1Range(d.Range)
+		if err != nil {
+			return nil, err
+		}
+		if sheets[i], ranges[i], err = ss.resolve(rng); err != nil {
+			return nil, err
+		}
+
+		for j, row := range d.Values {
+			if ranges[i].startRow+j > ranges[i].endRow || ranges[i].startCol+len(row)-1 > ranges[i].endCol {
+				return nil, errInvalidArgument(
+					"Requested writing within range [%s], but tried writing to row [%d] with %d values",
+					d.Range, ranges[i].startRow+j+1, len(row),
+				)
+			}
+		}
+	}
+
+	results := make([]UpdateResult, len(data))
+	for i, d := range data {
+		written := ss.writeValues(sheets[i], ranges[i].startRow, ranges[i].startCol, d.Values, inputOption)
+		results[i] = ss.updateResult(sheets[i], written, renderOption)
+	}
+	return results, nil
+}
+
+// Clear clears the values of the ranges and returns the cleared ranges.
+func (b *Backend) Clear(spreadsheetID string, ranges []string) ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ss := b.getSpreadsheet(spreadsheetID)
+	sheets := make([]*sheet, len(ranges))
+	resolved := make([]gridRange, len(ranges))
+	for i, a1Range := range ranges {
+		rng, err := parseA1Range(a1Range)
+		if err != nil {
+			return nil, err
+		}
+		if sheets[i], resolved[i], err = ss.resolve(rng); err != nil {
+			return nil, err
+		}
+	}
+
+	cleared := make([]string, len(ranges))
+	for i := range ranges {
+		sheets[i].clear(resolved[i])
+		cleared[i] = formatA1Range(sheets[i].title, resolved[i])
+	}
+	return cleared, nil
+}
+
+// Get returns the resolved range and its rendered values, without the trailing empty rows and columns.
+func (b *Backend) Get(spreadsheetID string, a1Range string, renderOption string) (string, [][]interface{}, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	rng, err := parseA1Range(a1Range)
+	if err != nil {
+		return "", nil, err
+	}
+	ss := b.getSpreadsheet(spreadsheetID)
+	sh, rng, err := ss.resolve(rng)
+	if err != nil {
+		return "", nil, err
+	}
+	return formatA1Range(sh.title, rng), ss.readValues(sh, rng, renderOption), nil
+}
+
+// Values returns the computed values of the given A1 notation range, e.g. "Sheet1!A1:C10".
+// Formulas are evaluated and the values are not formatted, just like UNFORMATTED_VALUE in the API.
+// It is mainly useful for assertions in tests.
+func (b *Backend) Values(spreadsheetID string, a1Range string) ([][]interface{}, error) {
+	_, values, err := b.Get(spreadsheetID, a1Range, RenderUnformatted)
+	return values, err
+}
+
+func (b *Backend) getSpreadsheet(id string) *spreadsheet {
+	ss, ok := b.spreadsheets[id]
+	if !ok {
+		ss = newSpreadsheet(id, id)
+		b.spreadsheets[id] = ss
+	}
+	return ss
+}
+
+func validateInputOption(option string) error {
+	switch option {
+	case InputUserEntered, InputRaw:
+		return nil
+	case "":
+		return errInvalidArgument("'valueInputOption' is required but not specified")
+	default:
+		return errInvalidArgument("Invalid value at 'value_input_option': %s", option)
+	}
+}
+
+// writeValues writes the values starting from the given cell and returns the written range.
+func (ss *spreadsheet) writeValues(sh *sheet, startRow int, startCol int, values [][]interface{}, option string) gridRange {
+	written := gridRange{startRow: startRow, startCol: startCol, endRow: startRow - 1, endCol: startCol - 1}
+	for i, row := range values {
+		for j, value := range row {
+			c, ok := parseInput(value, option)
+			if !ok {
+				continue
+			}
+			sh.set(startRow+i, startCol+j, c)
+		}
+		written.endRow = startRow + i
+		if end := startCol + len(row) - 1; end > written.endCol {
+			written.endCol = end
+		}
+	}
+	return written
+}
+
+func (ss *spreadsheet) updateResult(sh *sheet, written gridRange, renderOption string) UpdateResult {
+	rows := written.endRow - written.startRow + 1
+	cols := written.endCol - written.startCol + 1
+	if rows <= 0 || cols <= 0 {
+		return UpdateResult{Range: quoteSheetName(sh.title)}
+	}
+
+	return UpdateResult{
+		Range:   formatA1Range(sh.title, written),
+		Rows:    int64(rows),
+		Columns: int64(cols),
+		Cells:   int64(rows * cols),
+		Values:  ss.readValues(sh, written, renderOption),
+	}
+}
+
+// readValues returns the rendered values of the range, without the trailing empty rows and columns.
+func (ss *spreadsheet) readValues(sh *sheet, rng gridRange, renderOption string) [][]interface{} {
+	endRow := rng.endRow
+	if last := sh.lastRow(rng.startCol, rng.endCol); last < endRow {
+		endRow = last
+	}
+
+	values := make([][]interface{}, 0)
+	for row := rng.startRow; row <= endRow; row++ {
+		rowValues := make([]interface{}, 0)
+		for col := rng.startCol; col <= rng.endCol && col < len(sh.rows[row]); col++ {
+			value := render(sh.get(row, col), ss.evaluateCell(sh, row, col), renderOption)
+			if value == nil {
+				value = ""
+			}
+			rowValues = append(rowValues, value)
+		}
+		values = append(values, rowValues)
+	}
+	return trimValues(values)
+}
+
+func (ss *spreadsheet) hasSheet(id int64) bool {
+	for _, sh := range ss.sheets {
+		if sh.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (ss *spreadsheet) properties() SpreadsheetProperties {
+	result := SpreadsheetProperties{ID: ss.id, Title: ss.title}
+	for i, sh := range ss.sheets {
+		result.Sheets = append(result.Sheets, sh.properties(i))
+	}
+	return result
+}
+
+func (sh *sheet) properties(index int) SheetProperties {
+	return SheetProperties{
+		ID:          sh.id,
+		Title:       sh.title,
+		Index:       int64(index),
+		RowCount:    int64(sh.rowCount),
+		ColumnCount: int64(sh.columnCount),
+	}
+}
+
+func (sh *sheet) insertRows(at int, count int) {
+	if at >= len(sh.rows) || count <= 0 {
+		return
+	}
+	sh.rows = append(sh.rows[:at], append(make([][]cell, count), sh.rows[at:]...)...)
+	sh.rowCount += count
+}
+
+func (sh *sheet) clear(rng gridRange) {
+	for row := rng.startRow; row <= rng.endRow && row < len(sh.rows); row++ {
+		for col := rng.startCol; col <= rng.endCol && col < len(sh.rows[row]); col++ {
+			sh.rows[row][col] = cell{}
+		}
+	}
+}
+
+// SheetSnapshot holds the content of a sheet as user entered values.
+//
+// Formulas start with "=", while the strings that would be parsed into a different value when entered
+// (e.g. "10" or "=A1") are prefixed with an apostrophe, just like in the Google Sheets UI.
+type SheetSnapshot struct {
+	Title  string
+	Values [][]interface{}
+}
+
+// Snapshot returns the content of every sheet in the spreadsheet, in the same order as the sheets.
+// The values are either a string, a float64 or a bool. Empty cells are returned as an empty string.
+func (b *Backend) Snapshot(spreadsheetID string) []SheetSnapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ss := b.getSpreadsheet(spreadsheetID)
+	result := make([]SheetSnapshot, len(ss.sheets))
+	for i, sh := range ss.sheets {
+		values := make([][]interface{}, len(sh.rows))
+		for row, cells := range sh.rows {
+			values[row] = make([]interface{}, len(cells))
+			for col, c := range cells {
+				values[row][col] = userEnteredValue(c)
+			}
+		}
+		result[i] = SheetSnapshot{Title: sh.title, Values: trimValues(values)}
+	}
+	return result
+}
+
+// Restore replaces the spreadsheet content with the given sheets, e.g. ones returned by Snapshot.
+// The spreadsheet gets the default "Sheet1" sheet if no sheet is given.
+func (b *Backend) Restore(spreadsheetID string, sheets []SheetSnapshot) error {
+	ss := &spreadsheet{id: spreadsheetID, title: spreadsheetID}
+	if len(sheets) == 0 {
+		ss.addSheet(defaultSheetTitle, 0, 0)
+	}
+
+	for _, snapshot := range sheets {
+		if ss.sheetByTitle(snapshot.Title) != nil {
+			return errInvalidArgument("A sheet with the name %q already exists. Please enter another name.", snapshot.Title)
+		}
+		sh := ss.addSheet(snapshot.Title, len(snapshot.Values), 0)
+		ss.writeValues(sh, 0, 0, snapshot.Values, InputUserEntered)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.spreadsheets[spreadsheetID] = ss
+	return nil
+}
+
+// userEnteredValue converts a cell into the value that results in the same cell when it is entered.
+func userEnteredValue(c cell) interface{} {
+	if c.formula != "" {
+		return c.formula
+	}
+
+	switch converted := c.value.(type) {
+	case nil:
+		return ""
+	case string:
+		if parsed := parseUserEntered(converted); parsed.formula != "" || parsed.value != converted {
+			return "'" + converted
+		}
+		return converted
+	default:
+		return converted
+	}
+}
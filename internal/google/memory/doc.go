@@ -0,0 +1,6 @@
+// Package memory implements an in-memory spreadsheet backend emulating the subset of Google Sheets
+// used by the stores: the values API, the formulas used by the stores and the Google Visualization query language.
+//
+// Backend holds the spreadsheets and is safe for concurrent use. Wrapper exposes a Backend through the same methods
+// as sheets.Wrapper, so that the stores can run against it without any network access.
+package memory
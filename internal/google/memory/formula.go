@@ -0,0 +1,760 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxFormulaDepth limits how deep formulas referring to other formula cells are evaluated.
+// It prevents infinite recursion for circular references.
+const maxFormulaDepth = 32
+
+// formulaError is an error value produced by a formula, e.g. "#N/A".
+type formulaError string
+
+// rangeValue is a lazily evaluated cell range referred by a formula.
+type rangeValue struct {
+	sheet *sheet
+	rng   gridRange
+}
+
+// evaluateCell returns the computed value of the cell, evaluating its formula if there is one.
+// Formula errors are returned as strings (e.g. "#N/A"), just like the Google Sheets API does.
+func (s *spreadsheet) evaluateCell(sh *sheet, row int, col int) interface{} {
+	return toCellValue(s.evaluateCellDepth(sh, row, col, 0))
+}
+
+func (s *spreadsheet) evaluateCellDepth(sh *sheet, row int, col int, depth int) interface{} {
+	c := sh.get(row, col)
+	if c.formula == "" {
+		return c.value
+	}
+	if depth > maxFormulaDepth {
+		return formulaError(errorRef)
+	}
+
+	e := &formulaEvaluator{spreadsheet: s, sheet: sh, row: row, col: col, depth: depth}
+	result, err := e.evaluate(strings.TrimPrefix(c.formula, "="))
+	if err != nil {
+		return formulaError(errorName)
+	}
+	// A range result would spill into the neighbouring cells, only its first value is kept.
+	return e.scalar(result)
+}
+
+func toCellValue(value interface{}) interface{} {
+	switch converted := value.(type) {
+	case formulaError:
+		return string(converted)
+	default:
+		return value
+	}
+}
+
+type formulaEvaluator struct {
+	spreadsheet *spreadsheet
+	sheet       *sheet
+	row         int
+	col         int
+	depth       int
+
+	tokens []formulaToken
+	pos    int
+}
+
+type formulaTokenKind int
+
+const (
+	formulaTokenEOF formulaTokenKind = iota
+	formulaTokenNumber
+	formulaTokenString
+	formulaTokenIdent
+	formulaTokenOp
+)
+
+type formulaToken struct {
+	kind  formulaTokenKind
+	value string
+}
+
+func (e *formulaEvaluator) evaluate(formula string) (interface{}, error) {
+	tokens, err := tokenizeFormula(formula)
+	if err != nil {
+		return nil, err
+	}
+	e.tokens = tokens
+	e.pos = 0
+
+	result, err := e.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	if e.peek().kind != formulaTokenEOF {
+		return nil, fmt.Errorf("unexpected token: %s", e.peek().value)
+	}
+	return result, nil
+}
+
+func tokenizeFormula(formula string) ([]formulaToken, error) {
+	var tokens []formulaToken
+	runes := []rune(formula)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"':
+			var b strings.Builder
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("unterminated string")
+				}
+				if runes[i] == '"' {
+					if i+1 < len(runes) && runes[i+1] == '"' {
+						b.WriteRune('"')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				b.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, formulaToken{kind: formulaTokenString, value: b.String()})
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, formulaToken{kind: formulaTokenNumber, value: string(runes[start:i])})
+		case r == '\'':
+			// A quoted sheet name, e.g. 'My Sheet'!A1:B2.
+			start := i
+			i++
+			for i < len(runes) && !(runes[i] == '\'' && (i+1 >= len(runes) || runes[i+1] != '\'')) {
+				if runes[i] == '\'' {
+					i++
+				}
+				i++
+			}
+			i++
+			for i < len(runes) && isFormulaIdentRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, formulaToken{kind: formulaTokenIdent, value: string(runes[start:min(i, len(runes))])})
+		case unicode.IsLetter(r) || r == '_' || r == '$':
+			start := i
+			for i < len(runes) && isFormulaIdentRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, formulaToken{kind: formulaTokenIdent, value: string(runes[start:i])})
+		default:
+			op := string(r)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "<=", ">=", "<>":
+					op = two
+				}
+			}
+			if !strings.Contains("+-*/&=<>(),;", string(r)) {
+				return nil, fmt.Errorf("unexpected character: %c", r)
+			}
+			tokens = append(tokens, formulaToken{kind: formulaTokenOp, value: op})
+			i += len(op)
+		}
+	}
+	return append(tokens, formulaToken{kind: formulaTokenEOF}), nil
+}
+
+func isFormulaIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.$!:", r)
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func (e *formulaEvaluator) peek() formulaToken {
+	return e.tokens[e.pos]
+}
+
+func (e *formulaEvaluator) next() formulaToken {
+	t := e.tokens[e.pos]
+	if t.kind != formulaTokenEOF {
+		e.pos++
+	}
+	return t
+}
+
+func (e *formulaEvaluator) acceptOp(ops ...string) (string, bool) {
+	t := e.peek()
+	if t.kind != formulaTokenOp {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.value == op {
+			e.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (e *formulaEvaluator) parseComparison() (interface{}, error) {
+	left, err := e.parseConcat()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := e.acceptOp("=", "<>", "<", "<=", ">", ">=")
+		if !ok {
+			return left, nil
+		}
+		right, err := e.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		left = compareFormulaValues(op, e.scalar(left), e.scalar(right))
+	}
+}
+
+func (e *formulaEvaluator) parseConcat() (interface{}, error) {
+	left, err := e.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := e.acceptOp("&"); !ok {
+			return left, nil
+		}
+		right, err := e.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+
+		l, r := e.scalar(left), e.scalar(right)
+		if fe, ok := firstError(l, r); ok {
+			left = fe
+			continue
+		}
+		left = toFormulaString(l) + toFormulaString(r)
+	}
+}
+
+func (e *formulaEvaluator) parseAdditive() (interface{}, error) {
+	left, err := e.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := e.acceptOp("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := e.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = arithmetic(op, e.scalar(left), e.scalar(right))
+	}
+}
+
+func (e *formulaEvaluator) parseMultiplicative() (interface{}, error) {
+	left, err := e.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := e.acceptOp("*", "/")
+		if !ok {
+			return left, nil
+		}
+		right, err := e.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = arithmetic(op, e.scalar(left), e.scalar(right))
+	}
+}
+
+func (e *formulaEvaluator) parseUnary() (interface{}, error) {
+	if op, ok := e.acceptOp("-", "+"); ok {
+		operand, err := e.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if op == "+" {
+			return e.scalar(operand), nil
+		}
+		return arithmetic("-", float64(0), e.scalar(operand)), nil
+	}
+	return e.parsePrimary()
+}
+
+func (e *formulaEvaluator) parsePrimary() (interface{}, error) {
+	t := e.next()
+	switch t.kind {
+	case formulaTokenNumber:
+		return strconv.ParseFloat(t.value, 64)
+	case formulaTokenString:
+		return t.value, nil
+	case formulaTokenOp:
+		if t.value != "(" {
+			return nil, fmt.Errorf("unexpected token: %s", t.value)
+		}
+		result, err := e.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := e.acceptOp(")"); !ok {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return result, nil
+	case formulaTokenIdent:
+		if _, ok := e.acceptOp("("); ok {
+			args, err := e.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+			return e.call(strings.ToUpper(t.value), args), nil
+		}
+		switch strings.ToUpper(t.value) {
+		case "TRUE":
+			return true, nil
+		case "FALSE":
+			return false, nil
+		}
+		return e.reference(t.value), nil
+	default:
+		return nil, fmt.Errorf("unexpected end of formula")
+	}
+}
+
+func (e *formulaEvaluator) parseArgs() ([]interface{}, error) {
+	var args []interface{}
+	if _, ok := e.acceptOp(")"); ok {
+		return args, nil
+	}
+	for {
+		arg, err := e.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		if _, ok := e.acceptOp(",", ";"); ok {
+			continue
+		}
+		if _, ok := e.acceptOp(")"); ok {
+			return args, nil
+		}
+		return nil, fmt.Errorf("missing closing parenthesis")
+	}
+}
+
+func (e *formulaEvaluator) reference(ref string) interface{} {
+	rng, err := parseA1Range(ref)
+	if err != nil || rng.sheetName != "" && !strings.Contains(ref, "!") {
+		return formulaError(errorName)
+	}
+
+	sh := e.sheet
+	if rng.sheetName != "" {
+		if sh = e.spreadsheet.sheetByTitle(rng.sheetName); sh == nil {
+			return formulaError(errorRef)
+		}
+	}
+	if rng.endRow < 0 {
+		rng.endRow = sh.rowCount - 1
+	}
+	if rng.endCol < 0 {
+		rng.endCol = sh.columnCount - 1
+	}
+	return rangeValue{sheet: sh, rng: rng}
+}
+
+// scalar converts a range into its first value.
+func (e *formulaEvaluator) scalar(value interface{}) interface{} {
+	switch converted := value.(type) {
+	case rangeValue:
+		return e.spreadsheet.evaluateCellDepth(converted.sheet, converted.rng.startRow, converted.rng.startCol, e.depth+1)
+	case [][]interface{}:
+		if len(converted) == 0 || len(converted[0]) == 0 {
+			return nil
+		}
+		return converted[0][0]
+	default:
+		return value
+	}
+}
+
+// array converts a range into its values. Trailing empty rows are not included.
+func (e *formulaEvaluator) array(value interface{}) ([][]interface{}, bool) {
+	switch converted := value.(type) {
+	case rangeValue:
+		endRow := converted.rng.endRow
+		if last := converted.sheet.lastRow(converted.rng.startCol, converted.rng.endCol); last < endRow {
+			endRow = last
+		}
+
+		result := make([][]interface{}, 0)
+		for row := converted.rng.startRow; row <= endRow; row++ {
+			values := make([]interface{}, 0, converted.rng.endCol-converted.rng.startCol+1)
+			for col := converted.rng.startCol; col <= converted.rng.endCol; col++ {
+				values = append(values, e.spreadsheet.evaluateCellDepth(converted.sheet, row, col, e.depth+1))
+			}
+			result = append(result, values)
+		}
+		return result, true
+	case [][]interface{}:
+		return converted, true
+	default:
+		return nil, false
+	}
+}
+
+func (e *formulaEvaluator) call(name string, args []interface{}) interface{} {
+	switch name {
+	case "ROW", "COLUMN":
+		if len(args) == 0 {
+			if name == "ROW" {
+				return float64(e.row + 1)
+			}
+			return float64(e.col + 1)
+		}
+		r, ok := args[0].(rangeValue)
+		if !ok {
+			return formulaError(errorVal)
+		}
+		if name == "ROW" {
+			return float64(r.rng.startRow + 1)
+		}
+		return float64(r.rng.startCol + 1)
+	case "VLOOKUP":
+		return e.vlookup(args)
+	case "MATCH":
+		return e.match(args)
+	case "SORT":
+		return e.sort(args)
+	case "SUM":
+		return e.sum(args)
+	case "UPPER", "LOWER", "LEN":
+		if len(args) != 1 {
+			return formulaError(errorVal)
+		}
+		value := e.scalar(args[0])
+		if fe, ok := value.(formulaError); ok {
+			return fe
+		}
+		s := toFormulaString(value)
+		switch name {
+		case "UPPER":
+			return strings.ToUpper(s)
+		case "LOWER":
+			return strings.ToLower(s)
+		default:
+			return float64(len([]rune(s)))
+		}
+	default:
+		return formulaError(errorName)
+	}
+}
+
+func (e *formulaEvaluator) vlookup(args []interface{}) interface{} {
+	if len(args) < 3 || len(args) > 4 {
+		return formulaError(errorVal)
+	}
+
+	key := e.scalar(args[0])
+	table, ok := e.array(args[1])
+	if !ok {
+		return formulaError(errorVal)
+	}
+	index, ok := toFormulaNumber(e.scalar(args[2]))
+	if !ok || index < 1 {
+		return formulaError(errorVal)
+	}
+
+	for _, row := range table {
+		if len(row) == 0 || !lookupEqual(key, row[0]) {
+			continue
+		}
+		if int(index) > len(row) {
+			return formulaError(errorRef)
+		}
+		return row[int(index)-1]
+	}
+	return formulaError(errorNA)
+}
+
+func (e *formulaEvaluator) match(args []interface{}) interface{} {
+	if len(args) < 2 || len(args) > 3 {
+		return formulaError(errorVal)
+	}
+
+	key := e.scalar(args[0])
+	values, ok := e.array(args[1])
+	if !ok {
+		return formulaError(errorVal)
+	}
+
+	for i, row := range values {
+		if len(row) > 0 && lookupEqual(key, row[0]) {
+			return float64(i + 1)
+		}
+	}
+	return formulaError(errorNA)
+}
+
+func (e *formulaEvaluator) sort(args []interface{}) interface{} {
+	if len(args) == 0 {
+		return formulaError(errorVal)
+	}
+	values, ok := e.array(args[0])
+	if !ok {
+		return formulaError(errorVal)
+	}
+	if len(args)%2 != 1 {
+		return formulaError(errorVal)
+	}
+
+	type sortKey struct {
+		col int
+		asc bool
+	}
+	keys := make([]sortKey, 0, len(args)/2)
+	for i := 1; i < len(args); i += 2 {
+		col, ok := toFormulaNumber(e.scalar(args[i]))
+		if !ok || col < 1 {
+			return formulaError(errorVal)
+		}
+		asc, ok := e.scalar(args[i+1]).(bool)
+		if !ok {
+			return formulaError(errorVal)
+		}
+		keys = append(keys, sortKey{col: int(col) - 1, asc: asc})
+	}
+
+	result := make([][]interface{}, len(values))
+	copy(result, values)
+	sort.SliceStable(result, func(i, j int) bool {
+		for _, k := range keys {
+			a, b := valueAt(result[i], k.col), valueAt(result[j], k.col)
+			// Empty values are always sorted last.
+			if a == nil || b == nil {
+				if a == nil && b == nil {
+					continue
+				}
+				return b == nil
+			}
+			c := compareValues(a, b)
+			if c == 0 {
+				continue
+			}
+			if k.asc {
+				return c < 0
+			}
+			return c > 0
+		}
+		return false
+	})
+	return result
+}
+
+func (e *formulaEvaluator) sum(args []interface{}) interface{} {
+	total := float64(0)
+	for _, arg := range args {
+		values, ok := e.array(arg)
+		if !ok {
+			values = [][]interface{}{{e.scalar(arg)}}
+		}
+		for _, row := range values {
+			for _, v := range row {
+				switch converted := v.(type) {
+				case formulaError:
+					return converted
+				case float64:
+					total += converted
+				}
+			}
+		}
+	}
+	return total
+}
+
+func valueAt(row []interface{}, idx int) interface{} {
+	if idx < len(row) {
+		return row[idx]
+	}
+	return nil
+}
+
+// lookupEqual compares values like VLOOKUP and MATCH do, i.e. strings are compared case-insensitively.
+func lookupEqual(key interface{}, value interface{}) bool {
+	switch k := key.(type) {
+	case string:
+		v, ok := value.(string)
+		return ok && strings.EqualFold(k, v)
+	case float64, bool:
+		return key == value
+	default:
+		return false
+	}
+}
+
+func firstError(values ...interface{}) (formulaError, bool) {
+	for _, v := range values {
+		if fe, ok := v.(formulaError); ok {
+			return fe, true
+		}
+	}
+	return "", false
+}
+
+func arithmetic(op string, left interface{}, right interface{}) interface{} {
+	if fe, ok := firstError(left, right); ok {
+		return fe
+	}
+
+	l, ok := toFormulaNumber(left)
+	if !ok {
+		return formulaError(errorVal)
+	}
+	r, ok := toFormulaNumber(right)
+	if !ok {
+		return formulaError(errorVal)
+	}
+
+	switch op {
+	case "+":
+		return l + r
+	case "-":
+		return l - r
+	case "*":
+		return l * r
+	default:
+		if r == 0 {
+			return formulaError(errorDiv0)
+		}
+		return l / r
+	}
+}
+
+func compareFormulaValues(op string, left interface{}, right interface{}) interface{} {
+	if fe, ok := firstError(left, right); ok {
+		return fe
+	}
+
+	var c int
+	if ls, ok := left.(string); ok {
+		rs, ok := right.(string)
+		if !ok {
+			return op == "<>"
+		}
+		c = strings.Compare(strings.ToLower(ls), strings.ToLower(rs))
+	} else {
+		c = compareValues(left, right)
+	}
+
+	switch op {
+	case "=":
+		return c == 0
+	case "<>":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	default:
+		return c >= 0
+	}
+}
+
+func toFormulaNumber(value interface{}) (float64, bool) {
+	switch converted := value.(type) {
+	case nil:
+		return 0, true
+	case float64:
+		return converted, true
+	case bool:
+		if converted {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(converted), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func toFormulaString(value interface{}) string {
+	switch converted := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return converted
+	default:
+		return fmt.Sprint(formatValue(value))
+	}
+}
+
+// compareValues orders values of possibly different types: nil < numbers < strings < booleans.
+func compareValues(a interface{}, b interface{}) int {
+	ra, rb := typeRank(a), typeRank(b)
+	if ra != rb {
+		return ra - rb
+	}
+
+	switch av := a.(type) {
+	case float64:
+		bv := b.(float64)
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		}
+		return 0
+	case string:
+		return strings.Compare(av, b.(string))
+	case bool:
+		bv := b.(bool)
+		switch {
+		case av == bv:
+			return 0
+		case !av:
+			return -1
+		}
+		return 1
+	default:
+		return 0
+	}
+}
+
+func typeRank(v interface{}) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case float64:
+		return 1
+	case string, formulaError:
+		return 2
+	case bool:
+		return 3
+	default:
+		return 4
+	}
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateCell(t *testing.T) {
+	ss := newSpreadsheet("id", "title")
+	sh := ss.sheets[0]
+	kv := ss.addSheet("kv", 0, 3)
+
+	kv.set(0, 0, cell{value: "k1"})
+	kv.set(0, 1, cell{value: "old"})
+	kv.set(0, 2, cell{value: float64(1)})
+	kv.set(1, 0, cell{value: "k2"})
+	kv.set(1, 1, cell{value: "other"})
+	kv.set(1, 2, cell{value: float64(2)})
+	kv.set(2, 0, cell{value: "k1"})
+	kv.set(2, 1, cell{value: "new"})
+	kv.set(2, 2, cell{value: float64(3)})
+
+	tests := []struct {
+		formula  string
+		expected interface{}
+	}{
+		{formula: "=ROW()", expected: float64(5)},
+		{formula: "=ROW(kv!A3)*2+1", expected: float64(7)},
+		{formula: "=(1+2)*3-4/2", expected: float64(7)},
+		{formula: "=1/0", expected: errorDiv0},
+		{formula: `="a"&"b"`, expected: "ab"},
+		{formula: `=VLOOKUP("K1", kv!A1:C5000000, 2, FALSE)`, expected: "old"},
+		{formula: `=VLOOKUP("k1", SORT(kv!A1:C5000000, 3, FALSE), 2, FALSE)`, expected: "new"},
+		{formula: `=VLOOKUP("k3", kv!A1:C5000000, 2, FALSE)`, expected: errorNA},
+		{formula: `=MATCH("k2", kv!A1:A5000000, 0)`, expected: float64(2)},
+		{formula: `=MATCH("k3", kv!A1:A5000000, 0)`, expected: errorNA},
+		{formula: `=SUM(kv!C1:C3)`, expected: float64(6)},
+		{formula: `=UNKNOWN(1)`, expected: errorName},
+		{formula: `=unknown!A1`, expected: errorRef},
+		{formula: `=A5`, expected: errorRef},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.formula, func(t *testing.T) {
+			sh.set(4, 0, cell{formula: tc.formula})
+			assert.Equal(t, tc.expected, ss.evaluateCell(sh, 4, 0))
+		})
+	}
+}
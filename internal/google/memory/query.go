@@ -0,0 +1,575 @@
+package memory
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// query is a parsed Google Visualization API query.
+// See https://developers.google.com/chart/interactive/docs/querylanguage for the full language reference.
+type query struct {
+	// selects is nil when all columns are selected.
+	selects  []queryExpr
+	where    queryExpr
+	groupBy  []queryExpr
+	orderBy  []queryOrder
+	limit    int
+	offset   int
+	labels   map[string]string
+	formats  map[string]string
+	noFormat bool
+	noValues bool
+}
+
+type queryOrder struct {
+	expr queryExpr
+	desc bool
+}
+
+// queryExpr is a node of a query expression.
+// String returns the canonical form of the expression, which is also used as the result column ID.
+type queryExpr interface {
+	String() string
+}
+
+type columnExpr struct {
+	name string
+}
+
+type literalExpr struct {
+	value interface{}
+}
+
+type unaryExpr struct {
+	op      string
+	operand queryExpr
+}
+
+type binaryExpr struct {
+	op    string
+	left  queryExpr
+	right queryExpr
+}
+
+type isNullExpr struct {
+	operand queryExpr
+	not     bool
+}
+
+type funcExpr struct {
+	name string
+	args []queryExpr
+}
+
+func (e columnExpr) String() string {
+	return e.name
+}
+
+func (e literalExpr) String() string {
+	switch v := e.value.(type) {
+	case string:
+		return "'" + v + "'"
+	case float64:
+		return formatNumber(v)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (e unaryExpr) String() string {
+	if e.op == "-" {
+		return "-" + e.operand.String()
+	}
+	return e.op + " " + e.operand.String()
+}
+
+func (e binaryExpr) String() string {
+	return e.left.String() + " " + e.op + " " + e.right.String()
+}
+
+func (e isNullExpr) String() string {
+	if e.not {
+		return e.operand.String() + " is not null"
+	}
+	return e.operand.String() + " is null"
+}
+
+func (e funcExpr) String() string {
+	args := make([]string, len(e.args))
+	for i, arg := range e.args {
+		args[i] = arg.String()
+	}
+	return e.name + "(" + strings.Join(args, ", ") + ")"
+}
+
+var (
+	aggregateFunctions = map[string]bool{"count": true, "sum": true, "avg": true, "min": true, "max": true}
+	scalarFunctions    = map[string]bool{"upper": true, "lower": true}
+)
+
+type queryTokenKind int
+
+const (
+	queryTokenEOF queryTokenKind = iota
+	queryTokenIdent
+	queryTokenColumn
+	queryTokenNumber
+	queryTokenString
+	queryTokenOp
+)
+
+type queryToken struct {
+	kind  queryTokenKind
+	value string
+}
+
+// is reports whether the token is the given keyword or operator. Keywords are case-insensitive.
+func (t queryToken) is(value string) bool {
+	return (t.kind == queryTokenIdent || t.kind == queryTokenOp) && strings.EqualFold(t.value, value)
+}
+
+func tokenizeQuery(input string) ([]queryToken, error) {
+	var tokens []queryToken
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"' || r == '`':
+			// The query language has no escape sequences, a string simply ends at the next matching quote.
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			kind := queryTokenString
+			if r == '`' {
+				kind = queryTokenColumn
+			}
+			tokens = append(tokens, queryToken{kind: kind, value: string(runes[i+1 : end])})
+			i = end + 1
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == 'e' || runes[i] == 'E') {
+				i++
+			}
+			tokens = append(tokens, queryToken{kind: queryTokenNumber, value: string(runes[start:i])})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, queryToken{kind: queryTokenIdent, value: string(runes[start:i])})
+		default:
+			op := string(r)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "<=", ">=", "<>", "!=":
+					op = two
+				}
+			}
+			if !strings.Contains("=<>!+-*/%(),", op[:1]) || op == "!" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+			}
+			tokens = append(tokens, queryToken{kind: queryTokenOp, value: op})
+			i += len(op)
+		}
+	}
+	return append(tokens, queryToken{kind: queryTokenEOF}), nil
+}
+
+// clauseOrder maps each clause keyword into its position. LIMIT and OFFSET are accepted in any order.
+var clauseOrder = map[string]int{
+	"select": 0, "where": 1, "group": 2, "pivot": 3, "order": 4,
+	"limit": 5, "offset": 5, "label": 6, "format": 7, "options": 8,
+}
+
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+}
+
+func parseQuery(input string) (*query, error) {
+	tokens, err := tokenizeQuery(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &queryParser{tokens: tokens}
+	q := &query{limit: -1, labels: map[string]string{}, formats: map[string]string{}}
+
+	// Each clause is optional, but the clauses must be in the defined order.
+	last := -1
+	seen := make(map[string]bool)
+	for p.peek().kind != queryTokenEOF {
+		t := p.next()
+		if !isClause(t) {
+			return nil, fmt.Errorf("Encountered \"%s\" at line 1, column %d", t.value, p.pos)
+		}
+		clause := strings.ToLower(t.value)
+		if seen[clause] || clauseOrder[clause] < last {
+			return nil, fmt.Errorf("clause %s is in the wrong position or repeated", strings.ToUpper(clause))
+		}
+		seen[clause] = true
+		last = clauseOrder[clause]
+
+		if err := p.parseClause(q, clause); err != nil {
+			return nil, err
+		}
+	}
+	return q, nil
+}
+
+func isClause(t queryToken) bool {
+	_, ok := clauseOrder[strings.ToLower(t.value)]
+	return t.kind == queryTokenIdent && ok
+}
+
+func (p *queryParser) parseClause(q *query, clause string) (err error) {
+	switch clause {
+	case "select":
+		if _, ok := p.accept("*"); ok {
+			return nil
+		}
+		q.selects, err = p.parseExprList()
+	case "where":
+		q.where, err = p.parseExpr()
+	case "group":
+		if err = p.expect("by"); err != nil {
+			return err
+		}
+		q.groupBy, err = p.parseExprList()
+	case "pivot":
+		return fmt.Errorf("PIVOT is not supported by the emulator")
+	case "order":
+		if err = p.expect("by"); err != nil {
+			return err
+		}
+		for {
+			expr, err := p.parseExpr()
+			if err != nil {
+				return err
+			}
+			order := queryOrder{expr: expr}
+			if _, ok := p.accept("desc"); ok {
+				order.desc = true
+			} else {
+				p.accept("asc")
+			}
+			q.orderBy = append(q.orderBy, order)
+
+			if _, ok := p.accept(","); !ok {
+				return nil
+			}
+		}
+	case "limit", "offset":
+		t := p.next()
+		n, convErr := strconv.Atoi(t.value)
+		if t.kind != queryTokenNumber || convErr != nil || n < 0 {
+			return fmt.Errorf("invalid %s: %s", strings.ToUpper(clause), t.value)
+		}
+		if clause == "limit" {
+			q.limit = n
+		} else {
+			q.offset = n
+		}
+	case "label", "format":
+		for {
+			expr, err := p.parseExpr()
+			if err != nil {
+				return err
+			}
+			t := p.next()
+			if t.kind != queryTokenString {
+				return fmt.Errorf("expected a string after %s in %s", expr, strings.ToUpper(clause))
+			}
+			if clause == "label" {
+				q.labels[expr.String()] = t.value
+			} else {
+				q.formats[expr.String()] = t.value
+			}
+
+			if _, ok := p.accept(","); !ok {
+				return nil
+			}
+		}
+	case "options":
+		for {
+			t := p.next()
+			switch {
+			case t.is("no_format"):
+				q.noFormat = true
+			case t.is("no_values"):
+				q.noValues = true
+			default:
+				return fmt.Errorf("unknown option: %s", t.value)
+			}
+			if p.peek().kind != queryTokenIdent || isClause(p.peek()) {
+				return nil
+			}
+			p.accept(",")
+		}
+	}
+	return err
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() queryToken {
+	t := p.tokens[p.pos]
+	if t.kind != queryTokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *queryParser) accept(values ...string) (string, bool) {
+	t := p.peek()
+	for _, v := range values {
+		if t.is(v) {
+			p.pos++
+			return strings.ToLower(v), true
+		}
+	}
+	return "", false
+}
+
+func (p *queryParser) expect(value string) error {
+	if _, ok := p.accept(value); !ok {
+		return fmt.Errorf("Encountered \"%s\", was expecting \"%s\"", p.peek().value, value)
+	}
+	return nil
+}
+
+func (p *queryParser) parseExprList() ([]queryExpr, error) {
+	var exprs []queryExpr
+	for {
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+
+		if _, ok := p.accept(","); !ok {
+			return exprs, nil
+		}
+	}
+}
+
+func (p *queryParser) parseExpr() (queryExpr, error) {
+	return p.parseOr()
+}
+
+func (p *queryParser) parseOr() (queryExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("or"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr{op: "or", left: left, right: right}
+	}
+}
+
+func (p *queryParser) parseAnd() (queryExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("and"); !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr{op: "and", left: left, right: right}
+	}
+}
+
+func (p *queryParser) parseNot() (queryExpr, error) {
+	if _, ok := p.accept("not"); ok {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return unaryExpr{op: "not", operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *queryParser) parseComparison() (queryExpr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := p.accept("is"); ok {
+		_, not := p.accept("not")
+		if err := p.expect("null"); err != nil {
+			return nil, err
+		}
+		return isNullExpr{operand: left, not: not}, nil
+	}
+
+	op, ok := p.accept("=", "!=", "<>", "<", "<=", ">", ">=", "contains", "matches", "like")
+	if !ok {
+		if op, ok = p.accept("starts", "ends"); !ok {
+			return left, nil
+		}
+		if err := p.expect("with"); err != nil {
+			return nil, err
+		}
+		op += " with"
+	}
+	if op == "<>" {
+		op = "!="
+	}
+
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	return binaryExpr{op: op, left: left, right: right}, nil
+}
+
+func (p *queryParser) parseAdditive() (queryExpr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr{op: op, left: left, right: right}
+	}
+}
+
+func (p *queryParser) parseMultiplicative() (queryExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("*", "/", "%")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr{op: op, left: left, right: right}
+	}
+}
+
+func (p *queryParser) parseUnary() (queryExpr, error) {
+	if _, ok := p.accept("-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if lit, ok := operand.(literalExpr); ok {
+			if f, ok := lit.value.(float64); ok {
+				return literalExpr{value: -f}, nil
+			}
+		}
+		return unaryExpr{op: "-", operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (queryExpr, error) {
+	t := p.next()
+	switch t.kind {
+	case queryTokenNumber:
+		f, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number: %s", t.value)
+		}
+		return literalExpr{value: f}, nil
+	case queryTokenString:
+		return literalExpr{value: t.value}, nil
+	case queryTokenColumn:
+		return columnExpr{name: t.value}, nil
+	case queryTokenOp:
+		if t.value != "(" {
+			return nil, fmt.Errorf("Encountered \"%s\" at line 1, column %d", t.value, p.pos)
+		}
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		return expr, p.expect(")")
+	case queryTokenIdent:
+		return p.parseIdent(t)
+	default:
+		return nil, fmt.Errorf("Encountered \"<EOF>\" at line 1, column %d", p.pos)
+	}
+}
+
+func (p *queryParser) parseIdent(t queryToken) (queryExpr, error) {
+	switch {
+	case t.is("true"):
+		return literalExpr{value: true}, nil
+	case t.is("false"):
+		return literalExpr{value: false}, nil
+	case t.is("null"):
+		return literalExpr{}, nil
+	case t.is("date"), t.is("datetime"), t.is("timeofday"), t.is("timestamp"):
+		// Date and time values are not supported by the emulator, they are compared as strings instead.
+		if p.peek().kind == queryTokenString {
+			return literalExpr{value: p.next().value}, nil
+		}
+	}
+
+	if _, ok := p.accept("("); ok {
+		name := strings.ToLower(t.value)
+		if !aggregateFunctions[name] && !scalarFunctions[name] {
+			return nil, fmt.Errorf("unknown function: %s", t.value)
+		}
+
+		var args []queryExpr
+		if _, ok := p.accept(")"); !ok {
+			var err error
+			if args, err = p.parseExprList(); err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+		}
+		if len(args) != 1 {
+			return nil, fmt.Errorf("function %s requires exactly 1 argument, got %d", name, len(args))
+		}
+		return funcExpr{name: name, args: args}, nil
+	}
+
+	if isClause(t) {
+		return nil, fmt.Errorf("Encountered \"%s\" at line 1, column %d", t.value, p.pos)
+	}
+	return columnExpr{name: t.value}, nil
+}
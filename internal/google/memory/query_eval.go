@@ -0,0 +1,897 @@
+package memory
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/FreeLeh/GoFreeDB/internal/common"
+)
+
+const (
+	// QueryReasonInvalidQuery is the error reason of an invalid query, e.g. referring to an unknown column.
+	QueryReasonInvalidQuery = "invalid_query"
+
+	// QueryReasonUnknownDataSourceID is the error reason of a query to an unknown sheet.
+	QueryReasonUnknownDataSourceID = "unknown_data_source_id"
+
+	queryTypeNumber  = "number"
+	queryTypeString  = "string"
+	queryTypeBoolean = "boolean"
+
+	defaultNumberPattern = "General"
+)
+
+// QueryError is an error reported by the query endpoint.
+type QueryError struct {
+	// Reason is either QueryReasonInvalidQuery or QueryReasonUnknownDataSourceID.
+	Reason          string
+	Message         string
+	DetailedMessage string
+}
+
+func (e *QueryError) Error() string {
+	return e.DetailedMessage
+}
+
+func errInvalidQuery(format string, args ...interface{}) error {
+	return &QueryError{
+		Reason:          QueryReasonInvalidQuery,
+		Message:         "INVALID_QUERY",
+		DetailedMessage: "Invalid query: " + fmt.Sprintf(format, args...),
+	}
+}
+
+// QueryColumn describes a column of the query result.
+type QueryColumn struct {
+	ID      string
+	Label   string
+	Type    string
+	Pattern string
+}
+
+// QueryCell is a non-null cell of the query result.
+type QueryCell struct {
+	// Value is a float64, string or bool value. It is nil when the "options no_values" clause is used.
+	Value interface{}
+
+	// Formatted is the formatted value. It is empty for strings, as it is the same as the value,
+	// and when the "options no_format" clause is used.
+	Formatted string
+}
+
+// QueryResult is the result of a query. A nil cell is a null value.
+type QueryResult struct {
+	Columns []QueryColumn
+	Rows    [][]*QueryCell
+}
+
+type queryColumn struct {
+	id    string
+	label string
+	typ   string
+}
+
+// queryTable is the data source of a query, i.e. the sheet values converted into typed columns.
+type queryTable struct {
+	columns []queryColumn
+	// rows contains float64, string or bool values. A nil value is a null cell.
+	rows [][]interface{}
+}
+
+// Query runs a Google Visualization API query against the sheet. An empty sheet name uses the first sheet.
+// The first headers rows are used as the column labels instead of as the data rows.
+//
+// Errors related to the query are returned as *QueryError.
+func (b *Backend) Query(spreadsheetID string, sheetName string, rawQuery string, headers int) (*QueryResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ss := b.getSpreadsheet(spreadsheetID)
+
+	var sh *sheet
+	if sheetName == "" {
+		sh = ss.sheets[0]
+	} else if sh = ss.sheetByTitle(sheetName); sh == nil {
+		return nil, &QueryError{
+			Reason:          QueryReasonUnknownDataSourceID,
+			Message:         "UNKNOWN_DATA_SOURCE_ID",
+			DetailedMessage: fmt.Sprintf("Invalid sheet: %s", sheetName),
+		}
+	}
+
+	q, err := parseQuery(rawQuery)
+	if err != nil {
+		return nil, errInvalidQuery("PARSE_ERROR: %s", err)
+	}
+	return ss.newQueryTable(sh, headers).execute(q)
+}
+
+// newQueryTable converts the sheet data range into a query table.
+// Just like Google Sheets, the columns after the last column containing any value are not part of the table.
+func (ss *spreadsheet) newQueryTable(sh *sheet, headers int) *queryTable {
+	lastRow := sh.lastRow(0, sh.columnCount-1)
+	columnCount := sh.lastCol() + 1
+
+	values := make([][]interface{}, 0)
+	for row := 0; row <= lastRow; row++ {
+		rowValues := make([]interface{}, columnCount)
+		for col := 0; col < columnCount; col++ {
+			rowValues[col] = ss.evaluateCell(sh, row, col)
+		}
+		values = append(values, rowValues)
+	}
+
+	if headers > len(values) {
+		headers = len(values)
+	}
+	headerRows, dataRows := values[:headers], values[headers:]
+
+	t := &queryTable{columns: make([]queryColumn, columnCount), rows: dataRows}
+	for col := range t.columns {
+		labels := make([]string, 0, len(headerRows))
+		for _, row := range headerRows {
+			if row[col] != nil {
+				labels = append(labels, toFormulaString(formatValue(row[col])))
+			}
+		}
+
+		t.columns[col] = queryColumn{
+			id:    common.GenerateColumnName(col),
+			label: strings.Join(labels, " "),
+			typ:   columnType(dataRows, col),
+		}
+
+		// Just like Google Sheets, the values not matching the column type are returned as null.
+		for _, row := range dataRows {
+			if row[col] != nil && valueType(row[col]) != t.columns[col].typ {
+				row[col] = nil
+			}
+		}
+	}
+	return t
+}
+
+// columnType returns the majority type of the column values. An empty column is a string column.
+func columnType(rows [][]interface{}, col int) string {
+	counts := map[string]int{}
+	for _, row := range rows {
+		if row[col] != nil {
+			counts[valueType(row[col])]++
+		}
+	}
+
+	result, max := queryTypeString, 0
+	for _, typ := range []string{queryTypeNumber, queryTypeString, queryTypeBoolean} {
+		if counts[typ] > max {
+			result, max = typ, counts[typ]
+		}
+	}
+	return result
+}
+
+func valueType(value interface{}) string {
+	switch value.(type) {
+	case float64:
+		return queryTypeNumber
+	case bool:
+		return queryTypeBoolean
+	case nil:
+		return ""
+	default:
+		return queryTypeString
+	}
+}
+
+func (t *queryTable) column(name string) (int, bool) {
+	for i, col := range t.columns {
+		if col.id == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (t *queryTable) execute(q *query) (*QueryResult, error) {
+	selects := q.selects
+	if selects == nil {
+		for _, col := range t.columns {
+			selects = append(selects, columnExpr{name: col.id})
+		}
+	}
+
+	types, err := t.validate(q, selects)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([][]interface{}, 0, len(t.rows))
+	for _, row := range t.rows {
+		if q.where != nil {
+			matched, err := t.eval(q.where, row)
+			if err != nil {
+				return nil, err
+			}
+			if matched != true {
+				continue
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	type resultRow struct {
+		values []interface{}
+		keys   []interface{}
+	}
+	var results []resultRow
+
+	evalRows := func(group [][]interface{}) error {
+		r := resultRow{values: make([]interface{}, len(selects)), keys: make([]interface{}, len(q.orderBy))}
+		for i, expr := range selects {
+			v, err := t.evalGroup(expr, group)
+			if err != nil {
+				return err
+			}
+			r.values[i] = v
+		}
+		for i, order := range q.orderBy {
+			v, err := t.evalGroup(order.expr, group)
+			if err != nil {
+				return err
+			}
+			r.keys[i] = v
+		}
+		results = append(results, r)
+		return nil
+	}
+
+	if q.isAggregated(selects) {
+		for _, group := range t.groupRows(q.groupBy, rows) {
+			if err := evalRows(group); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		for _, row := range rows {
+			if err := evalRows([][]interface{}{row}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		for k, order := range q.orderBy {
+			c := compareValues(results[i].keys[k], results[j].keys[k])
+			if c == 0 {
+				continue
+			}
+			if order.desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+
+	if q.offset >= len(results) {
+		results = nil
+	} else {
+		results = results[q.offset:]
+	}
+	if q.limit >= 0 && q.limit < len(results) {
+		results = results[:q.limit]
+	}
+
+	result := &QueryResult{Columns: make([]QueryColumn, len(selects)), Rows: make([][]*QueryCell, len(results))}
+	for i, expr := range selects {
+		id := expr.String()
+		label, ok := q.labels[id]
+		if !ok {
+			label = t.defaultLabel(expr)
+		}
+
+		col := QueryColumn{ID: id, Label: label, Type: types[i]}
+		if pattern, ok := q.formats[id]; ok {
+			col.Pattern = pattern
+		} else if types[i] == queryTypeNumber {
+			col.Pattern = defaultNumberPattern
+		}
+		result.Columns[i] = col
+	}
+
+	for i, r := range results {
+		cells := make([]*QueryCell, len(r.values))
+		for j, v := range r.values {
+			cells[j] = queryCell(v, result.Columns[j].Pattern, q.noFormat, q.noValues)
+		}
+		result.Rows[i] = cells
+	}
+	return result, nil
+}
+
+// validate checks the query semantics and returns the type of each selected column.
+func (t *queryTable) validate(q *query, selects []queryExpr) ([]string, error) {
+	if q.where != nil {
+		if containsAggregate(q.where) {
+			return nil, errInvalidQuery("CANNOT_BE_IN_WHERE: %s", q.where)
+		}
+		if _, err := t.typeOf(q.where); err != nil {
+			return nil, err
+		}
+	}
+
+	groupBy := make(map[string]bool)
+	for _, expr := range q.groupBy {
+		if containsAggregate(expr) {
+			return nil, errInvalidQuery("CANNOT_BE_IN_GROUP_BY: %s", expr)
+		}
+		if _, err := t.typeOf(expr); err != nil {
+			return nil, err
+		}
+		groupBy[expr.String()] = true
+	}
+
+	aggregated := q.isAggregated(selects)
+	if len(q.groupBy) > 0 && !aggregated {
+		return nil, errInvalidQuery("CANNOT_GROUP_WITHOUT_AGG")
+	}
+
+	types := make([]string, len(selects))
+	for i, expr := range selects {
+		typ, err := t.typeOf(expr)
+		if err != nil {
+			return nil, err
+		}
+		types[i] = typ
+
+		if aggregated && !containsAggregate(expr) && !groupBy[expr.String()] {
+			return nil, errInvalidQuery("ADD_COL_TO_GROUP_BY_OR_AGG: %s", expr)
+		}
+	}
+
+	for _, order := range q.orderBy {
+		if _, err := t.typeOf(order.expr); err != nil {
+			return nil, err
+		}
+		if aggregated && !containsAggregate(order.expr) && !groupBy[order.expr.String()] {
+			return nil, errInvalidQuery("COL_IN_ORDER_MUST_BE_IN_SELECT: %s", order.expr)
+		}
+	}
+	return types, nil
+}
+
+func (q *query) isAggregated(selects []queryExpr) bool {
+	if len(q.groupBy) > 0 {
+		return true
+	}
+	for _, expr := range selects {
+		if containsAggregate(expr) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAggregate(expr queryExpr) bool {
+	switch e := expr.(type) {
+	case funcExpr:
+		if aggregateFunctions[e.name] {
+			return true
+		}
+		for _, arg := range e.args {
+			if containsAggregate(arg) {
+				return true
+			}
+		}
+	case unaryExpr:
+		return containsAggregate(e.operand)
+	case binaryExpr:
+		return containsAggregate(e.left) || containsAggregate(e.right)
+	case isNullExpr:
+		return containsAggregate(e.operand)
+	}
+	return false
+}
+
+// typeOf returns the static type of the expression. It is empty for the null literal.
+func (t *queryTable) typeOf(expr queryExpr) (string, error) {
+	switch e := expr.(type) {
+	case columnExpr:
+		idx, ok := t.column(e.name)
+		if !ok {
+			return "", errInvalidQuery("NO_COLUMN: %s", e.name)
+		}
+		return t.columns[idx].typ, nil
+	case literalExpr:
+		return valueType(e.value), nil
+	case isNullExpr:
+		if _, err := t.typeOf(e.operand); err != nil {
+			return "", err
+		}
+		return queryTypeBoolean, nil
+	case unaryExpr:
+		want := queryTypeBoolean
+		if e.op == "-" {
+			want = queryTypeNumber
+		}
+		if err := t.expectType(e.operand, want, e.op); err != nil {
+			return "", err
+		}
+		return want, nil
+	case binaryExpr:
+		return t.typeOfBinary(e)
+	case funcExpr:
+		switch e.name {
+		case "count":
+			_, err := t.typeOf(e.args[0])
+			return queryTypeNumber, err
+		case "sum", "avg":
+			return queryTypeNumber, t.expectType(e.args[0], queryTypeNumber, e.name)
+		case "upper", "lower":
+			return queryTypeString, t.expectType(e.args[0], queryTypeString, e.name)
+		default:
+			return t.typeOf(e.args[0])
+		}
+	}
+	return "", errInvalidQuery("unsupported expression: %s", expr)
+}
+
+func (t *queryTable) typeOfBinary(e binaryExpr) (string, error) {
+	switch e.op {
+	case "and", "or":
+		if err := t.expectType(e.left, queryTypeBoolean, e.op); err != nil {
+			return "", err
+		}
+		return queryTypeBoolean, t.expectType(e.right, queryTypeBoolean, e.op)
+	case "contains", "starts with", "ends with", "matches", "like":
+		if err := t.expectType(e.left, queryTypeString, e.op); err != nil {
+			return "", err
+		}
+		return queryTypeBoolean, t.expectType(e.right, queryTypeString, e.op)
+	case "+", "-", "*", "/", "%":
+		if err := t.expectType(e.left, queryTypeNumber, e.op); err != nil {
+			return "", err
+		}
+		return queryTypeNumber, t.expectType(e.right, queryTypeNumber, e.op)
+	}
+
+	left, err := t.typeOf(e.left)
+	if err != nil {
+		return "", err
+	}
+	right, err := t.typeOf(e.right)
+	if err != nil {
+		return "", err
+	}
+	if left != "" && right != "" && left != right {
+		return "", errInvalidQuery(
+			"Can't perform the function '%s' on values that are not compatible: %s %s %s",
+			e.op, e.left, e.op, e.right,
+		)
+	}
+	return queryTypeBoolean, nil
+}
+
+func (t *queryTable) expectType(expr queryExpr, want string, op string) error {
+	typ, err := t.typeOf(expr)
+	if err != nil {
+		return err
+	}
+	if typ != "" && typ != want {
+		return errInvalidQuery("Can't perform the function '%s' on a column that is not a %s column: %s", op, want, expr)
+	}
+	return nil
+}
+
+// groupRows groups the rows by the given expressions, ordered by the group values.
+// Without any group by expression, all rows are in a single group unless there is no row at all.
+func (t *queryTable) groupRows(groupBy []queryExpr, rows [][]interface{}) [][][]interface{} {
+	if len(rows) == 0 {
+		return nil
+	}
+	if len(groupBy) == 0 {
+		return [][][]interface{}{rows}
+	}
+
+	type group struct {
+		keys []interface{}
+		rows [][]interface{}
+	}
+	var groups []*group
+	index := make(map[string]*group)
+
+	for _, row := range rows {
+		keys := make([]interface{}, len(groupBy))
+		for i, expr := range groupBy {
+			// The expressions are validated already and cannot fail.
+			keys[i], _ = t.eval(expr, row)
+		}
+
+		id := fmt.Sprintf("%#v", keys)
+		g, ok := index[id]
+		if !ok {
+			g = &group{keys: keys}
+			index[id] = g
+			groups = append(groups, g)
+		}
+		g.rows = append(g.rows, row)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		for k := range groupBy {
+			if c := compareValues(groups[i].keys[k], groups[j].keys[k]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+
+	result := make([][][]interface{}, len(groups))
+	for i, g := range groups {
+		result[i] = g.rows
+	}
+	return result
+}
+
+// evalGroup evaluates an expression that may contain aggregations over the given rows.
+// The non-aggregated parts are evaluated against the first row, as they are the same for the whole group.
+func (t *queryTable) evalGroup(expr queryExpr, rows [][]interface{}) (interface{}, error) {
+	if !containsAggregate(expr) {
+		return t.eval(expr, rows[0])
+	}
+
+	switch e := expr.(type) {
+	case funcExpr:
+		if aggregateFunctions[e.name] {
+			return t.aggregate(e, rows)
+		}
+		arg, err := t.evalGroup(e.args[0], rows)
+		if err != nil {
+			return nil, err
+		}
+		return evalScalarFunc(e.name, arg), nil
+	case unaryExpr:
+		operand, err := t.evalGroup(e.operand, rows)
+		if err != nil {
+			return nil, err
+		}
+		return evalUnary(e.op, operand), nil
+	case binaryExpr:
+		left, err := t.evalGroup(e.left, rows)
+		if err != nil {
+			return nil, err
+		}
+		right, err := t.evalGroup(e.right, rows)
+		if err != nil {
+			return nil, err
+		}
+		return evalBinary(e.op, left, right)
+	case isNullExpr:
+		operand, err := t.evalGroup(e.operand, rows)
+		if err != nil {
+			return nil, err
+		}
+		return (operand == nil) != e.not, nil
+	}
+	return nil, errInvalidQuery("unsupported expression: %s", expr)
+}
+
+func (t *queryTable) aggregate(e funcExpr, rows [][]interface{}) (interface{}, error) {
+	var values []interface{}
+	for _, row := range rows {
+		v, err := t.eval(e.args[0], row)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			values = append(values, v)
+		}
+	}
+
+	if e.name == "count" {
+		return float64(len(values)), nil
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	switch e.name {
+	case "sum", "avg":
+		total := float64(0)
+		for _, v := range values {
+			total += v.(float64)
+		}
+		if e.name == "avg" {
+			return total / float64(len(values)), nil
+		}
+		return total, nil
+	default:
+		result := values[0]
+		for _, v := range values[1:] {
+			c := compareValues(v, result)
+			if (e.name == "min" && c < 0) || (e.name == "max" && c > 0) {
+				result = v
+			}
+		}
+		return result, nil
+	}
+}
+
+func (t *queryTable) eval(expr queryExpr, row []interface{}) (interface{}, error) {
+	switch e := expr.(type) {
+	case columnExpr:
+		idx, ok := t.column(e.name)
+		if !ok {
+			return nil, errInvalidQuery("NO_COLUMN: %s", e.name)
+		}
+		return row[idx], nil
+	case literalExpr:
+		return e.value, nil
+	case isNullExpr:
+		operand, err := t.eval(e.operand, row)
+		if err != nil {
+			return nil, err
+		}
+		return (operand == nil) != e.not, nil
+	case unaryExpr:
+		operand, err := t.eval(e.operand, row)
+		if err != nil {
+			return nil, err
+		}
+		return evalUnary(e.op, operand), nil
+	case binaryExpr:
+		left, err := t.eval(e.left, row)
+		if err != nil {
+			return nil, err
+		}
+		// Short-circuit, so that "A is not null and A > 1" does not evaluate the second part needlessly.
+		if e.op == "and" && left != true {
+			return false, nil
+		}
+		if e.op == "or" && left == true {
+			return true, nil
+		}
+		right, err := t.eval(e.right, row)
+		if err != nil {
+			return nil, err
+		}
+		return evalBinary(e.op, left, right)
+	case funcExpr:
+		if aggregateFunctions[e.name] {
+			return nil, errInvalidQuery("aggregation is not allowed here: %s", e)
+		}
+		arg, err := t.eval(e.args[0], row)
+		if err != nil {
+			return nil, err
+		}
+		return evalScalarFunc(e.name, arg), nil
+	}
+	return nil, errInvalidQuery("unsupported expression: %s", expr)
+}
+
+func evalUnary(op string, operand interface{}) interface{} {
+	switch v := operand.(type) {
+	case bool:
+		return !v
+	case float64:
+		return -v
+	default:
+		// A null operand results in false for "not" and null for "-".
+		if op == "not" {
+			return false
+		}
+		return nil
+	}
+}
+
+func evalScalarFunc(name string, arg interface{}) interface{} {
+	s, ok := arg.(string)
+	if !ok {
+		return nil
+	}
+	if name == "upper" {
+		return strings.ToUpper(s)
+	}
+	return strings.ToLower(s)
+}
+
+func evalBinary(op string, left interface{}, right interface{}) (interface{}, error) {
+	switch op {
+	case "and":
+		return left == true && right == true, nil
+	case "or":
+		return left == true || right == true, nil
+	case "+", "-", "*", "/", "%":
+		l, lok := left.(float64)
+		r, rok := right.(float64)
+		if !lok || !rok {
+			return nil, nil
+		}
+		switch op {
+		case "+":
+			return l + r, nil
+		case "-":
+			return l - r, nil
+		case "*":
+			return l * r, nil
+		}
+		if r == 0 {
+			return nil, nil
+		}
+		if op == "/" {
+			return l / r, nil
+		}
+		return float64(int64(l) % int64(r)), nil
+	}
+
+	// Any comparison with a null value is false.
+	if left == nil || right == nil {
+		return false, nil
+	}
+
+	switch op {
+	case "contains", "starts with", "ends with", "matches", "like":
+		l, r := toFormulaString(left), toFormulaString(right)
+		switch op {
+		case "contains":
+			return strings.Contains(l, r), nil
+		case "starts with":
+			return strings.HasPrefix(l, r), nil
+		case "ends with":
+			return strings.HasSuffix(l, r), nil
+		case "matches":
+			re, err := regexp.Compile("^(?:" + r + ")$")
+			if err != nil {
+				return nil, errInvalidQuery("invalid regular expression: %s", r)
+			}
+			return re.MatchString(l), nil
+		default:
+			return likePattern(r).MatchString(l), nil
+		}
+	}
+
+	c := compareValues(left, right)
+	switch op {
+	case "=":
+		return c == 0, nil
+	case "!=":
+		return c != 0, nil
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	default:
+		return c >= 0, nil
+	}
+}
+
+// likePattern converts a LIKE pattern, where "%" matches any characters and "_" matches a single character,
+// into a regular expression.
+func likePattern(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+func (t *queryTable) defaultLabel(expr queryExpr) string {
+	switch e := expr.(type) {
+	case columnExpr:
+		if idx, ok := t.column(e.name); ok {
+			return t.columns[idx].label
+		}
+	case funcExpr:
+		return e.name + " " + t.defaultLabel(e.args[0])
+	}
+	return expr.String()
+}
+
+func queryCell(value interface{}, pattern string, noFormat bool, noValues bool) *QueryCell {
+	if value == nil {
+		return nil
+	}
+
+	formatted := ""
+	switch v := value.(type) {
+	case float64:
+		formatted = formatNumberPattern(v, pattern)
+	case bool:
+		formatted = formatValue(v).(string)
+	case string:
+		// The formatted value of a string is the string itself, so it is omitted.
+		if noValues {
+			return &QueryCell{Formatted: v}
+		}
+		return &QueryCell{Value: v}
+	}
+
+	result := &QueryCell{}
+	if !noValues {
+		result.Value = value
+	}
+	if !noFormat || noValues {
+		result.Formatted = formatted
+	}
+	return result
+}
+
+// formatNumberPattern formats a number based on a subset of the ICU decimal format patterns,
+// e.g. "#,##0.00", "0.#" and "0%". Any text before and after the pattern is kept as is.
+func formatNumberPattern(value float64, pattern string) string {
+	if pattern == "" || pattern == defaultNumberPattern {
+		return formatNumber(value)
+	}
+
+	start := strings.IndexAny(pattern, "#0")
+	if start == -1 {
+		return formatNumber(value)
+	}
+	end := strings.LastIndexAny(pattern, "#0") + 1
+	prefix, number, suffix := pattern[:start], pattern[start:end], pattern[end:]
+	if idx := strings.LastIndex(prefix, "."); idx != -1 && idx == len(prefix)-1 {
+		prefix, number = prefix[:idx], "."+number
+	}
+
+	if strings.Contains(prefix+suffix, "%") {
+		value *= 100
+	}
+
+	minDecimals, maxDecimals := 0, 0
+	if idx := strings.Index(number, "."); idx != -1 {
+		decimals := number[idx+1:]
+		maxDecimals = len(decimals)
+		minDecimals = strings.Count(decimals, "0")
+	}
+
+	formatted := strconv.FormatFloat(value, 'f', maxDecimals, 64)
+	if maxDecimals > minDecimals && strings.Contains(formatted, ".") {
+		intPart, decPart := formatted[:strings.Index(formatted, ".")], formatted[strings.Index(formatted, ".")+1:]
+		for len(decPart) > minDecimals && strings.HasSuffix(decPart, "0") {
+			decPart = decPart[:len(decPart)-1]
+		}
+		formatted = intPart
+		if decPart != "" {
+			formatted += "." + decPart
+		}
+	}
+
+	if strings.Contains(number, ",") {
+		formatted = groupThousands(formatted)
+	}
+	return prefix + formatted + suffix
+}
+
+func groupThousands(number string) string {
+	sign := ""
+	if strings.HasPrefix(number, "-") {
+		sign, number = "-", number[1:]
+	}
+
+	intPart, rest := number, ""
+	if idx := strings.Index(number, "."); idx != -1 {
+		intPart, rest = number[:idx], number[idx:]
+	}
+
+	var b strings.Builder
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	return sign + b.String() + rest
+}
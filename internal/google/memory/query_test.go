@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteQuery(t *testing.T) {
+	b := NewBackend()
+	sh := b.getSpreadsheet("id").sheets[0]
+
+	rows := [][]interface{}{
+		{"name", "age", "active"},
+		{"alice", float64(1234.5), true},
+		{"bob", float64(20), false},
+		{"carol", "n/a", true},
+	}
+	for i, row := range rows {
+		for j, value := range row {
+			sh.set(i, j, cell{value: value})
+		}
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected [][]*QueryCell
+	}{
+		{
+			name:     "where_like_and_contains",
+			query:    "select A where A like 'a%' or A contains 'ro'",
+			expected: [][]*QueryCell{{{Value: "alice"}}, {{Value: "carol"}}},
+		},
+		{
+			name:     "minority_type_is_null",
+			query:    "select B where A = 'carol'",
+			expected: [][]*QueryCell{{nil}},
+		},
+		{
+			name:     "format_and_options",
+			query:    "select B where C = true and B is not null format B '#,##0.00' options no_values",
+			expected: [][]*QueryCell{{{Formatted: "1,234.50"}}},
+		},
+		{
+			name:     "aggregate",
+			query:    "select max(B), count(A) where A != 'x'",
+			expected: [][]*QueryCell{{{Value: float64(1234.5), Formatted: "1234.5"}, {Value: float64(3), Formatted: "3"}}},
+		},
+		{
+			name:     "offset_limit",
+			query:    "select A order by A desc offset 1 limit 1",
+			expected: [][]*QueryCell{{{Value: "bob"}}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := b.Query("id", "Sheet1", tc.query, 1)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, result.Rows)
+		})
+	}
+
+	errors := []struct {
+		query    string
+		expected string
+	}{
+		{query: "select D", expected: "Invalid query: NO_COLUMN: D"},
+		{query: "select A where B = 'x'", expected: "Invalid query: Can't perform the function '=' on values that are not compatible: B = 'x'"},
+		{query: "select A, count(B)", expected: "Invalid query: ADD_COL_TO_GROUP_BY_OR_AGG: A"},
+		{query: "where A = 'x' select A", expected: "Invalid query: PARSE_ERROR: clause SELECT is in the wrong position or repeated"},
+	}
+	for _, tc := range errors {
+		t.Run(tc.query, func(t *testing.T) {
+			_, err := b.Query("id", "Sheet1", tc.query, 1)
+			assert.Equal(t, &QueryError{Reason: QueryReasonInvalidQuery, Message: "INVALID_QUERY", DetailedMessage: tc.expected}, err)
+		})
+	}
+
+	_, err := b.Query("id", "unknown", "select A", 1)
+	assert.Equal(t, QueryReasonUnknownDataSourceID, err.(*QueryError).Reason)
+}
@@ -0,0 +1,452 @@
+package memory
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/FreeLeh/GoFreeDB/internal/common"
+)
+
+const (
+	defaultRowCount    = 1000
+	defaultColumnCount = 26
+	defaultSheetTitle  = "Sheet1"
+
+	// RenderFormatted renders the values using the default "General" number format, e.g. TRUE as "TRUE".
+	RenderFormatted = "FORMATTED_VALUE"
+
+	// RenderUnformatted renders the computed values as is.
+	RenderUnformatted = "UNFORMATTED_VALUE"
+
+	// RenderFormula renders the formula of a cell instead of its computed value.
+	RenderFormula = "FORMULA"
+
+	// InputUserEntered parses the values as if they were typed into the Google Sheets UI.
+	InputUserEntered = "USER_ENTERED"
+
+	// InputRaw stores the values as is.
+	InputRaw = "RAW"
+
+	errorNA   = "#N/A"
+	errorName = "#NAME?"
+	errorRef  = "#REF!"
+	errorDiv0 = "#DIV/0!"
+	errorVal  = "#VALUE!"
+)
+
+// cell holds either a formula or a literal value (float64, string, bool or nil).
+type cell struct {
+	formula string
+	value   interface{}
+}
+
+func (c cell) isEmpty() bool {
+	return c.formula == "" && c.value == nil
+}
+
+type sheet struct {
+	id          int64
+	title       string
+	rowCount    int
+	columnCount int
+	rows        [][]cell
+}
+
+func (s *sheet) get(row int, col int) cell {
+	if row < 0 || row >= len(s.rows) || col < 0 || col >= len(s.rows[row]) {
+		return cell{}
+	}
+	return s.rows[row][col]
+}
+
+func (s *sheet) set(row int, col int, c cell) {
+	for len(s.rows) <= row {
+		s.rows = append(s.rows, nil)
+	}
+	for len(s.rows[row]) <= col {
+		s.rows[row] = append(s.rows[row], cell{})
+	}
+	s.rows[row][col] = c
+
+	if row >= s.rowCount {
+		s.rowCount = row + 1
+	}
+	if col >= s.columnCount {
+		s.columnCount = col + 1
+	}
+}
+
+// lastRow returns the last non-empty row index within the given columns, or -1 if there is none.
+func (s *sheet) lastRow(startCol int, endCol int) int {
+	for row := len(s.rows) - 1; row >= 0; row-- {
+		for col := startCol; col <= endCol && col < len(s.rows[row]); col++ {
+			if !s.rows[row][col].isEmpty() {
+				return row
+			}
+		}
+	}
+	return -1
+}
+
+// lastCol returns the last non-empty column index, or -1 if the sheet is empty.
+func (s *sheet) lastCol() int {
+	result := -1
+	for _, row := range s.rows {
+		for col := len(row) - 1; col > result; col-- {
+			if !row[col].isEmpty() {
+				result = col
+				break
+			}
+		}
+	}
+	return result
+}
+
+type spreadsheet struct {
+	id          string
+	title       string
+	sheets      []*sheet
+	nextSheetID int64
+}
+
+func newSpreadsheet(id string, title string) *spreadsheet {
+	s := &spreadsheet{id: id, title: title}
+	s.addSheet(defaultSheetTitle, 0, 0)
+	return s
+}
+
+func (s *spreadsheet) addSheet(title string, rowCount int, columnCount int) *sheet {
+	if rowCount <= 0 {
+		rowCount = defaultRowCount
+	}
+	if columnCount <= 0 {
+		columnCount = defaultColumnCount
+	}
+
+	sh := &sheet{id: s.nextSheetID, title: title, rowCount: rowCount, columnCount: columnCount}
+	s.nextSheetID++
+	s.sheets = append(s.sheets, sh)
+	return sh
+}
+
+func (s *spreadsheet) sheetByTitle(title string) *sheet {
+	for _, sh := range s.sheets {
+		if sh.title == title {
+			return sh
+		}
+	}
+	return nil
+}
+
+func (s *spreadsheet) deleteSheet(id int64) bool {
+	for i, sh := range s.sheets {
+		if sh.id == id {
+			s.sheets = append(s.sheets[:i], s.sheets[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// gridRange is a parsed A1 notation range with 0-based inclusive indices.
+// An end index of -1 means the range is unbounded in that dimension.
+type gridRange struct {
+	sheetName string
+	startRow  int
+	startCol  int
+	endRow    int
+	endCol    int
+}
+
+// resolve returns the sheet referred by the range and clamps the unbounded dimensions to the sheet grid.
+func (s *spreadsheet) resolve(rng gridRange) (*sheet, gridRange, error) {
+	var sh *sheet
+	if rng.sheetName == "" {
+		if len(s.sheets) == 0 {
+			return nil, rng, errInvalidRange(formatA1Range("", rng))
+		}
+		sh = s.sheets[0]
+	} else if sh = s.sheetByTitle(rng.sheetName); sh == nil {
+		return nil, rng, errInvalidRange(formatA1Range(rng.sheetName, rng))
+	}
+
+	if rng.endRow < 0 {
+		rng.endRow = sh.rowCount - 1
+	}
+	if rng.endCol < 0 {
+		rng.endCol = sh.columnCount - 1
+	}
+	return sh, rng, nil
+}
+
+func parseA1Range(value string) (gridRange, error) {
+	sheetName, cells, err := splitSheetName(value)
+	if err != nil {
+		return gridRange{}, err
+	}
+
+	rng := gridRange{sheetName: sheetName, endRow: -1, endCol: -1}
+	if cells == "" {
+		return rng, nil
+	}
+
+	parts := strings.Split(cells, ":")
+	if len(parts) > 2 {
+		return gridRange{}, errInvalidRange(value)
+	}
+
+	startRow, startCol, err := parseCellRef(parts[0])
+	if err != nil {
+		return gridRange{}, errInvalidRange(value)
+	}
+	rng.startRow, rng.startCol = max0(startRow), max0(startCol)
+
+	if len(parts) == 1 {
+		rng.endRow, rng.endCol = startRow, startCol
+		return rng, nil
+	}
+
+	endRow, endCol, err := parseCellRef(parts[1])
+	if err != nil {
+		return gridRange{}, errInvalidRange(value)
+	}
+	rng.endRow, rng.endCol = endRow, endCol
+	if (rng.endRow >= 0 && rng.endRow < rng.startRow) || (rng.endCol >= 0 && rng.endCol < rng.startCol) {
+		return gridRange{}, errInvalidRange(value)
+	}
+	return rng, nil
+}
+
+func max0(v int) int {
+	if v < 0 {
+		return 0
+	}
+	return v
+}
+
+// splitSheetName splits "Sheet1!A1:B2" or "'My Sheet'!A1" into the sheet name and the cells part.
+func splitSheetName(value string) (string, string, error) {
+	if strings.HasPrefix(value, "'") {
+		var name strings.Builder
+		for i := 1; i < len(value); i++ {
+			if value[i] != '\'' {
+				name.WriteByte(value[i])
+				continue
+			}
+			if i+1 < len(value) && value[i+1] == '\'' {
+				name.WriteByte('\'')
+				i++
+				continue
+			}
+			rest := value[i+1:]
+			if rest == "" {
+				return name.String(), "", nil
+			}
+			if !strings.HasPrefix(rest, "!") {
+				return "", "", errInvalidRange(value)
+			}
+			return name.String(), rest[1:], nil
+		}
+		return "", "", errInvalidRange(value)
+	}
+
+	idx := strings.LastIndex(value, "!")
+	if idx == -1 {
+		// A value without "!" is either a sheet name or a range in the first sheet.
+		// A single reference without any row number (e.g. "kv") is considered as a sheet name.
+		if strings.Contains(value, ":") || strings.IndexFunc(value, unicode.IsDigit) != -1 {
+			if _, _, err := parseCellRef(strings.Split(value, ":")[0]); err == nil {
+				return "", value, nil
+			}
+		}
+		return value, "", nil
+	}
+	return value[:idx], value[idx+1:], nil
+}
+
+// parseCellRef parses "B3", "B" or "3" into 0-based row and column indices, where -1 means missing.
+func parseCellRef(ref string) (int, int, error) {
+	ref = strings.ReplaceAll(strings.TrimSpace(ref), "$", "")
+	if ref == "" {
+		return 0, 0, fmt.Errorf("empty cell reference")
+	}
+
+	idx := strings.IndexFunc(ref, unicode.IsDigit)
+	letters, digits := ref, ""
+	if idx != -1 {
+		letters, digits = ref[:idx], ref[idx:]
+	}
+
+	col := -1
+	if letters != "" {
+		c, err := columnIndex(letters)
+		if err != nil {
+			return 0, 0, err
+		}
+		col = c
+	}
+
+	row := -1
+	if digits != "" {
+		r, err := strconv.Atoi(digits)
+		if err != nil || r <= 0 {
+			return 0, 0, fmt.Errorf("invalid row number: %s", digits)
+		}
+		row = r - 1
+	}
+	return row, col, nil
+}
+
+// columnIndex converts a column name (e.g. "AB") into a 0-based index.
+func columnIndex(name string) (int, error) {
+	if name == "" || len(name) > 3 {
+		return 0, fmt.Errorf("invalid column: %s", name)
+	}
+
+	result := 0
+	for _, r := range strings.ToUpper(name) {
+		if r < 'A' || r > 'Z' {
+			return 0, fmt.Errorf("invalid column: %s", name)
+		}
+		result = result*26 + int(r-'A'+1)
+	}
+	return result - 1, nil
+}
+
+func formatA1Range(sheetName string, rng gridRange) string {
+	cells := formatCellRef(rng.startRow, rng.startCol)
+	if rng.endRow != rng.startRow || rng.endCol != rng.startCol {
+		cells += ":" + formatCellRef(rng.endRow, rng.endCol)
+	}
+	if sheetName == "" {
+		return cells
+	}
+	return quoteSheetName(sheetName) + "!" + cells
+}
+
+// formatCellRef formats 0-based indices into "B3", where -1 omits the row or the column.
+func formatCellRef(row int, col int) string {
+	result := ""
+	if col >= 0 {
+		result += common.GenerateColumnName(col)
+	}
+	if row >= 0 {
+		result += strconv.Itoa(row + 1)
+	}
+	return result
+}
+
+func quoteSheetName(name string) string {
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return "'" + strings.ReplaceAll(name, "'", "''") + "'"
+		}
+	}
+	return name
+}
+
+// parseInput converts a value sent through the API into a cell, based on the value input option.
+// The second return value is false if the value must not change the existing cell (i.e. JSON null).
+func parseInput(value interface{}, option string) (cell, bool) {
+	switch converted := value.(type) {
+	case nil:
+		return cell{}, false
+	case float64, bool:
+		return cell{value: converted}, true
+	case string:
+		if converted == "" {
+			return cell{}, true
+		}
+		if option == InputRaw {
+			return cell{value: converted}, true
+		}
+		return parseUserEntered(converted), true
+	default:
+		return cell{value: fmt.Sprint(converted)}, true
+	}
+}
+
+// parseUserEntered parses a string as if it is typed into the Google Sheets UI.
+// Note that dates and currencies are not recognised, they are kept as strings.
+func parseUserEntered(value string) cell {
+	if strings.HasPrefix(value, "'") {
+		return cell{value: value[1:]}
+	}
+	if strings.HasPrefix(value, "=") {
+		return cell{formula: value}
+	}
+
+	trimmed := strings.TrimSpace(value)
+	switch strings.ToUpper(trimmed) {
+	case "TRUE":
+		return cell{value: true}
+	case "FALSE":
+		return cell{value: false}
+	}
+	if f, err := strconv.ParseFloat(trimmed, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		return cell{value: f}
+	}
+	return cell{value: value}
+}
+
+// render converts a computed cell value based on the response value render option.
+func render(c cell, value interface{}, option string) interface{} {
+	switch option {
+	case RenderFormula:
+		if c.formula != "" {
+			return c.formula
+		}
+		return value
+	case RenderUnformatted:
+		return value
+	default:
+		return formatValue(value)
+	}
+}
+
+// formatValue formats a value using the default "General" number format.
+func formatValue(value interface{}) interface{} {
+	switch converted := value.(type) {
+	case float64:
+		return formatNumber(converted)
+	case bool:
+		if converted {
+			return "TRUE"
+		}
+		return "FALSE"
+	default:
+		return value
+	}
+}
+
+func formatNumber(value float64) string {
+	if math.Abs(value) >= 1e15 {
+		return strings.ToUpper(strconv.FormatFloat(value, 'E', -1, 64))
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// trimValues removes the trailing empty values of each row and the trailing empty rows,
+// just like the Google Sheets API does.
+func trimValues(values [][]interface{}) [][]interface{} {
+	for i := range values {
+		end := len(values[i])
+		for end > 0 && isEmptyValue(values[i][end-1]) {
+			end--
+		}
+		values[i] = values[i][:end]
+	}
+
+	end := len(values)
+	for end > 0 && len(values[end-1]) == 0 {
+		end--
+	}
+	return values[:end]
+}
+
+func isEmptyValue(value interface{}) bool {
+	return value == nil || value == ""
+}
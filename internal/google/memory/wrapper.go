@@ -0,0 +1,265 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
+	"google.golang.org/api/googleapi"
+)
+
+// Wrapper provides the same methods as sheets.Wrapper, but the data is stored in a Backend instead of
+// Google Sheets. The errors are also converted into the same error types returned by sheets.Wrapper.
+type Wrapper struct {
+	backend      *Backend
+	renderOption string
+}
+
+// NewWrapper creates a Wrapper rendering the returned values based on the given render option.
+// An empty render option uses sheets.ValueRenderFormatted, just like sheets.Wrapper.
+func NewWrapper(backend *Backend, renderOption sheets.ValueRenderOption) *Wrapper {
+	if renderOption == "" {
+		renderOption = sheets.ValueRenderFormatted
+	}
+	return &Wrapper{backend: backend, renderOption: string(renderOption)}
+}
+
+func (w *Wrapper) CreateSpreadsheet(ctx context.Context, title string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return w.backend.CreateSpreadsheet(title).ID, nil
+}
+
+func (w *Wrapper) CreateSheet(ctx context.Context, spreadsheetID string, sheetName string) error {
+	return w.CreateSheetWithGridSize(ctx, spreadsheetID, sheetName, sheets.GridSize{})
+}
+
+func (w *Wrapper) CreateSheetWithGridSize(
+	ctx context.Context,
+	spreadsheetID string,
+	sheetName string,
+	size sheets.GridSize,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err := w.backend.BatchUpdateSheets(spreadsheetID, []SheetRequest{{
+		AddSheet: &SheetProperties{Title: sheetName, RowCount: size.RowCount, ColumnCount: size.ColumnCount},
+	}})
+	return convertError(err)
+}
+
+func (w *Wrapper) GetSheetNameToID(ctx context.Context, spreadsheetID string) (map[string]int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int64)
+	for _, sh := range w.backend.Spreadsheet(spreadsheetID).Sheets {
+		result[sh.Title] = sh.ID
+	}
+	return result, nil
+}
+
+func (w *Wrapper) DeleteSheets(ctx context.Context, spreadsheetID string, sheetIDs []int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	requests := make([]SheetRequest, len(sheetIDs))
+	for i := range sheetIDs {
+		requests[i] = SheetRequest{DeleteSheetID: &sheetIDs[i]}
+	}
+	_, err := w.backend.BatchUpdateSheets(spreadsheetID, requests)
+	return convertError(err)
+}
+
+func (w *Wrapper) InsertRows(
+	ctx context.Context,
+	spreadsheetID string,
+	a1Range string,
+	values [][]interface{},
+) (sheets.InsertRowsResult, error) {
+	return w.insertRows(ctx, spreadsheetID, a1Range, values, true)
+}
+
+func (w *Wrapper) OverwriteRows(
+	ctx context.Context,
+	spreadsheetID string,
+	a1Range string,
+	values [][]interface{},
+) (sheets.InsertRowsResult, error) {
+	return w.insertRows(ctx, spreadsheetID, a1Range, values, false)
+}
+
+func (w *Wrapper) insertRows(
+	ctx context.Context,
+	spreadsheetID string,
+	a1Range string,
+	values [][]interface{},
+	insertRows bool,
+) (sheets.InsertRowsResult, error) {
+	if err := ctx.Err(); err != nil {
+		return sheets.InsertRowsResult{}, err
+	}
+
+	values, err := normalizeValues(values)
+	if err != nil {
+		return sheets.InsertRowsResult{}, err
+	}
+
+	result, err := w.backend.Append(spreadsheetID, a1Range, values, InputUserEntered, insertRows, w.renderOption)
+	if err != nil {
+		return sheets.InsertRowsResult{}, convertError(err)
+	}
+	return sheets.InsertRowsResult{
+		UpdatedRange:   sheets.NewA1Range(result.Range),
+		UpdatedRows:    result.Rows,
+		UpdatedColumns: result.Columns,
+		UpdatedCells:   result.Cells,
+		InsertedValues: result.Values,
+	}, nil
+}
+
+func (w *Wrapper) UpdateRows(
+	ctx context.Context,
+	spreadsheetID string,
+	a1Range string,
+	values [][]interface{},
+) (sheets.UpdateRowsResult, error) {
+	results, err := w.BatchUpdateRows(ctx, spreadsheetID, []sheets.BatchUpdateRowsRequest{{A1Range: a1Range, Values: values}})
+	if err != nil {
+		return sheets.UpdateRowsResult{}, err
+	}
+	return results[0], nil
+}
+
+func (w *Wrapper) BatchUpdateRows(
+	ctx context.Context,
+	spreadsheetID string,
+	requests []sheets.BatchUpdateRowsRequest,
+) (sheets.BatchUpdateRowsResult, error) {
+	return w.BatchUpdateRowsWithOption(ctx, spreadsheetID, requests, sheets.ValueInputUserEntered)
+}
+
+func (w *Wrapper) BatchUpdateRowsWithOption(
+	ctx context.Context,
+	spreadsheetID string,
+	requests []sheets.BatchUpdateRowsRequest,
+	option sheets.ValueInputOption,
+) (sheets.BatchUpdateRowsResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	data := make([]ValueRange, len(requests))
+	for i, req := range requests {
+		values, err := normalizeValues(req.Values)
+		if err != nil {
+			return nil, err
+		}
+		data[i] = ValueRange{Range: req.A1Range, Values: values}
+	}
+
+	results, err := w.backend.BatchUpdate(spreadsheetID, data, string(option), w.renderOption)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	converted := make(sheets.BatchUpdateRowsResult, len(results))
+	for i, result := range results {
+		converted[i] = sheets.UpdateRowsResult{
+			UpdatedRange:   sheets.NewA1Range(result.Range),
+			UpdatedRows:    result.Rows,
+			UpdatedColumns: result.Columns,
+			UpdatedCells:   result.Cells,
+			UpdatedValues:  result.Values,
+		}
+	}
+	return converted, nil
+}
+
+func (w *Wrapper) QueryRows(
+	ctx context.Context,
+	spreadsheetID string,
+	sheetName string,
+	query string,
+	skipHeader bool,
+) (sheets.QueryRowsResult, error) {
+	if err := ctx.Err(); err != nil {
+		return sheets.QueryRowsResult{}, err
+	}
+
+	headers := 0
+	if skipHeader {
+		headers = 1
+	}
+
+	result, err := w.backend.Query(spreadsheetID, sheetName, query, headers)
+	if err != nil {
+		return sheets.QueryRowsResult{}, convertError(err)
+	}
+
+	converted := sheets.QueryRowsResult{Rows: make([][]interface{}, len(result.Rows))}
+	if len(result.Columns) > 0 {
+		converted.Columns = make([]sheets.QueryRowsColumn, len(result.Columns))
+		for i, col := range result.Columns {
+			converted.Columns[i] = sheets.QueryRowsColumn(col)
+		}
+	}
+
+	for i, row := range result.Rows {
+		converted.Rows[i] = make([]interface{}, len(row))
+		for j, c := range row {
+			switch {
+			case c == nil:
+				converted.Rows[i][j] = nil
+			case c.Value == nil:
+				// The values are omitted when the query uses the "options no_values" clause.
+				converted.Rows[i][j] = c.Formatted
+			default:
+				converted.Rows[i][j] = c.Value
+			}
+		}
+	}
+	return converted, nil
+}
+
+func (w *Wrapper) Clear(ctx context.Context, spreadsheetID string, ranges []string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	cleared, err := w.backend.Clear(spreadsheetID, ranges)
+	return cleared, convertError(err)
+}
+
+// normalizeValues converts the values into the JSON types received by the Google Sheets API,
+// e.g. all numeric types become float64, just like when they are sent by sheets.Wrapper.
+func normalizeValues(values [][]interface{}) ([][]interface{}, error) {
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding values: %w", err)
+	}
+
+	var normalized [][]interface{}
+	if err := json.Unmarshal(raw, &normalized); err != nil {
+		return nil, fmt.Errorf("error decoding values: %w", err)
+	}
+	return normalized, nil
+}
+
+// convertError converts the backend errors into the errors returned by sheets.Wrapper.
+func convertError(err error) error {
+	switch converted := err.(type) {
+	case *Error:
+		return sheets.WrapAPIError(&googleapi.Error{Code: converted.Code, Message: converted.Message})
+	case *QueryError:
+		return sheets.NewQueryError(converted.Reason, converted.Message, converted.DetailedMessage)
+	default:
+		return err
+	}
+}
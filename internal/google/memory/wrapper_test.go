@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapper(t *testing.T) {
+	ctx := context.Background()
+	w := NewWrapper(NewBackend(), sheets.ValueRenderUnformatted)
+
+	id, err := w.CreateSpreadsheet(ctx, "title")
+	assert.Nil(t, err)
+
+	assert.Nil(t, w.CreateSheet(ctx, id, "data"))
+	assert.ErrorIs(t, w.CreateSheet(ctx, id, "data"), sheets.ErrConflict)
+
+	nameToID, err := w.GetSheetNameToID(ctx, id)
+	assert.Nil(t, err)
+	assert.Len(t, nameToID, 2)
+
+	inserted, err := w.OverwriteRows(ctx, id, "data!A1:C", [][]interface{}{
+		{"name", "age", "total"},
+		{"alice", int64(30), "=B2*2"},
+		{"bob", 25, "=B3*2"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "data!A1:C3", inserted.UpdatedRange.Original)
+	assert.Equal(t, []interface{}{"alice", float64(30), float64(60)}, inserted.InsertedValues[1])
+
+	updated, err := w.UpdateRows(ctx, id, "data!B3", [][]interface{}{{26}})
+	assert.Nil(t, err)
+	assert.Equal(t, [][]interface{}{{float64(26)}}, updated.UpdatedValues)
+
+	result, err := w.QueryRows(ctx, id, "data", "select A, C where B > 25 order by B desc", true)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]interface{}{{"alice", float64(60)}, {"bob", float64(52)}}, result.Rows)
+
+	_, err = w.QueryRows(ctx, id, "data", "select Z", true)
+	assert.ErrorIs(t, err, sheets.ErrInvalidQuery)
+
+	_, err = w.QueryRows(ctx, id, "unknown", "select A", true)
+	assert.ErrorIs(t, err, sheets.ErrSheetNotFound)
+
+	_, err = w.Clear(ctx, id, []string{"data!A3:C3"})
+	assert.Nil(t, err)
+
+	result, err = w.QueryRows(ctx, id, "data", "select count(A)", true)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]interface{}{{float64(1)}}, result.Rows)
+
+	assert.Nil(t, w.DeleteSheets(ctx, id, []int64{nameToID["data"]}))
+	assert.NotNil(t, w.DeleteSheets(ctx, id, []int64{nameToID["data"]}))
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = w.GetSheetNameToID(cancelled, id)
+	assert.ErrorIs(t, err, context.Canceled)
+}
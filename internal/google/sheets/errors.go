@@ -0,0 +1,208 @@
+package sheets
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+var (
+	// ErrInvalidQuery is returned when the query is rejected by Google Sheets, e.g. due to a syntax error or
+	// an unknown column.
+	ErrInvalidQuery = errors.New("invalid query")
+
+	// ErrPermissionDenied is returned when the credentials do not have access to the spreadsheet.
+	ErrPermissionDenied = errors.New("permission denied")
+
+	// ErrSpreadsheetNotFound is returned when the spreadsheet does not exist.
+	ErrSpreadsheetNotFound = errors.New("spreadsheet not found")
+
+	// ErrSheetNotFound is returned when the sheet does not exist inside the spreadsheet.
+	ErrSheetNotFound = errors.New("sheet not found")
+
+	// ErrQuotaExceeded is returned when the Google Sheets API read or write quota is exhausted.
+	ErrQuotaExceeded = errors.New("quota exceeded")
+
+	// ErrInvalidRange is returned when the A1 range cannot be parsed by Google Sheets.
+	// Note that Google Sheets also reports a range referring to a non-existent sheet as an invalid range.
+	ErrInvalidRange = errors.New("invalid range")
+
+	// ErrConflict is returned when the request conflicts with the current spreadsheet state,
+	// e.g. when adding a sheet whose name is already taken.
+	ErrConflict = errors.New("conflict")
+)
+
+const (
+	queryStatusError = "error"
+
+	queryReasonInvalidQuery         = "invalid_query"
+	queryReasonAccessDenied         = "access_denied"
+	queryReasonUserNotAuthenticated = "user_not_authenticated"
+	queryReasonUnknownDataSourceID  = "unknown_data_source_id"
+)
+
+// QueryError is returned when the query endpoint returns an error status.
+// Use errors.Is with ErrInvalidQuery, ErrPermissionDenied or ErrSheetNotFound to check the error category.
+type QueryError struct {
+	// Reason is the error reason returned by the query endpoint (e.g. "invalid_query" or "access_denied").
+	Reason string
+
+	// Message is a short description of the error.
+	Message string
+
+	// DetailedMessage is the detailed description of the error, e.g. "Invalid query: NO_COLUMN: C".
+	DetailedMessage string
+
+	err error
+}
+
+func (e *QueryError) Error() string {
+	msg := e.DetailedMessage
+	if msg == "" {
+		msg = e.Message
+	}
+	if e.err != nil {
+		return fmt.Sprintf("%s: %s (%s)", e.err, msg, e.Reason)
+	}
+	return fmt.Sprintf("query error: %s (%s)", msg, e.Reason)
+}
+
+func (e *QueryError) Unwrap() error {
+	return e.err
+}
+
+// NewQueryError creates a QueryError categorised based on the reason, e.g. for backends other than the Wrapper.
+func NewQueryError(reason string, message string, detailedMessage string) *QueryError {
+	return newQueryError(rawQueryRowsResultMessage{Reason: reason, Message: message, DetailedMessage: detailedMessage})
+}
+
+func newQueryError(raw rawQueryRowsResultMessage) *QueryError {
+	return &QueryError{
+		Reason:          raw.Reason,
+		Message:         raw.Message,
+		DetailedMessage: raw.DetailedMessage,
+		err:             queryReasonToError(raw.Reason),
+	}
+}
+
+func queryReasonToError(reason string) error {
+	switch strings.ToLower(reason) {
+	case queryReasonInvalidQuery:
+		return ErrInvalidQuery
+	case queryReasonAccessDenied, queryReasonUserNotAuthenticated:
+		return ErrPermissionDenied
+	case queryReasonUnknownDataSourceID:
+		return ErrSheetNotFound
+	default:
+		return nil
+	}
+}
+
+// queryStatusToError converts a non-successful HTTP status code returned by the query endpoint into an *APIError.
+func queryStatusToError(resp *http.Response, body string) error {
+	var kind error
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		kind = ErrPermissionDenied
+	case http.StatusNotFound:
+		kind = ErrSpreadsheetNotFound
+	case http.StatusTooManyRequests:
+		kind = ErrQuotaExceeded
+	}
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    body,
+		RetryAfter: parseRetryAfter(resp.Header),
+		kind:       kind,
+		err:        fmt.Errorf("query endpoint returned HTTP %d: %s", resp.StatusCode, body),
+	}
+}
+
+// APIError is returned when a Google Sheets API call fails.
+// Use errors.Is with one of the sentinel errors (e.g. ErrQuotaExceeded) to check the error category,
+// or errors.As with *googleapi.Error to access the original error.
+type APIError struct {
+	// StatusCode is the HTTP status code returned by the Google Sheets API.
+	StatusCode int
+
+	// Message is the error message returned by the Google Sheets API.
+	Message string
+
+	// RetryAfter is the delay requested by the server through the Retry-After header, if any.
+	RetryAfter time.Duration
+
+	kind error
+	err  error
+}
+
+func (e *APIError) Error() string {
+	if e.kind == nil {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.kind, e.err)
+}
+
+func (e *APIError) Is(target error) bool {
+	return e.kind != nil && e.kind == target
+}
+
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// WrapAPIError converts a *googleapi.Error into an *APIError categorised by the sentinel errors, just like
+// the Wrapper does. Any other error is returned as is.
+func WrapAPIError(err error) error {
+	return wrapAPIError(err)
+}
+
+// wrapAPIError converts the error returned by the Google Sheets API client into an *APIError.
+// Errors not coming from the Google Sheets API (e.g. a cancelled context) are returned as is.
+func wrapAPIError(err error) error {
+	var apiErr *googleapi.Error
+	if err == nil || !errors.As(err, &apiErr) {
+		return err
+	}
+	return &APIError{
+		StatusCode: apiErr.Code,
+		Message:    apiErr.Message,
+		RetryAfter: parseRetryAfter(apiErr.Header),
+		kind:       apiErrorKind(apiErr),
+		err:        err,
+	}
+}
+
+func apiErrorKind(err *googleapi.Error) error {
+	for _, item := range err.Errors {
+		switch item.Reason {
+		case "rateLimitExceeded", "userRateLimitExceeded", "quotaExceeded":
+			return ErrQuotaExceeded
+		}
+	}
+
+	msg := strings.ToLower(err.Message)
+	switch err.Code {
+	case http.StatusTooManyRequests:
+		return ErrQuotaExceeded
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrPermissionDenied
+	case http.StatusNotFound:
+		return ErrSpreadsheetNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusBadRequest:
+		switch {
+		case strings.Contains(msg, "unable to parse range"):
+			return ErrInvalidRange
+		case strings.Contains(msg, "no grid with id"):
+			return ErrSheetNotFound
+		case strings.Contains(msg, "already exists"):
+			return ErrConflict
+		}
+	}
+	return nil
+}
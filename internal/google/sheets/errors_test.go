@@ -0,0 +1,96 @@
+package sheets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/googleapi"
+)
+
+func TestWrapAPIError(t *testing.T) {
+	tc := []struct {
+		name     string
+		input    *googleapi.Error
+		expected error
+	}{
+		{
+			name:     "too_many_requests",
+			input:    &googleapi.Error{Code: http.StatusTooManyRequests},
+			expected: ErrQuotaExceeded,
+		},
+		{
+			name: "rate_limit_reason",
+			input: &googleapi.Error{
+				Code:   http.StatusForbidden,
+				Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}},
+			},
+			expected: ErrQuotaExceeded,
+		},
+		{
+			name:     "forbidden",
+			input:    &googleapi.Error{Code: http.StatusForbidden, Message: "The caller does not have permission"},
+			expected: ErrPermissionDenied,
+		},
+		{
+			name:     "unauthorized",
+			input:    &googleapi.Error{Code: http.StatusUnauthorized},
+			expected: ErrPermissionDenied,
+		},
+		{
+			name:     "not_found",
+			input:    &googleapi.Error{Code: http.StatusNotFound, Message: "Requested entity was not found."},
+			expected: ErrSpreadsheetNotFound,
+		},
+		{
+			name:     "invalid_range",
+			input:    &googleapi.Error{Code: http.StatusBadRequest, Message: "Unable to parse range: Sheet1!A"},
+			expected: ErrInvalidRange,
+		},
+		{
+			name:     "sheet_not_found",
+			input:    &googleapi.Error{Code: http.StatusBadRequest, Message: "Invalid requests[0].deleteSheet: No grid with id: 1"},
+			expected: ErrSheetNotFound,
+		},
+		{
+			name:     "sheet_already_exists",
+			input:    &googleapi.Error{Code: http.StatusBadRequest, Message: `A sheet with the name "s1" already exists.`},
+			expected: ErrConflict,
+		},
+		{
+			name:     "conflict",
+			input:    &googleapi.Error{Code: http.StatusConflict},
+			expected: ErrConflict,
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			err := wrapAPIError(fmt.Errorf("wrapped: %w", c.input))
+			assert.ErrorIs(t, err, c.expected)
+
+			var googleErr *googleapi.Error
+			assert.True(t, errors.As(err, &googleErr))
+			assert.Equal(t, c.input, googleErr)
+		})
+	}
+
+	t.Run("unknown_api_error", func(t *testing.T) {
+		input := &googleapi.Error{Code: http.StatusInternalServerError, Message: "internal error"}
+		err := wrapAPIError(input)
+
+		var apiErr *APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+		assert.Equal(t, input.Error(), err.Error())
+		assert.NotErrorIs(t, err, ErrQuotaExceeded)
+	})
+
+	t.Run("non_api_error", func(t *testing.T) {
+		assert.Nil(t, wrapAPIError(nil))
+		assert.Equal(t, context.Canceled, wrapAPIError(context.Canceled))
+	})
+}
@@ -2,11 +2,24 @@ package sheets
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type appendMode string
 
+// ValueRenderOption defines how the values returned by the Google Sheets API should be rendered.
+type ValueRenderOption string
+
+// ValueInputOption defines how the values written into Google Sheets should be interpreted.
+type ValueInputOption string
+
+// DateTimeRenderOption defines how dates, times and durations returned by the Google Sheets API should be rendered.
+// It is ignored if the ValueRenderOption is ValueRenderFormatted.
+type DateTimeRenderOption string
+
 const (
 	majorDimensionRows                      = "ROWS"
 	valueInputUserEntered                   = "USER_ENTERED"
@@ -14,9 +27,114 @@ const (
 	appendModeInsert             appendMode = "INSERT_ROWS"
 	appendModeOverwrite          appendMode = "OVERWRITE"
 
-	queryRowsURLTemplate = "https://docs.google.com/spreadsheets/d/%s/gviz/tq"
+	defaultQueryEndpoint  = "https://docs.google.com"
+	queryRowsPathTemplate = "/spreadsheets/d/%s/gviz/tq"
+
+	// ValueInputUserEntered parses the values as if they were typed into the Google Sheets UI.
+	// For example, "=ROW()" is evaluated as a formula and "2020-01-01" is converted into a date.
+	ValueInputUserEntered ValueInputOption = valueInputUserEntered
+
+	// ValueInputRaw stores the values as-is without any parsing.
+	ValueInputRaw ValueInputOption = "RAW"
+
+	// ValueRenderFormatted renders values as they are displayed in the Google Sheets UI.
+	// Note that the result depends on the spreadsheet locale (e.g. "1.234,5" instead of "1234.5").
+	ValueRenderFormatted ValueRenderOption = responseValueRenderFormatted
+
+	// ValueRenderUnformatted renders values without any formatting, independent of the spreadsheet locale.
+	ValueRenderUnformatted ValueRenderOption = "UNFORMATTED_VALUE"
+
+	// ValueRenderFormula renders the formula of a cell instead of its calculated value.
+	ValueRenderFormula ValueRenderOption = "FORMULA"
+
+	// DateTimeRenderSerialNumber renders dates, times and durations as a serial number (days since 30 December 1899).
+	DateTimeRenderSerialNumber DateTimeRenderOption = "SERIAL_NUMBER"
+
+	// DateTimeRenderFormattedString renders dates, times and durations based on the cell number format.
+	DateTimeRenderFormattedString DateTimeRenderOption = "FORMATTED_STRING"
+
+	secondsPerDay = 24 * 60 * 60
 )
 
+var (
+	serialNumberEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+	queryDateRegex    = regexp.MustCompile(`^Date\(([\d,\s]+)\)$`)
+)
+
+// WrapperConfig defines a list of configurations that can be used to customise how the Wrapper works.
+type WrapperConfig struct {
+	// ValueRenderOption defines how the values returned by the Google Sheets API are rendered.
+	// The default value is ValueRenderFormatted.
+	//
+	// If it is set to ValueRenderUnformatted, the date, datetime and timeofday values returned by QueryRows
+	// are also rendered based on DateTimeRenderOption instead of the spreadsheet locale.
+	ValueRenderOption ValueRenderOption
+
+	// DateTimeRenderOption defines how dates, times and durations are rendered.
+	// The default value is DateTimeRenderSerialNumber.
+	DateTimeRenderOption DateTimeRenderOption
+
+	// RetryPolicy defines how failed API calls are retried.
+	// The default value disables retries.
+	RetryPolicy RetryPolicy
+
+	// RateLimiter limits the number of API calls, including the retried ones.
+	// It can be shared by multiple wrappers. The default value (nil) disables rate limiting.
+	RateLimiter *RateLimiter
+
+	// Endpoints overrides the Google Sheets API and query endpoints, e.g. to target a local emulator.
+	Endpoints Endpoints
+}
+
+// Endpoints defines the base URLs used by the Wrapper.
+// An empty value uses the default Google endpoint.
+type Endpoints struct {
+	// API is the base URL of the Google Sheets API v4, e.g. "http://localhost:8080/".
+	// The default value is "https://sheets.googleapis.com/".
+	API string
+
+	// Query is the base URL of the Google Visualization query endpoint used by QueryRows,
+	// e.g. "http://localhost:8080". The request path "/spreadsheets/d/<id>/gviz/tq" is appended to it.
+	// The default value is "https://docs.google.com".
+	Query string
+}
+
+func (e Endpoints) queryRowsURL(spreadsheetID string) string {
+	base := e.Query
+	if base == "" {
+		base = defaultQueryEndpoint
+	}
+	return strings.TrimSuffix(base, "/") + fmt.Sprintf(queryRowsPathTemplate, spreadsheetID)
+}
+
+func (e Endpoints) apiEndpoint() string {
+	if e.API == "" || strings.HasSuffix(e.API, "/") {
+		return e.API
+	}
+	// The Google API client resolves the request paths relative to the endpoint,
+	// so a missing trailing slash drops the last path segment.
+	return e.API + "/"
+}
+
+func (c WrapperConfig) valueRenderOption() string {
+	if c.ValueRenderOption == "" {
+		return string(ValueRenderFormatted)
+	}
+	return string(c.ValueRenderOption)
+}
+
+func (c WrapperConfig) dateTimeRenderOption() string {
+	if c.DateTimeRenderOption == "" {
+		return string(DateTimeRenderSerialNumber)
+	}
+	return string(c.DateTimeRenderOption)
+}
+
+func (c WrapperConfig) isSerialDateTime() bool {
+	return c.valueRenderOption() != string(ValueRenderFormatted) &&
+		c.dateTimeRenderOption() == string(DateTimeRenderSerialNumber)
+}
+
 type A1Range struct {
 	Original  string
 	SheetName string
@@ -107,18 +225,62 @@ type BatchUpdateRowsResult []UpdateRowsResult
 }
 */
 type rawQueryRowsResult struct {
-	Table rawQueryRowsResultTable `json:"table"`
+	Status   string                      `json:"status"`
+	Warnings []rawQueryRowsResultMessage `json:"warnings"`
+	Errors   []rawQueryRowsResultMessage `json:"errors"`
+	Table    rawQueryRowsResultTable     `json:"table"`
+}
+
+/*
+{
+	"version":"0.6",
+	"reqId":"0",
+	"status":"error",
+	"errors":[
+		{"reason":"invalid_query","message":"INVALID_QUERY","detailed_message":"Invalid query: NO_COLUMN: C"}
+	]
+}
+*/
+type rawQueryRowsResultMessage struct {
+	Reason          string `json:"reason"`
+	Message         string `json:"message"`
+	DetailedMessage string `json:"detailed_message"`
 }
 
-func (r rawQueryRowsResult) toQueryRowsResult() (QueryRowsResult, error) {
+// toError returns the first error returned by the query endpoint, if the status is an error.
+func (r rawQueryRowsResult) toError() error {
+	if r.Status != queryStatusError {
+		return nil
+	}
+	if len(r.Errors) == 0 {
+		return &QueryError{Reason: "unknown", Message: "query endpoint returned an error status without any details"}
+	}
+	return newQueryError(r.Errors[0])
+}
+
+func (r rawQueryRowsResult) toQueryRowsResult(config WrapperConfig) (QueryRowsResult, error) {
 	result := QueryRowsResult{
 		Rows: make([][]interface{}, len(r.Table.Rows)),
 	}
 
+	if len(r.Warnings) > 0 {
+		result.Warnings = make([]QueryWarning, len(r.Warnings))
+		for i, w := range r.Warnings {
+			result.Warnings[i] = QueryWarning(w)
+		}
+	}
+
+	if len(r.Table.Cols) > 0 {
+		result.Columns = make([]QueryRowsColumn, len(r.Table.Cols))
+		for i, col := range r.Table.Cols {
+			result.Columns[i] = QueryRowsColumn(col)
+		}
+	}
+
 	for rowIdx, row := range r.Table.Rows {
 		result.Rows[rowIdx] = make([]interface{}, len(row.Cells))
 		for cellIdx, cell := range row.Cells {
-			val, err := r.convertRawValue(cellIdx, cell)
+			val, err := r.convertRawValue(cellIdx, cell, config)
 			if err != nil {
 				return QueryRowsResult{}, err
 			}
@@ -129,8 +291,19 @@ func (r rawQueryRowsResult) toQueryRowsResult() (QueryRowsResult, error) {
 	return result, nil
 }
 
-func (r rawQueryRowsResult) convertRawValue(cellIdx int, cell rawQueryRowsResultCell) (interface{}, error) {
+func (r rawQueryRowsResult) convertRawValue(
+	cellIdx int,
+	cell rawQueryRowsResultCell,
+	config WrapperConfig,
+) (interface{}, error) {
 	col := r.Table.Cols[cellIdx]
+
+	// The values are omitted when the query uses the "options no_values" clause.
+	// In that case, the formatted value is the only available value.
+	if cell.Value == nil && cell.Raw != "" {
+		return cell.Raw, nil
+	}
+
 	switch col.Type {
 	case "boolean":
 		return cell.Value, nil
@@ -140,19 +313,99 @@ func (r rawQueryRowsResult) convertRawValue(cellIdx int, cell rawQueryRowsResult
 		// `string` type does not have the raw value
 		return cell.Value, nil
 	case "date", "datetime", "timeofday":
+		// The formatted values are omitted when the query uses the "options no_format" clause.
+		if config.isSerialDateTime() || (cell.Raw == "" && cell.Value != nil) {
+			return convertQuerySerialDateTime(cell.Value)
+		}
 		return cell.Raw, nil
 	}
 	return nil, fmt.Errorf("unsupported cell value: %s", col.Type)
 }
 
+// convertQuerySerialDateTime converts the locale independent raw date and time values returned by the query
+// endpoint into a serial number, similar to the one returned by the Google Sheets API.
+//
+// The dates are returned as "Date(year,month,day[,hour,minute,second[,millisecond]])" with a zero-based month.
+// The times of day are returned as [hour, minute, second, millisecond].
+func convertQuerySerialDateTime(value interface{}) (interface{}, error) {
+	switch converted := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return parseQueryDate(converted)
+	case []interface{}:
+		return parseQueryTimeOfDay(converted)
+	default:
+		return nil, fmt.Errorf("unsupported date time value: %v", value)
+	}
+}
+
+func parseQueryDate(value string) (float64, error) {
+	matches := queryDateRegex.FindStringSubmatch(value)
+	if matches == nil {
+		return 0, fmt.Errorf("unsupported date value: %s", value)
+	}
+
+	parts := strings.Split(matches[1], ",")
+	if len(parts) < 3 {
+		return 0, fmt.Errorf("unsupported date value: %s", value)
+	}
+
+	// year, month, day, hour, minute, second, millisecond
+	components := make([]int, 7)
+	for i, part := range parts {
+		if i >= len(components) {
+			return 0, fmt.Errorf("unsupported date value: %s", value)
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return 0, fmt.Errorf("unsupported date value: %s, %w", value, err)
+		}
+		components[i] = n
+	}
+
+	t := time.Date(
+		components[0],
+		time.Month(components[1]+1),
+		components[2],
+		components[3],
+		components[4],
+		components[5],
+		components[6]*int(time.Millisecond),
+		time.UTC,
+	)
+	return t.Sub(serialNumberEpoch).Seconds() / secondsPerDay, nil
+}
+
+func parseQueryTimeOfDay(value []interface{}) (float64, error) {
+	// hour, minute, second, millisecond
+	multipliers := []float64{60 * 60, 60, 1, 0.001}
+	if len(value) < 3 || len(value) > len(multipliers) {
+		return 0, fmt.Errorf("unsupported time of day value: %v", value)
+	}
+
+	seconds := 0.0
+	for i, part := range value {
+		n, ok := part.(float64)
+		if !ok {
+			return 0, fmt.Errorf("unsupported time of day value: %v", value)
+		}
+		seconds += n * multipliers[i]
+	}
+	return seconds / secondsPerDay, nil
+}
+
 type rawQueryRowsResultTable struct {
 	Cols []rawQueryRowsResultColumn `json:"cols"`
 	Rows []rawQueryRowsResultRow    `json:"rows"`
 }
 
 type rawQueryRowsResultColumn struct {
-	ID   string `json:"id"`
-	Type string `json:"type"`
+	ID      string `json:"id"`
+	Label   string `json:"label"`
+	Type    string `json:"type"`
+	Pattern string `json:"pattern"`
 }
 
 type rawQueryRowsResultRow struct {
@@ -164,6 +417,37 @@ type rawQueryRowsResultCell struct {
 	Raw   string      `json:"f"`
 }
 
+// QueryRowsColumn describes a column returned by the query endpoint.
+type QueryRowsColumn struct {
+	// ID is the column letter (e.g. "A") or the expression for computed columns (e.g. "upper(A)").
+	ID string
+
+	// Label is the column label, either from the header row or from the "label" clause.
+	Label string
+
+	// Type is one of "boolean", "number", "string", "date", "datetime" and "timeofday".
+	Type string
+
+	// Pattern is the number format pattern of the column (e.g. "General" or "yyyy-mm-dd"), if any.
+	Pattern string
+}
+
+// GridSize defines the number of rows and columns of a sheet.
+// A zero value uses the Google Sheets default (1000 rows and 26 columns).
+type GridSize struct {
+	RowCount    int64
+	ColumnCount int64
+}
+
+// QueryWarning describes a warning returned by the query endpoint, e.g. when the result is truncated.
+type QueryWarning struct {
+	Reason          string
+	Message         string
+	DetailedMessage string
+}
+
 type QueryRowsResult struct {
-	Rows [][]interface{}
+	Rows     [][]interface{}
+	Columns  []QueryRowsColumn
+	Warnings []QueryWarning
 }
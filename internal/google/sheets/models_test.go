@@ -74,9 +74,12 @@ func TestRawQueryRowsResult_toQueryRowsResult(t *testing.T) {
 			},
 		}
 
-		expected := QueryRowsResult{Rows: make([][]interface{}, 0)}
+		expected := QueryRowsResult{
+			Rows:    make([][]interface{}, 0),
+			Columns: []QueryRowsColumn{{ID: "A", Type: "number"}, {ID: "B", Type: "string"}},
+		}
 
-		result, err := r.toQueryRowsResult()
+		result, err := r.toQueryRowsResult(WrapperConfig{})
 		assert.Nil(t, err)
 		assert.Equal(t, expected, result)
 	})
@@ -121,9 +124,14 @@ func TestRawQueryRowsResult_toQueryRowsResult(t *testing.T) {
 				{float64(456), "blah2", false},
 				{123.1, "blah", true},
 			},
+			Columns: []QueryRowsColumn{
+				{ID: "A", Type: "number"},
+				{ID: "B", Type: "string"},
+				{ID: "C", Type: "boolean"},
+			},
 		}
 
-		result, err := r.toQueryRowsResult()
+		result, err := r.toQueryRowsResult(WrapperConfig{})
 		assert.Nil(t, err)
 		assert.Equal(t, expected, result)
 	})
@@ -162,8 +170,99 @@ func TestRawQueryRowsResult_toQueryRowsResult(t *testing.T) {
 			},
 		}
 
-		result, err := r.toQueryRowsResult()
+		result, err := r.toQueryRowsResult(WrapperConfig{})
 		assert.Equal(t, QueryRowsResult{}, result)
 		assert.NotNil(t, err)
 	})
 }
+
+func TestRawQueryRowsResult_toQueryRowsResult_DateTime(t *testing.T) {
+	r := rawQueryRowsResult{
+		Table: rawQueryRowsResultTable{
+			Cols: []rawQueryRowsResultColumn{
+				{ID: "A", Type: "date"},
+				{ID: "B", Type: "datetime"},
+				{ID: "C", Type: "timeofday"},
+			},
+			Rows: []rawQueryRowsResultRow{
+				{
+					[]rawQueryRowsResultCell{
+						{Value: "Date(2020,0,1)", Raw: "1.1.2020"},
+						{Value: "Date(2020,0,1,12,0,0)", Raw: "1.1.2020 12:00:00"},
+						{Value: []interface{}{18.0, 0.0, 0.0, 0.0}, Raw: "18:00:00"},
+					},
+				},
+				{
+					[]rawQueryRowsResultCell{
+						{},
+						{},
+						{},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("formatted", func(t *testing.T) {
+		result, err := r.toQueryRowsResult(WrapperConfig{})
+		assert.Nil(t, err)
+		assert.Equal(t, []interface{}{"1.1.2020", "1.1.2020 12:00:00", "18:00:00"}, result.Rows[0])
+	})
+
+	t.Run("formatted_string", func(t *testing.T) {
+		result, err := r.toQueryRowsResult(WrapperConfig{
+			ValueRenderOption:    ValueRenderUnformatted,
+			DateTimeRenderOption: DateTimeRenderFormattedString,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []interface{}{"1.1.2020", "1.1.2020 12:00:00", "18:00:00"}, result.Rows[0])
+	})
+
+	t.Run("serial_number", func(t *testing.T) {
+		result, err := r.toQueryRowsResult(WrapperConfig{ValueRenderOption: ValueRenderUnformatted})
+		assert.Nil(t, err)
+		assert.Equal(t, []interface{}{float64(43831), 43831.5, 0.75}, result.Rows[0])
+		assert.Equal(t, []interface{}{nil, nil, nil}, result.Rows[1])
+	})
+
+	t.Run("invalid_date", func(t *testing.T) {
+		invalid := rawQueryRowsResult{
+			Table: rawQueryRowsResultTable{
+				Cols: []rawQueryRowsResultColumn{{ID: "A", Type: "date"}},
+				Rows: []rawQueryRowsResultRow{{[]rawQueryRowsResultCell{{Value: "2020-01-01"}}}},
+			},
+		}
+
+		result, err := invalid.toQueryRowsResult(WrapperConfig{ValueRenderOption: ValueRenderUnformatted})
+		assert.NotNil(t, err)
+		assert.Equal(t, QueryRowsResult{}, result)
+	})
+}
+
+func TestRawQueryRowsResult_toQueryRowsResult_Options(t *testing.T) {
+	t.Run("no_values", func(t *testing.T) {
+		r := rawQueryRowsResult{
+			Table: rawQueryRowsResultTable{
+				Cols: []rawQueryRowsResultColumn{{ID: "A", Type: "number"}, {ID: "B", Type: "boolean"}},
+				Rows: []rawQueryRowsResultRow{{[]rawQueryRowsResultCell{{Raw: "1,234.5"}, {Raw: "TRUE"}}}},
+			},
+		}
+
+		result, err := r.toQueryRowsResult(WrapperConfig{})
+		assert.Nil(t, err)
+		assert.Equal(t, [][]interface{}{{"1,234.5", "TRUE"}}, result.Rows)
+	})
+
+	t.Run("no_format", func(t *testing.T) {
+		r := rawQueryRowsResult{
+			Table: rawQueryRowsResultTable{
+				Cols: []rawQueryRowsResultColumn{{ID: "A", Type: "number"}, {ID: "B", Type: "date"}},
+				Rows: []rawQueryRowsResultRow{{[]rawQueryRowsResultCell{{Value: 1234.5}, {Value: "Date(2020,0,1)"}}}},
+			},
+		}
+
+		result, err := r.toQueryRowsResult(WrapperConfig{})
+		assert.Nil(t, err)
+		assert.Equal(t, [][]interface{}{{1234.5, float64(43831)}}, result.Rows)
+	})
+}
@@ -0,0 +1,189 @@
+package sheets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// The default Google Sheets API quota is 60 read and 60 write requests per minute per user.
+	defaultReadRequestsPerMinute  = 60
+	defaultWriteRequestsPerMinute = 60
+)
+
+// ErrRateLimitExceeded is returned by a fail fast RateLimiter when there is no remaining request budget.
+var ErrRateLimitExceeded = errors.New("client side rate limit exceeded")
+
+type requestKind int
+
+const (
+	requestRead requestKind = iota
+	requestWrite
+)
+
+func (k requestKind) String() string {
+	if k == requestWrite {
+		return "write"
+	}
+	return "read"
+}
+
+// RateLimiterConfig defines a list of configurations that can be used to customise how the RateLimiter works.
+type RateLimiterConfig struct {
+	// ReadRequestsPerMinute is the number of read requests (including queries) allowed per minute.
+	// The default value is 60, matching the default Google Sheets API quota per user.
+	ReadRequestsPerMinute int
+
+	// WriteRequestsPerMinute is the number of write requests allowed per minute.
+	// The default value is 60, matching the default Google Sheets API quota per user.
+	WriteRequestsPerMinute int
+
+	// ReadBurst is the maximum number of read requests that can be sent at once.
+	// The default value is ReadRequestsPerMinute.
+	ReadBurst int
+
+	// WriteBurst is the maximum number of write requests that can be sent at once.
+	// The default value is WriteRequestsPerMinute.
+	WriteBurst int
+
+	// FailFast makes the RateLimiter return ErrRateLimitExceeded immediately when the budget is exhausted,
+	// instead of waiting until a request is allowed.
+	FailFast bool
+}
+
+// RateLimiter limits the number of Google Sheets API requests using a token bucket for reads and writes separately.
+//
+// A single RateLimiter can be shared by multiple stores, so that they share the same per-user quota.
+// It is safe for concurrent use.
+type RateLimiter struct {
+	read     *tokenBucket
+	write    *tokenBucket
+	failFast bool
+}
+
+// NewRateLimiter creates a RateLimiter with the given configuration.
+func NewRateLimiter(config RateLimiterConfig) *RateLimiter {
+	if config.ReadRequestsPerMinute <= 0 {
+		config.ReadRequestsPerMinute = defaultReadRequestsPerMinute
+	}
+	if config.WriteRequestsPerMinute <= 0 {
+		config.WriteRequestsPerMinute = defaultWriteRequestsPerMinute
+	}
+	if config.ReadBurst <= 0 {
+		config.ReadBurst = config.ReadRequestsPerMinute
+	}
+	if config.WriteBurst <= 0 {
+		config.WriteBurst = config.WriteRequestsPerMinute
+	}
+
+	return &RateLimiter{
+		read:     newTokenBucket(config.ReadRequestsPerMinute, config.ReadBurst, time.Now),
+		write:    newTokenBucket(config.WriteRequestsPerMinute, config.WriteBurst, time.Now),
+		failFast: config.FailFast,
+	}
+}
+
+// wait blocks until a request of the given kind is allowed.
+// It is a no-op if the RateLimiter is nil.
+func (r *RateLimiter) wait(ctx context.Context, kind requestKind) error {
+	if r == nil {
+		return nil
+	}
+
+	bucket := r.read
+	if kind == requestWrite {
+		bucket = r.write
+	}
+
+	if r.failFast {
+		if !bucket.take() {
+			return fmt.Errorf("%w: no remaining %s request budget", ErrRateLimitExceeded, kind)
+		}
+		return nil
+	}
+
+	delay := bucket.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		bucket.cancel()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type tokenBucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	capacity float64
+	interval time.Duration
+	last     time.Time
+	now      func() time.Time
+}
+
+func newTokenBucket(perMinute int, burst int, now func() time.Time) *tokenBucket {
+	return &tokenBucket{
+		tokens:   float64(burst),
+		capacity: float64(burst),
+		interval: time.Minute / time.Duration(perMinute),
+		last:     now(),
+		now:      now,
+	}
+}
+
+// refill must be called with the mutex held.
+func (b *tokenBucket) refill() {
+	now := b.now()
+	b.tokens += float64(now.Sub(b.last)) / float64(b.interval)
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+}
+
+// take consumes a token if there is one available.
+func (b *tokenBucket) take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// reserve consumes a token and returns how long the caller has to wait until the token is available.
+// The token can be returned by calling cancel if the caller gives up waiting.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens * float64(b.interval))
+}
+
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens++
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+}
@@ -0,0 +1,112 @@
+package sheets
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/FreeLeh/GoFreeDB/google/auth"
+	"github.com/FreeLeh/GoFreeDB/internal/google/fixtures"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	t.Run("take", func(t *testing.T) {
+		bucket := newTokenBucket(60, 2, clock)
+		assert.True(t, bucket.take())
+		assert.True(t, bucket.take())
+		assert.False(t, bucket.take())
+
+		now = now.Add(time.Second)
+		assert.True(t, bucket.take())
+		assert.False(t, bucket.take())
+
+		now = now.Add(time.Hour)
+		assert.True(t, bucket.take())
+		assert.True(t, bucket.take())
+		assert.False(t, bucket.take(), "tokens must not exceed the bucket capacity")
+	})
+
+	t.Run("reserve", func(t *testing.T) {
+		bucket := newTokenBucket(60, 1, clock)
+		assert.Equal(t, time.Duration(0), bucket.reserve())
+		assert.Equal(t, time.Second, bucket.reserve())
+		assert.Equal(t, 2*time.Second, bucket.reserve())
+
+		bucket.cancel()
+		assert.Equal(t, 2*time.Second, bucket.reserve())
+	})
+}
+
+func TestRateLimiter_wait(t *testing.T) {
+	t.Run("nil_limiter", func(t *testing.T) {
+		var limiter *RateLimiter
+		assert.Nil(t, limiter.wait(context.Background(), requestWrite))
+	})
+
+	t.Run("fail_fast", func(t *testing.T) {
+		limiter := NewRateLimiter(RateLimiterConfig{
+			ReadRequestsPerMinute:  1,
+			WriteRequestsPerMinute: 1,
+			FailFast:               true,
+		})
+
+		assert.Nil(t, limiter.wait(context.Background(), requestRead))
+		assert.ErrorIs(t, limiter.wait(context.Background(), requestRead), ErrRateLimitExceeded)
+
+		// The write budget is independent of the read budget.
+		assert.Nil(t, limiter.wait(context.Background(), requestWrite))
+		assert.ErrorIs(t, limiter.wait(context.Background(), requestWrite), ErrRateLimitExceeded)
+	})
+
+	t.Run("queue", func(t *testing.T) {
+		limiter := NewRateLimiter(RateLimiterConfig{ReadRequestsPerMinute: 6000, ReadBurst: 1})
+
+		start := time.Now()
+		assert.Nil(t, limiter.wait(context.Background(), requestRead))
+		assert.Nil(t, limiter.wait(context.Background(), requestRead))
+		assert.GreaterOrEqual(t, time.Since(start), 5*time.Millisecond)
+	})
+
+	t.Run("context_cancelled", func(t *testing.T) {
+		limiter := NewRateLimiter(RateLimiterConfig{ReadRequestsPerMinute: 1})
+		assert.Nil(t, limiter.wait(context.Background(), requestRead))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, limiter.wait(ctx, requestRead), context.DeadlineExceeded)
+	})
+}
+
+func TestWrapper_RateLimiter(t *testing.T) {
+	path := fixtures.PathToFixture("service_account.json")
+
+	auth, err := auth.NewServiceFromFile(path, []string{}, auth.ServiceConfig{})
+	assert.Nil(t, err, "should not have any error instantiating a new service account client")
+
+	limiter := NewRateLimiter(RateLimiterConfig{ReadRequestsPerMinute: 1, FailFast: true})
+	wrapper1, err := NewWrapperWithConfig(auth, WrapperConfig{RateLimiter: limiter})
+	assert.Nil(t, err, "should not have any error instantiating a new sheets wrapper")
+	wrapper2, err := NewWrapperWithConfig(auth, WrapperConfig{RateLimiter: limiter})
+	assert.Nil(t, err, "should not have any error instantiating a new sheets wrapper")
+
+	gock.InterceptClient(auth.HTTPClient())
+
+	gock.New("https://sheets.googleapis.com").
+		Get("/v4/spreadsheets/123").
+		Reply(http.StatusOK).
+		JSON(map[string]interface{}{"sheets": []interface{}{}})
+
+	_, err = wrapper1.GetSheetNameToID(context.Background(), "123")
+	assert.Nil(t, err)
+
+	_, err = wrapper2.GetSheetNameToID(context.Background(), "123")
+	assert.True(t, errors.Is(err, ErrRateLimitExceeded), "the budget must be shared across wrappers")
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,181 @@
+package sheets
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRetryMaxAttempts    = 5
+	defaultRetryInitialBackoff = time.Second
+	defaultRetryMaxBackoff     = 32 * time.Second
+	defaultRetryMultiplier     = 2
+	defaultRetryJitter         = 0.2
+)
+
+// RetryPolicy defines how failed Google Sheets API calls are retried.
+//
+// The zero value disables retries, i.e. every call is attempted exactly once.
+// Use DefaultRetryPolicy for a policy following the Google Sheets API recommendation (truncated exponential backoff).
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts for a single call, including the first one.
+	// A value of 0 or 1 disables retries.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry. The default value is 1 second.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the upper bound of the delay between 2 attempts. The default value is 32 seconds.
+	// Note that a longer delay requested by the server through the Retry-After header is still respected.
+	MaxBackoff time.Duration
+
+	// Multiplier is the factor the delay is multiplied with after every attempt. The default value is 2.
+	Multiplier float64
+
+	// Jitter is the fraction of the delay that is randomised, e.g. 0.2 means the delay is randomly
+	// increased or decreased by up to 20%. The value must be between 0 and 1.
+	Jitter float64
+
+	// IsRetryable decides whether a failed call can be retried.
+	// By default, quota errors (HTTP 429), HTTP 500, 502, 503 and 504 are retried.
+	IsRetryable func(err error) bool
+
+	// RetryNonIdempotent allows retrying calls that may be applied more than once, e.g. appending rows.
+	//
+	// By default, such calls are only retried when the quota is exceeded, as the request is rejected before
+	// it is processed. Other errors (e.g. HTTP 503) may be returned after the rows are appended, so retrying
+	// them may result in duplicated rows.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a retry policy with 5 attempts and a truncated exponential backoff
+// starting from 1 second up to 32 seconds.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    defaultRetryMaxAttempts,
+		InitialBackoff: defaultRetryInitialBackoff,
+		MaxBackoff:     defaultRetryMaxBackoff,
+		Multiplier:     defaultRetryMultiplier,
+		Jitter:         defaultRetryJitter,
+	}
+}
+
+func (p RetryPolicy) initialBackoff() time.Duration {
+	if p.InitialBackoff <= 0 {
+		return defaultRetryInitialBackoff
+	}
+	return p.InitialBackoff
+}
+
+func (p RetryPolicy) maxBackoff() time.Duration {
+	if p.MaxBackoff <= 0 {
+		return defaultRetryMaxBackoff
+	}
+	return p.MaxBackoff
+}
+
+func (p RetryPolicy) multiplier() float64 {
+	if p.Multiplier < 1 {
+		return defaultRetryMultiplier
+	}
+	return p.Multiplier
+}
+
+func (p RetryPolicy) isRetryable(err error, idempotent bool) bool {
+	if !idempotent && !p.RetryNonIdempotent {
+		return errors.Is(err, ErrQuotaExceeded)
+	}
+	if p.IsRetryable != nil {
+		return p.IsRetryable(err)
+	}
+	return IsRetryableError(err)
+}
+
+// backoff returns the delay before the given retry attempt (starting from 1).
+func (p RetryPolicy) backoff(retry int, err error) time.Duration {
+	delay := float64(p.initialBackoff()) * math.Pow(p.multiplier(), float64(retry-1))
+	if maxDelay := float64(p.maxBackoff()); delay > maxDelay {
+		delay = maxDelay
+	}
+
+	jitter := math.Min(math.Max(p.Jitter, 0), 1)
+	delay += delay * jitter * (2*rand.Float64() - 1)
+
+	result := time.Duration(delay)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > result {
+		return apiErr.RetryAfter
+	}
+	return result
+}
+
+// IsRetryableError returns true if the error is likely a temporary failure,
+// i.e. the quota is exceeded or Google Sheets is temporarily unavailable.
+func IsRetryableError(err error) bool {
+	if errors.Is(err, ErrQuotaExceeded) {
+		return true
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// withRetry calls fn until it succeeds, the error is not retryable, the maximum attempts are reached
+// or the context is done.
+// Every attempt is subject to the configured RateLimiter based on the given request kind.
+// Set idempotent to false if calling fn more than once may apply the same change multiple times.
+func (w *Wrapper) withRetry(ctx context.Context, kind requestKind, idempotent bool, fn func() error) error {
+	policy := w.config.RetryPolicy
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = w.config.RateLimiter.wait(ctx, kind); err != nil {
+			return err
+		}
+		if err = fn(); err == nil {
+			return nil
+		}
+		if attempt >= policy.MaxAttempts || !policy.isRetryable(err, idempotent) {
+			return err
+		}
+
+		timer := time.NewTimer(policy.backoff(attempt, err))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// parseRetryAfter parses the Retry-After header, which is either a number of seconds or an HTTP date.
+func parseRetryAfter(header http.Header) time.Duration {
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
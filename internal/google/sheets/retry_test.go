@@ -0,0 +1,199 @@
+package sheets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/FreeLeh/GoFreeDB/google/auth"
+	"github.com/FreeLeh/GoFreeDB/internal/google/fixtures"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/googleapi"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     3,
+	}
+
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1, nil))
+	assert.Equal(t, 300*time.Millisecond, policy.backoff(2, nil))
+	assert.Equal(t, 900*time.Millisecond, policy.backoff(3, nil))
+	assert.Equal(t, time.Second, policy.backoff(4, nil))
+
+	t.Run("jitter", func(t *testing.T) {
+		policy := policy
+		policy.Jitter = 0.5
+		for i := 0; i < 100; i++ {
+			delay := policy.backoff(1, nil)
+			assert.GreaterOrEqual(t, delay, 50*time.Millisecond)
+			assert.LessOrEqual(t, delay, 150*time.Millisecond)
+		}
+	})
+
+	t.Run("retry_after", func(t *testing.T) {
+		err := &APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: 5 * time.Second, kind: ErrQuotaExceeded}
+		assert.Equal(t, 5*time.Second, policy.backoff(1, fmt.Errorf("wrapped: %w", err)))
+	})
+}
+
+func TestRetryPolicy_isRetryable(t *testing.T) {
+	quotaErr := wrapAPIError(&googleapi.Error{Code: http.StatusTooManyRequests})
+	unavailableErr := wrapAPIError(&googleapi.Error{Code: http.StatusServiceUnavailable})
+	invalidRangeErr := wrapAPIError(&googleapi.Error{Code: http.StatusBadRequest, Message: "Unable to parse range"})
+
+	policy := DefaultRetryPolicy()
+	assert.True(t, policy.isRetryable(quotaErr, true))
+	assert.True(t, policy.isRetryable(unavailableErr, true))
+	assert.False(t, policy.isRetryable(invalidRangeErr, true))
+	assert.False(t, policy.isRetryable(errors.New("some error"), true))
+
+	assert.True(t, policy.isRetryable(quotaErr, false))
+	assert.False(t, policy.isRetryable(unavailableErr, false))
+
+	policy.RetryNonIdempotent = true
+	assert.True(t, policy.isRetryable(unavailableErr, false))
+
+	policy.IsRetryable = func(err error) bool { return errors.Is(err, ErrInvalidRange) }
+	assert.True(t, policy.isRetryable(invalidRangeErr, true))
+	assert.False(t, policy.isRetryable(unavailableErr, true))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, time.Duration(0), parseRetryAfter(http.Header{}))
+	assert.Equal(t, 3*time.Second, parseRetryAfter(http.Header{"Retry-After": []string{"3"}}))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(http.Header{"Retry-After": []string{"invalid"}}))
+
+	date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	delay := parseRetryAfter(http.Header{"Retry-After": []string{date}})
+	assert.Greater(t, delay, 59*time.Minute)
+}
+
+func TestWrapper_Retry(t *testing.T) {
+	path := fixtures.PathToFixture("service_account.json")
+
+	auth, err := auth.NewServiceFromFile(path, []string{}, auth.ServiceConfig{})
+	assert.Nil(t, err, "should not have any error instantiating a new service account client")
+
+	wrapper, err := NewWrapperWithConfig(auth, WrapperConfig{
+		RetryPolicy: RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+	})
+	assert.Nil(t, err, "should not have any error instantiating a new sheets wrapper")
+
+	gock.InterceptClient(auth.HTTPClient())
+
+	errorResp := func(code int) map[string]interface{} {
+		return map[string]interface{}{"error": map[string]interface{}{"code": code, "message": http.StatusText(code)}}
+	}
+	appendResp := map[string]interface{}{
+		"updates": map[string]interface{}{
+			"updatedRange": "Sheet1!A1:A1",
+			"updatedRows":  1,
+			"updatedData":  map[string]interface{}{"values": [][]interface{}{{"1"}}},
+		},
+	}
+
+	t.Run("retry_until_successful", func(t *testing.T) {
+		gock.New("https://sheets.googleapis.com").Get("/v4/spreadsheets/123").
+			Times(2).
+			Reply(http.StatusServiceUnavailable).
+			JSON(errorResp(http.StatusServiceUnavailable))
+		gock.New("https://sheets.googleapis.com").Get("/v4/spreadsheets/123").
+			Reply(http.StatusOK).
+			JSON(map[string]interface{}{"sheets": []interface{}{}})
+
+		result, err := wrapper.GetSheetNameToID(context.Background(), "123")
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]int64{}, result)
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("max_attempts", func(t *testing.T) {
+		gock.New("https://sheets.googleapis.com").Get("/v4/spreadsheets/123").
+			Times(3).
+			Reply(http.StatusInternalServerError).
+			JSON(errorResp(http.StatusInternalServerError))
+
+		_, err := wrapper.GetSheetNameToID(context.Background(), "123")
+		assert.NotNil(t, err)
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("non_retryable", func(t *testing.T) {
+		gock.New("https://sheets.googleapis.com").Get("/v4/spreadsheets/123").
+			Reply(http.StatusNotFound).
+			JSON(errorResp(http.StatusNotFound))
+
+		_, err := wrapper.GetSheetNameToID(context.Background(), "123")
+		assert.ErrorIs(t, err, ErrSpreadsheetNotFound)
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("append_not_retried_on_server_error", func(t *testing.T) {
+		gock.New("https://sheets.googleapis.com").Post("/v4/spreadsheets/123/values/Sheet1!A1:append").
+			Reply(http.StatusServiceUnavailable).
+			JSON(errorResp(http.StatusServiceUnavailable))
+		gock.New("https://sheets.googleapis.com").Post("/v4/spreadsheets/123/values/Sheet1!A1:append").
+			Reply(http.StatusOK).
+			JSON(appendResp)
+
+		_, err := wrapper.InsertRows(context.Background(), "123", "Sheet1!A1", [][]interface{}{{"1"}})
+		assert.NotNil(t, err)
+		assert.False(t, gock.IsDone(), "append must not be retried after a server error")
+		gock.Flush()
+	})
+
+	t.Run("append_retried_on_quota_exceeded", func(t *testing.T) {
+		gock.New("https://sheets.googleapis.com").Post("/v4/spreadsheets/123/values/Sheet1!A1:append").
+			Reply(http.StatusTooManyRequests).
+			JSON(errorResp(http.StatusTooManyRequests))
+		gock.New("https://sheets.googleapis.com").Post("/v4/spreadsheets/123/values/Sheet1!A1:append").
+			Reply(http.StatusOK).
+			JSON(appendResp)
+
+		result, err := wrapper.InsertRows(context.Background(), "123", "Sheet1!A1", [][]interface{}{{"1"}})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), result.UpdatedRows)
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("query_rows", func(t *testing.T) {
+		gock.New("https://docs.google.com").Get("/spreadsheets/d/123/gviz/tq").
+			Reply(http.StatusTooManyRequests).
+			SetHeader("Retry-After", "0").
+			BodyString("Too Many Requests")
+		gock.New("https://docs.google.com").Get("/spreadsheets/d/123/gviz/tq").
+			Reply(http.StatusOK).
+			BodyString(`freedb({"status":"ok","table":{"cols":[{"id":"A","type":"string"}],"rows":[{"c":[{"v":"k1"}]}]}})`)
+
+		result, err := wrapper.QueryRows(context.Background(), "123", "Sheet1", "select A", true)
+		assert.Nil(t, err)
+		assert.Equal(t, [][]interface{}{{"k1"}}, result.Rows)
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("context_cancelled", func(t *testing.T) {
+		slowWrapper, err := NewWrapperWithConfig(auth, WrapperConfig{
+			RetryPolicy: RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour},
+		})
+		assert.Nil(t, err)
+
+		gock.New("https://sheets.googleapis.com").Get("/v4/spreadsheets/123").
+			Reply(http.StatusServiceUnavailable).
+			JSON(errorResp(http.StatusServiceUnavailable))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = slowWrapper.GetSheetNameToID(ctx, "123")
+		var apiErr *APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	})
+}
@@ -22,6 +22,7 @@ type AuthClient interface {
 type Wrapper struct {
 	service   *sheets.Service
 	rawClient *http.Client
+	config    WrapperConfig
 }
 
 func (w *Wrapper) CreateSpreadsheet(ctx context.Context, title string) (string, error) {
@@ -29,7 +30,12 @@ func (w *Wrapper) CreateSpreadsheet(ctx context.Context, title string) (string,
 		Properties: &sheets.SpreadsheetProperties{Title: title},
 	}).Context(ctx)
 
-	spreadsheet, err := createSpreadsheetReq.Do()
+	// Creating a spreadsheet is not idempotent, a retried attempt may create a duplicated spreadsheet.
+	var spreadsheet *sheets.Spreadsheet
+	err := w.withRetry(ctx, requestWrite, false, func() (err error) {
+		spreadsheet, err = createSpreadsheetReq.Do()
+		return wrapAPIError(err)
+	})
 	if err != nil {
 		return "", err
 	}
@@ -37,7 +43,25 @@ func (w *Wrapper) CreateSpreadsheet(ctx context.Context, title string) (string,
 }
 
 func (w *Wrapper) CreateSheet(ctx context.Context, spreadsheetID string, sheetName string) error {
-	addSheetReq := &sheets.AddSheetRequest{Properties: &sheets.SheetProperties{Title: sheetName}}
+	return w.CreateSheetWithGridSize(ctx, spreadsheetID, sheetName, GridSize{})
+}
+
+// CreateSheetWithGridSize works just like CreateSheet, but the new sheet has the given number of rows and columns.
+func (w *Wrapper) CreateSheetWithGridSize(
+	ctx context.Context,
+	spreadsheetID string,
+	sheetName string,
+	size GridSize,
+) error {
+	properties := &sheets.SheetProperties{Title: sheetName}
+	if size.RowCount > 0 || size.ColumnCount > 0 {
+		properties.GridProperties = &sheets.GridProperties{
+			RowCount:    size.RowCount,
+			ColumnCount: size.ColumnCount,
+		}
+	}
+
+	addSheetReq := &sheets.AddSheetRequest{Properties: properties}
 	requests := []*sheets.Request{
 		{AddSheet: addSheetReq},
 	}
@@ -46,12 +70,19 @@ func (w *Wrapper) CreateSheet(ctx context.Context, spreadsheetID string, sheetNa
 		&sheets.BatchUpdateSpreadsheetRequest{Requests: requests},
 	).Context(ctx)
 
-	_, err := batchUpdateSpreadsheetReq.Do()
-	return err
+	// A retried attempt fails with ErrConflict if the previous attempt succeeded, so only retry when it is safe.
+	return w.withRetry(ctx, requestWrite, false, func() error {
+		_, err := batchUpdateSpreadsheetReq.Do()
+		return wrapAPIError(err)
+	})
 }
 
 func (w *Wrapper) GetSheetNameToID(ctx context.Context, spreadsheetID string) (map[string]int64, error) {
-	resp, err := w.service.Spreadsheets.Get(spreadsheetID).Context(ctx).Do()
+	var resp *sheets.Spreadsheet
+	err := w.withRetry(ctx, requestRead, true, func() (err error) {
+		resp, err = w.service.Spreadsheets.Get(spreadsheetID).Context(ctx).Do()
+		return wrapAPIError(err)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -79,8 +110,11 @@ func (w *Wrapper) DeleteSheets(ctx context.Context, spreadsheetID string, sheetI
 		&sheets.BatchUpdateSpreadsheetRequest{Requests: requests},
 	).Context(ctx)
 
-	_, err := batchUpdateSpreadsheetReq.Do()
-	return err
+	// A retried attempt fails with ErrSheetNotFound if the previous attempt succeeded, so only retry when it is safe.
+	return w.withRetry(ctx, requestWrite, false, func() error {
+		_, err := batchUpdateSpreadsheetReq.Do()
+		return wrapAPIError(err)
+	})
 }
 
 func (w *Wrapper) InsertRows(
@@ -117,11 +151,19 @@ func (w *Wrapper) insertRows(
 	req := w.service.Spreadsheets.Values.Append(spreadsheetID, a1Range, valueRange).
 		InsertDataOption(string(mode)).
 		IncludeValuesInResponse(true).
-		ResponseValueRenderOption(responseValueRenderFormatted).
+		ResponseValueRenderOption(w.config.valueRenderOption()).
 		ValueInputOption(valueInputUserEntered).
 		Context(ctx)
+	if w.config.valueRenderOption() != responseValueRenderFormatted {
+		req = req.ResponseDateTimeRenderOption(w.config.dateTimeRenderOption())
+	}
 
-	resp, err := req.Do()
+	// Appending rows is not idempotent, as the rows may be appended again when a failed attempt is retried.
+	var resp *sheets.AppendValuesResponse
+	err := w.withRetry(ctx, requestWrite, false, func() (err error) {
+		resp, err = req.Do()
+		return wrapAPIError(err)
+	})
 	if err != nil {
 		return InsertRowsResult{}, err
 	}
@@ -149,11 +191,18 @@ func (w *Wrapper) UpdateRows(
 
 	req := w.service.Spreadsheets.Values.Update(spreadsheetID, a1Range, valueRange).
 		IncludeValuesInResponse(true).
-		ResponseValueRenderOption(responseValueRenderFormatted).
+		ResponseValueRenderOption(w.config.valueRenderOption()).
 		ValueInputOption(valueInputUserEntered).
 		Context(ctx)
+	if w.config.valueRenderOption() != responseValueRenderFormatted {
+		req = req.ResponseDateTimeRenderOption(w.config.dateTimeRenderOption())
+	}
 
-	resp, err := req.Do()
+	var resp *sheets.UpdateValuesResponse
+	err := w.withRetry(ctx, requestWrite, true, func() (err error) {
+		resp, err = req.Do()
+		return wrapAPIError(err)
+	})
 	if err != nil {
 		return UpdateRowsResult{}, err
 	}
@@ -171,6 +220,17 @@ func (w *Wrapper) BatchUpdateRows(
 	ctx context.Context,
 	spreadsheetID string,
 	requests []BatchUpdateRowsRequest,
+) (BatchUpdateRowsResult, error) {
+	return w.BatchUpdateRowsWithOption(ctx, spreadsheetID, requests, ValueInputUserEntered)
+}
+
+// BatchUpdateRowsWithOption works just like BatchUpdateRows, but the values are interpreted based on the
+// given ValueInputOption instead of always being parsed as user entered values.
+func (w *Wrapper) BatchUpdateRowsWithOption(
+	ctx context.Context,
+	spreadsheetID string,
+	requests []BatchUpdateRowsRequest,
+	option ValueInputOption,
 ) (BatchUpdateRowsResult, error) {
 	valueRanges := make([]*sheets.ValueRange, len(requests))
 	for i := range requests {
@@ -184,13 +244,20 @@ func (w *Wrapper) BatchUpdateRows(
 	batchUpdate := &sheets.BatchUpdateValuesRequest{
 		Data:                      valueRanges,
 		IncludeValuesInResponse:   true,
-		ResponseValueRenderOption: responseValueRenderFormatted,
-		ValueInputOption:          valueInputUserEntered,
+		ResponseValueRenderOption: w.config.valueRenderOption(),
+		ValueInputOption:          string(option),
+	}
+	if w.config.valueRenderOption() != responseValueRenderFormatted {
+		batchUpdate.ResponseDateTimeRenderOption = w.config.dateTimeRenderOption()
 	}
 
 	req := w.service.Spreadsheets.Values.BatchUpdate(spreadsheetID, batchUpdate).Context(ctx)
 
-	resp, err := req.Do()
+	var resp *sheets.BatchUpdateValuesResponse
+	err := w.withRetry(ctx, requestWrite, true, func() (err error) {
+		resp, err = req.Do()
+		return wrapAPIError(err)
+	})
 	if err != nil {
 		return BatchUpdateRowsResult{}, err
 	}
@@ -216,11 +283,15 @@ func (w *Wrapper) QueryRows(
 	query string,
 	skipHeader bool,
 ) (QueryRowsResult, error) {
-	rawResult, err := w.execQueryRows(ctx, spreadsheetID, sheetName, query, skipHeader)
+	var rawResult rawQueryRowsResult
+	err := w.withRetry(ctx, requestRead, true, func() (err error) {
+		rawResult, err = w.execQueryRows(ctx, spreadsheetID, sheetName, query, skipHeader)
+		return err
+	})
 	if err != nil {
 		return QueryRowsResult{}, err
 	}
-	return rawResult.toQueryRowsResult()
+	return rawResult.toQueryRowsResult(w.config)
 }
 
 func (w *Wrapper) execQueryRows(
@@ -241,7 +312,7 @@ func (w *Wrapper) execQueryRows(
 	}
 	params.Add("headers", strconv.FormatInt(int64(header), 10))
 
-	url := fmt.Sprintf(queryRowsURLTemplate, spreadsheetID) + "?" + params.Encode()
+	url := w.config.Endpoints.queryRowsURL(spreadsheetID) + "?" + params.Encode()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -260,6 +331,10 @@ func (w *Wrapper) execQueryRows(
 	}
 	respString := string(respBytes)
 
+	if resp.StatusCode != http.StatusOK {
+		return rawQueryRowsResult{}, queryStatusToError(resp, respString)
+	}
+
 	firstCurly := strings.Index(respString, "{")
 	if firstCurly == -1 {
 		return rawQueryRowsResult{}, fmt.Errorf("opening curly bracket not found: %s", respString)
@@ -274,13 +349,20 @@ func (w *Wrapper) execQueryRows(
 	if err := json.Unmarshal([]byte(respString[firstCurly:lastCurly+1]), &result); err != nil {
 		return rawQueryRowsResult{}, err
 	}
+	if err := result.toError(); err != nil {
+		return rawQueryRowsResult{}, err
+	}
 	return result, nil
 }
 
 func (w *Wrapper) Clear(ctx context.Context, spreadsheetID string, ranges []string) ([]string, error) {
 	req := w.service.Spreadsheets.Values.BatchClear(spreadsheetID, &sheets.BatchClearValuesRequest{Ranges: ranges}).
 		Context(ctx)
-	resp, err := req.Do()
+	var resp *sheets.BatchClearValuesResponse
+	err := w.withRetry(ctx, requestWrite, true, func() (err error) {
+		resp, err = req.Do()
+		return wrapAPIError(err)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -288,15 +370,26 @@ func (w *Wrapper) Clear(ctx context.Context, spreadsheetID string, ranges []stri
 }
 
 func NewWrapper(authClient AuthClient) (*Wrapper, error) {
+	return NewWrapperWithConfig(authClient, WrapperConfig{})
+}
+
+// NewWrapperWithConfig works just like NewWrapper, but with the given configuration.
+func NewWrapperWithConfig(authClient AuthClient, config WrapperConfig) (*Wrapper, error) {
 	// The `ctx` provided into `NewService` is not really used for anything in our case.
 	// Internally it seems it's used for creating a new HTTP client, but we already provide with our
 	// own auth HTTP client.
-	service, err := sheets.NewService(context.Background(), option.WithHTTPClient(authClient.HTTPClient()))
+	opts := []option.ClientOption{option.WithHTTPClient(authClient.HTTPClient())}
+	if endpoint := config.Endpoints.apiEndpoint(); endpoint != "" {
+		opts = append(opts, option.WithEndpoint(endpoint))
+	}
+
+	service, err := sheets.NewService(context.Background(), opts...)
 	if err != nil {
 		return nil, err
 	}
 	return &Wrapper{
 		service:   service,
 		rawClient: authClient.HTTPClient(),
+		config:    config,
 	}, nil
 }
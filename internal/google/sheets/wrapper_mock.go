@@ -6,6 +6,9 @@ type MockWrapper struct {
 	CreateSpreadsheetResult string
 	CreateSpreadsheetError  error
 
+	GetSheetNameToIDResult map[string]int64
+	GetSheetNameToIDError  error
+
 	CreateSheetError error
 
 	InsertRowsResult InsertRowsResult
@@ -32,7 +35,7 @@ func (w *MockWrapper) CreateSpreadsheet(ctx context.Context, title string) (stri
 }
 
 func (w *MockWrapper) GetSheetNameToID(ctx context.Context, spreadsheetID string) (map[string]int64, error) {
-	return nil, nil
+	return w.GetSheetNameToIDResult, w.GetSheetNameToIDError
 }
 
 func (w *MockWrapper) DeleteSheets(ctx context.Context, spreadsheetID string, sheetIDs []int64) error {
@@ -43,6 +46,10 @@ func (w *MockWrapper) CreateSheet(ctx context.Context, spreadsheetID string, she
 	return w.CreateSheetError
 }
 
+func (w *MockWrapper) CreateSheetWithGridSize(ctx context.Context, spreadsheetID string, sheetName string, size GridSize) error {
+	return w.CreateSheetError
+}
+
 func (w *MockWrapper) InsertRows(ctx context.Context, spreadsheetID string, a1Range string, values [][]interface{}) (InsertRowsResult, error) {
 	return w.InsertRowsResult, w.InsertRowsError
 }
@@ -59,6 +66,10 @@ func (w *MockWrapper) BatchUpdateRows(ctx context.Context, spreadsheetID string,
 	return w.BatchUpdateRowsResult, w.BatchUpdateRowsError
 }
 
+func (w *MockWrapper) BatchUpdateRowsWithOption(ctx context.Context, spreadsheetID string, requests []BatchUpdateRowsRequest, option ValueInputOption) (BatchUpdateRowsResult, error) {
+	return w.BatchUpdateRowsResult, w.BatchUpdateRowsError
+}
+
 func (w *MockWrapper) QueryRows(ctx context.Context, spreadsheetID string, sheetName string, query string, skipHeader bool) (QueryRowsResult, error) {
 	return w.QueryRowsResult, w.QueryRowsError
 }
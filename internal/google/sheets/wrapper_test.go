@@ -2,12 +2,14 @@ package sheets
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"testing"
 
 	"github.com/FreeLeh/GoFreeDB/google/auth"
 	"github.com/FreeLeh/GoFreeDB/internal/google/fixtures"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/googleapi"
 	"gopkg.in/h2non/gock.v1"
 )
 
@@ -124,6 +126,33 @@ func TestCreateSheet(t *testing.T) {
 		assert.Nil(t, err, "should not have any error creating a new sheet")
 	})
 
+	t.Run("with_grid_size", func(t *testing.T) {
+		expectedReqBody := map[string]interface{}{
+			"requests": []interface{}{
+				map[string]interface{}{
+					"addSheet": map[string]interface{}{
+						"properties": map[string]interface{}{
+							"title": "sheet",
+							"gridProperties": map[string]interface{}{
+								"rowCount":    10,
+								"columnCount": 3,
+							},
+						},
+					},
+				},
+			},
+		}
+
+		gock.New("https://sheets.googleapis.com").
+			Post("/v4/spreadsheets/123:batchUpdate").
+			JSON(expectedReqBody).
+			Reply(http.StatusOK).
+			JSON(map[string]string{"spreadsheetId": "123"})
+
+		err := wrapper.CreateSheetWithGridSize(context.Background(), "123", "sheet", GridSize{RowCount: 10, ColumnCount: 3})
+		assert.Nil(t, err, "should not have any error creating a new sheet")
+	})
+
 	t.Run("http500", func(t *testing.T) {
 		expectedReqBody := map[string][]map[string]map[string]map[string]string{
 			"requests": {
@@ -613,11 +642,248 @@ func TestQueryRows(t *testing.T) {
 		)
 		assert.Nil(t, err)
 
-		expected := QueryRowsResult{Rows: [][]interface{}{
-			{"k1", 103.51},
-			{"k2", float64(111)},
-			{"k3", float64(123)},
-		}}
+		expected := QueryRowsResult{
+			Rows: [][]interface{}{
+				{"k1", 103.51},
+				{"k2", float64(111)},
+				{"k3", float64(123)},
+			},
+			Columns: []QueryRowsColumn{
+				{ID: "A", Type: "string"},
+				{ID: "B", Type: "number", Pattern: "General"},
+			},
+		}
 		assert.Equal(t, expected, res)
 	})
+
+	t.Run("warnings", func(t *testing.T) {
+		resp := `freedb({"version":"0.6","reqId":"0","status":"warning","warnings":[{"reason":"data_truncated","message":"Retrieved data was truncated"}],"table":{"cols":[{"id":"A","label":"","type":"string"}],"rows":[{"c":[{"v":"k1"}]}]}})`
+
+		gock.New("https://docs.google.com").
+			Get("/spreadsheets/d/spreadsheetID/gviz/tq").
+			Reply(http.StatusOK).
+			BodyString(resp)
+
+		res, err := wrapper.QueryRows(context.Background(), "spreadsheetID", "s1", "select A", true)
+		assert.Nil(t, err)
+		assert.Equal(t, [][]interface{}{{"k1"}}, res.Rows)
+		assert.Equal(t, []QueryWarning{{Reason: "data_truncated", Message: "Retrieved data was truncated"}}, res.Warnings)
+	})
+
+	errorCases := []struct {
+		name        string
+		status      int
+		resp        string
+		expectedErr error
+		reason      string
+	}{
+		{
+			name:        "invalid_query",
+			status:      http.StatusOK,
+			resp:        `freedb({"version":"0.6","reqId":"0","status":"error","errors":[{"reason":"invalid_query","message":"INVALID_QUERY","detailed_message":"Invalid query: NO_COLUMN: C"}]})`,
+			expectedErr: ErrInvalidQuery,
+			reason:      "invalid_query",
+		},
+		{
+			name:        "access_denied",
+			status:      http.StatusOK,
+			resp:        `freedb({"version":"0.6","reqId":"0","status":"error","errors":[{"reason":"access_denied","message":"Access denied"}]})`,
+			expectedErr: ErrPermissionDenied,
+			reason:      "access_denied",
+		},
+		{
+			name:        "unknown_data_source",
+			status:      http.StatusOK,
+			resp:        `freedb({"version":"0.6","reqId":"0","status":"error","errors":[{"reason":"unknown_data_source_id","message":"Unknown data source"}]})`,
+			expectedErr: ErrSheetNotFound,
+			reason:      "unknown_data_source_id",
+		},
+		{
+			name:        "http_unauthorized",
+			status:      http.StatusUnauthorized,
+			resp:        `<html>Sign in</html>`,
+			expectedErr: ErrPermissionDenied,
+		},
+		{
+			name:        "http_not_found",
+			status:      http.StatusNotFound,
+			resp:        `<html>Not Found</html>`,
+			expectedErr: ErrSpreadsheetNotFound,
+		},
+	}
+
+	for _, c := range errorCases {
+		t.Run(c.name, func(t *testing.T) {
+			gock.New("https://docs.google.com").
+				Get("/spreadsheets/d/spreadsheetID/gviz/tq").
+				Reply(c.status).
+				BodyString(c.resp)
+
+			res, err := wrapper.QueryRows(context.Background(), "spreadsheetID", "s1", "select C", true)
+			assert.Equal(t, QueryRowsResult{}, res)
+			assert.ErrorIs(t, err, c.expectedErr)
+
+			var queryErr *QueryError
+			if c.reason == "" {
+				assert.False(t, errors.As(err, &queryErr))
+				return
+			}
+			assert.True(t, errors.As(err, &queryErr))
+			assert.Equal(t, c.reason, queryErr.Reason)
+		})
+	}
+
+	t.Run("unknown_reason", func(t *testing.T) {
+		gock.New("https://docs.google.com").
+			Get("/spreadsheets/d/spreadsheetID/gviz/tq").
+			Reply(http.StatusOK).
+			BodyString(`freedb({"version":"0.6","reqId":"0","status":"error","errors":[{"reason":"internal_error","message":"Internal error"}]})`)
+
+		_, err := wrapper.QueryRows(context.Background(), "spreadsheetID", "s1", "select A", true)
+		assert.NotNil(t, err)
+		assert.NotErrorIs(t, err, ErrInvalidQuery)
+
+		var queryErr *QueryError
+		assert.True(t, errors.As(err, &queryErr))
+		assert.Equal(t, "Internal error", queryErr.Message)
+	})
+}
+
+func TestUpdateRows_Unformatted(t *testing.T) {
+	path := fixtures.PathToFixture("service_account.json")
+
+	auth, err := auth.NewServiceFromFile(path, []string{}, auth.ServiceConfig{})
+	assert.Nil(t, err, "should not have any error instantiating a new service account client")
+
+	wrapper, err := NewWrapperWithConfig(auth, WrapperConfig{ValueRenderOption: ValueRenderUnformatted})
+	assert.Nil(t, err, "should not have any error instantiating a new sheets wrapper")
+
+	gock.InterceptClient(auth.HTTPClient())
+
+	expectedParams := map[string]string{
+		"includeValuesInResponse":      "true",
+		"responseValueRenderOption":    string(ValueRenderUnformatted),
+		"responseDateTimeRenderOption": string(DateTimeRenderSerialNumber),
+		"valueInputOption":             valueInputUserEntered,
+	}
+	resp := map[string]interface{}{
+		"spreadsheetId":  "123",
+		"updatedRange":   "Sheet1!A1",
+		"updatedRows":    1,
+		"updatedColumns": 1,
+		"updatedCells":   1,
+		"updatedData": map[string]interface{}{
+			"range":          "Sheet1!A1",
+			"majorDimension": majorDimensionRows,
+			"values":         [][]interface{}{{1234.5}},
+		},
+	}
+
+	gock.New("https://sheets.googleapis.com").
+		Put("/v4/spreadsheets/123/values/Sheet1!A1").
+		MatchParams(expectedParams).
+		Reply(http.StatusOK).
+		JSON(resp)
+
+	res, err := wrapper.UpdateRows(context.Background(), "123", "Sheet1!A1", [][]interface{}{{"=1234.5"}})
+	assert.Nil(t, err)
+	assert.Equal(t, [][]interface{}{{1234.5}}, res.UpdatedValues)
+}
+
+func TestWrapper_APIError(t *testing.T) {
+	path := fixtures.PathToFixture("service_account.json")
+
+	auth, err := auth.NewServiceFromFile(path, []string{}, auth.ServiceConfig{})
+	assert.Nil(t, err, "should not have any error instantiating a new service account client")
+
+	wrapper, err := NewWrapper(auth)
+	assert.Nil(t, err, "should not have any error instantiating a new sheets wrapper")
+
+	gock.InterceptClient(auth.HTTPClient())
+
+	t.Run("quota_exceeded", func(t *testing.T) {
+		resp := map[string]interface{}{
+			"error": map[string]interface{}{
+				"code":    http.StatusTooManyRequests,
+				"message": "Quota exceeded for quota metric 'Read requests'",
+				"status":  "RESOURCE_EXHAUSTED",
+			},
+		}
+		gock.New("https://sheets.googleapis.com").
+			Get("/v4/spreadsheets/123").
+			Reply(http.StatusTooManyRequests).
+			JSON(resp)
+
+		_, err := wrapper.GetSheetNameToID(context.Background(), "123")
+		assert.ErrorIs(t, err, ErrQuotaExceeded)
+
+		var apiErr *APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+
+		var googleErr *googleapi.Error
+		assert.True(t, errors.As(err, &googleErr))
+		assert.Equal(t, http.StatusTooManyRequests, googleErr.Code)
+	})
+
+	t.Run("invalid_range", func(t *testing.T) {
+		resp := map[string]interface{}{
+			"error": map[string]interface{}{
+				"code":    http.StatusBadRequest,
+				"message": "Unable to parse range: Unknown!A1",
+				"status":  "INVALID_ARGUMENT",
+			},
+		}
+		gock.New("https://sheets.googleapis.com").
+			Post("/v4/spreadsheets/123/values:batchClear").
+			Reply(http.StatusBadRequest).
+			JSON(resp)
+
+		_, err := wrapper.Clear(context.Background(), "123", []string{"Unknown!A1"})
+		assert.ErrorIs(t, err, ErrInvalidRange)
+		assert.NotErrorIs(t, err, ErrQuotaExceeded)
+	})
+}
+
+func TestWrapper_Endpoints(t *testing.T) {
+	path := fixtures.PathToFixture("service_account.json")
+
+	auth, err := auth.NewServiceFromFile(path, []string{}, auth.ServiceConfig{})
+	assert.Nil(t, err, "should not have any error instantiating a new service account client")
+
+	wrapper, err := NewWrapperWithConfig(auth, WrapperConfig{
+		Endpoints: Endpoints{
+			API:   "http://localhost:8080/sheets",
+			Query: "http://localhost:8081/",
+		},
+	})
+	assert.Nil(t, err, "should not have any error instantiating a new sheets wrapper")
+
+	gock.InterceptClient(auth.HTTPClient())
+
+	t.Run("api", func(t *testing.T) {
+		gock.New("http://localhost:8080").
+			Get("/sheets/v4/spreadsheets/123").
+			Reply(http.StatusOK).
+			JSON(map[string]interface{}{
+				"sheets": []interface{}{
+					map[string]interface{}{"properties": map[string]interface{}{"title": "s1", "sheetId": 1}},
+				},
+			})
+
+		result, err := wrapper.GetSheetNameToID(context.Background(), "123")
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]int64{"s1": 1}, result)
+	})
+
+	t.Run("query", func(t *testing.T) {
+		gock.New("http://localhost:8081").
+			Get("/spreadsheets/d/123/gviz/tq").
+			Reply(http.StatusOK).
+			BodyString(`freedb({"status":"ok","table":{"cols":[{"id":"A","type":"string"}],"rows":[{"c":[{"v":"k1"}]}]}})`)
+
+		result, err := wrapper.QueryRows(context.Background(), "123", "s1", "select A", true)
+		assert.Nil(t, err)
+		assert.Equal(t, [][]interface{}{{"k1"}}, result.Rows)
+	})
 }
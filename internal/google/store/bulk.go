@@ -0,0 +1,179 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+const (
+	defaultBulkChunkSize  = 1000
+	defaultBulkChunkBytes = 2 << 20
+)
+
+// BulkProgress describes the progress of a statement writing its rows in chunks,
+// see GoogleSheetInsertStmt.ChunkSize and GoogleSheetUpdateStmt.ChunkSize.
+type BulkProgress struct {
+	TotalRows   int
+	WrittenRows int
+	FailedRows  int
+}
+
+// BulkChunkError describes a chunk of rows that could not be written.
+type BulkChunkError struct {
+	// Start and End (exclusive) are the chunk boundaries. For inserts, they are the indices of the rows given to
+	// GoogleSheetRowStore.Insert. For updates, they are the indices of the matching rows in the sheet order.
+	Start int
+	End   int
+	Err   error
+}
+
+// BulkError is returned by a statement writing its rows in more than one chunk when some of the chunks failed.
+// The other chunks have been written, so only the failed chunks need to be retried.
+type BulkError struct {
+	Progress BulkProgress
+
+	// Chunks are the failed chunks, ordered by their position.
+	Chunks []BulkChunkError
+}
+
+func (e *BulkError) Error() string {
+	return fmt.Sprintf(
+		"failed writing %d of %d rows in %d chunks, first error: %v",
+		e.Progress.FailedRows,
+		e.Progress.TotalRows,
+		len(e.Chunks),
+		e.Chunks[0].Err,
+	)
+}
+
+// Unwrap returns the error of the first failed chunk.
+func (e *BulkError) Unwrap() error {
+	return e.Chunks[0].Err
+}
+
+// bulkOptions defines how a statement splits its rows into chunks written by separate requests.
+type bulkOptions struct {
+	chunkSize   int
+	concurrency int
+	onProgress  func(BulkProgress)
+}
+
+// bulkChunk is the range of rows written by a single request, end is exclusive.
+type bulkChunk struct {
+	start int
+	end   int
+}
+
+// split splits the total rows into chunks. If the chunk size is set, every chunk has up to that number of rows.
+// Otherwise, a chunk has up to defaultBulkChunkSize rows and, if rowBytes is given, up to defaultBulkChunkBytes
+// of encoded rows. A single row larger than defaultBulkChunkBytes is written alone.
+func (o bulkOptions) split(total int, rowBytes func(i int) int) []bulkChunk {
+	maxRows, maxBytes := o.chunkSize, 0
+	if maxRows <= 0 {
+		maxRows, maxBytes = defaultBulkChunkSize, defaultBulkChunkBytes
+	}
+	if rowBytes == nil {
+		maxBytes = 0
+	}
+
+	chunks := make([]bulkChunk, 0)
+	chunk, chunkBytes := bulkChunk{}, 0
+	for i := 0; i < total; i++ {
+		size := 0
+		if maxBytes > 0 {
+			size = rowBytes(i)
+		}
+
+		full := chunk.end-chunk.start >= maxRows || (maxBytes > 0 && chunkBytes+size > maxBytes)
+		if chunk.end > chunk.start && full {
+			chunks = append(chunks, chunk)
+			chunk, chunkBytes = bulkChunk{start: i, end: i}, 0
+		}
+		chunk.end++
+		chunkBytes += size
+	}
+	if chunk.end > chunk.start {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// run calls write with the index of every chunk, using up to the configured number of concurrent workers.
+// All chunks are attempted, even if some of them fail. The error of a single chunk is returned as is.
+func (o bulkOptions) run(ctx context.Context, chunks []bulkChunk, write func(ctx context.Context, idx int) error) error {
+	total := 0
+	if len(chunks) > 0 {
+		total = chunks[len(chunks)-1].end
+	}
+
+	workers := o.concurrency
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > len(chunks) {
+		workers = len(chunks)
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		progress = BulkProgress{TotalRows: total}
+		failures []BulkChunkError
+		indices  = make(chan int)
+	)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indices {
+				chunk := chunks[idx]
+
+				err := ctx.Err()
+				if err == nil {
+					err = write(ctx, idx)
+				}
+
+				mu.Lock()
+				if err != nil {
+					progress.FailedRows += chunk.end - chunk.start
+					failures = append(failures, BulkChunkError{Start: chunk.start, End: chunk.end, Err: err})
+				} else {
+					progress.WrittenRows += chunk.end - chunk.start
+				}
+				if o.onProgress != nil {
+					o.onProgress(progress)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for idx := range chunks {
+		indices <- idx
+	}
+	close(indices)
+	wg.Wait()
+
+	if len(failures) == 0 {
+		return nil
+	}
+	if len(chunks) == 1 {
+		return failures[0].Err
+	}
+
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Start < failures[j].Start })
+	return &BulkError{Progress: progress, Chunks: failures}
+}
+
+// encodedSize returns the size of the value encoded in the request payload, or 0 if it cannot be encoded.
+func encodedSize(value interface{}) int {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return 0
+	}
+	return len(encoded)
+}
@@ -0,0 +1,184 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/FreeLeh/GoFreeDB/internal/google/memory"
+	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkOptions_Split(t *testing.T) {
+	assert.Empty(t, bulkOptions{}.split(0, nil))
+	assert.Equal(t, []bulkChunk{{0, 3}, {3, 6}, {6, 7}}, bulkOptions{chunkSize: 3}.split(7, nil))
+	assert.Equal(t, []bulkChunk{{0, 1000}, {1000, 1001}}, bulkOptions{}.split(1001, nil))
+
+	// The default chunks are bounded by the encoded size as well, a larger row is written alone.
+	sizes := []int{1 << 20, 1 << 20, 1 << 20, 3 << 20, 10}
+	rowBytes := func(i int) int { return sizes[i] }
+	assert.Equal(t, []bulkChunk{{0, 2}, {2, 3}, {3, 4}, {4, 5}}, bulkOptions{}.split(len(sizes), rowBytes))
+
+	// The chunk size overrides the default bounds.
+	assert.Equal(t, []bulkChunk{{0, 5}}, bulkOptions{chunkSize: 5}.split(len(sizes), rowBytes))
+}
+
+func TestBulkOptions_Run(t *testing.T) {
+	ctx := context.Background()
+	expectedErr := errors.New("error")
+
+	var progress []BulkProgress
+	opts := bulkOptions{
+		chunkSize:   3,
+		concurrency: 2,
+		onProgress:  func(p BulkProgress) { progress = append(progress, p) },
+	}
+	chunks := opts.split(10, nil)
+	err := opts.run(ctx, chunks, func(ctx context.Context, idx int) error {
+		if chunks[idx].start == 3 || chunks[idx].start == 9 {
+			return expectedErr
+		}
+		return nil
+	})
+
+	var bulkErr *BulkError
+	assert.ErrorAs(t, err, &bulkErr)
+	assert.ErrorIs(t, err, expectedErr)
+	assert.Equal(t, BulkProgress{TotalRows: 10, WrittenRows: 6, FailedRows: 4}, bulkErr.Progress)
+	assert.Equal(t, []BulkChunkError{
+		{Start: 3, End: 6, Err: expectedErr},
+		{Start: 9, End: 10, Err: expectedErr},
+	}, bulkErr.Chunks)
+	assert.Len(t, progress, 4)
+	assert.Equal(t, bulkErr.Progress, progress[3])
+
+	// The error of a single chunk is returned as is.
+	err = bulkOptions{}.run(ctx, []bulkChunk{{0, 10}}, func(ctx context.Context, idx int) error {
+		assert.Equal(t, 0, idx)
+		return expectedErr
+	})
+	assert.Equal(t, expectedErr, err)
+}
+
+func TestConsecutiveRows(t *testing.T) {
+	assert.Equal(t, [][]int64{{1, 2}, {4}, {6, 7, 8}}, consecutiveRows([]int64{1, 2, 4, 6, 7, 8}))
+	assert.Empty(t, consecutiveRows(nil))
+}
+
+func TestGoogleSheetRowStore_BulkInsertAndUpdate(t *testing.T) {
+	counts := make(map[string]int)
+	ctx := context.Background()
+	db, err := NewGoogleSheetRowStoreWithContext(
+		ctx,
+		nil,
+		"spreadsheet",
+		"people",
+		GoogleSheetRowStoreConfig{
+			Columns:       []string{"name", "age", "dob"},
+			MemoryBackend: memory.NewBackend(),
+			Interceptors:  []Interceptor{newOperationCounter(counts)},
+		},
+	)
+	assert.Nil(t, err)
+
+	rows := make([]interface{}, 25)
+	for i := range rows {
+		rows[i] = testPerson{"name", int64(i), "1999-01-01"}
+	}
+
+	var progress []BulkProgress
+	counts["OverwriteRows"] = 0
+	err = db.Insert(rows...).
+		ChunkSize(10).
+		Concurrency(3).
+		OnProgress(func(p BulkProgress) { progress = append(progress, p) }).
+		Exec(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, counts["OverwriteRows"])
+	assert.Len(t, progress, 3)
+	assert.Equal(t, BulkProgress{TotalRows: 25, WrittenRows: 25}, progress[2])
+
+	var out []testPerson
+	assert.Nil(t, db.Select(&out).Exec(ctx))
+	assert.Len(t, out, 25)
+
+	ages := make([]int, len(out))
+	for i, p := range out {
+		ages[i] = int(p.Age)
+	}
+	sort.Ints(ages)
+	for i, age := range ages {
+		assert.Equal(t, i, age)
+	}
+
+	counts["BatchUpdateRows"] = 0
+	err = db.Update(map[string]interface{}{"dob": "2000-01-01"}).
+		Where("age >= ?", 5).
+		ChunkSize(10).
+		Exec(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, counts["BatchUpdateRows"])
+
+	count, err := db.Count().Where("dob = ?", "2000-01-01").Exec(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(20), count)
+}
+
+func TestGoogleSheetRowStore_BulkInsertDefaultChunks(t *testing.T) {
+	counts := make(map[string]int)
+	ctx := context.Background()
+	db, err := NewGoogleSheetRowStoreWithContext(
+		ctx,
+		nil,
+		"spreadsheet",
+		"people",
+		GoogleSheetRowStoreConfig{
+			Columns:       []string{"name", "age", "dob"},
+			MemoryBackend: memory.NewBackend(),
+			Interceptors:  []Interceptor{newOperationCounter(counts)},
+		},
+	)
+	assert.Nil(t, err)
+
+	rows := make([]interface{}, defaultBulkChunkSize+1)
+	for i := range rows {
+		rows[i] = testPerson{"name", int64(i), "1999-01-01"}
+	}
+
+	counts["OverwriteRows"] = 0
+	assert.Nil(t, db.Insert(rows...).Exec(ctx))
+	assert.Equal(t, 2, counts["OverwriteRows"])
+
+	count, err := db.Count().Exec(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(len(rows)), count)
+}
+
+func TestGoogleSheetInsertStmt_BulkFailure(t *testing.T) {
+	ctx := context.Background()
+	wrapper := &failingInsertWrapper{
+		SheetsWrapper: memory.NewWrapper(memory.NewBackend(), sheets.ValueRenderFormatted),
+	}
+	db, err := NewGoogleSheetRowStoreWithContext(
+		ctx,
+		nil,
+		"spreadsheet",
+		"people",
+		GoogleSheetRowStoreConfig{Columns: []string{"name", "age", "dob"}, Wrapper: wrapper},
+	)
+	assert.Nil(t, err)
+
+	expectedErr := errors.New("error")
+	wrapper.err = expectedErr
+
+	err = db.Insert(testPerson{"name1", 10, "1999-01-01"}, testPerson{"name2", 11, "2000-01-01"}).
+		ChunkSize(1).
+		Exec(ctx)
+	var bulkErr *BulkError
+	assert.ErrorAs(t, err, &bulkErr)
+	assert.ErrorIs(t, err, expectedErr)
+	assert.Equal(t, BulkProgress{TotalRows: 2, FailedRows: 2}, bulkErr.Progress)
+	assert.Len(t, bulkErr.Chunks, 2)
+}
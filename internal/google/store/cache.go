@@ -0,0 +1,251 @@
+package store
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
+)
+
+const defaultCacheMaxEntries = 1000
+
+// CacheConfig defines the read-through cache of a store.
+//
+// The row store caches the results of GoogleSheetSelectStmt.Exec and GoogleSheetCountStmt.Exec keyed by the
+// generated query, while GoogleSheetKVStore caches the values keyed by the key. The rows updated or deleted by
+// GoogleSheetUpdateStmt and GoogleSheetDeleteStmt are always looked up without the cache.
+// The cache is invalidated whenever the same store instance writes. Writes made by other store instances or
+// directly in Google Sheets are only visible once the cached entries expire.
+type CacheConfig struct {
+	// TTL defines how long a cached entry is used. The default value (0) disables the cache.
+	TTL time.Duration
+
+	// MaxEntries defines the maximum number of cached entries.
+	// The least recently used entry is evicted when the cache is full. The default value is 1000.
+	MaxEntries int
+}
+
+// cache is a TTL and size bounded LRU cache.
+//
+// Every invalidation increments the cache generation. A value read before an invalidation is not stored,
+// so that a read racing with a write cannot put a stale value into the cache.
+type cache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List
+	generation uint64
+	now        func() time.Time
+}
+
+type cacheEntry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// newCache returns nil if the cache is disabled. All cache methods accept a nil receiver.
+func newCache(config CacheConfig) *cache {
+	if config.TTL <= 0 {
+		return nil
+	}
+
+	maxEntries := config.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultCacheMaxEntries
+	}
+	return &cache{
+		ttl:        config.TTL,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		now:        time.Now,
+	}
+}
+
+// get returns the cached value and the current generation, which must be passed to put.
+func (c *cache) get(key string) (interface{}, uint64, bool) {
+	if c == nil {
+		return nil, 0, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, c.generation, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.remove(elem)
+		return nil, c.generation, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.value, c.generation, true
+}
+
+// put stores the value, unless the cache has been invalidated since the given generation.
+func (c *cache) put(key string, value interface{}, generation uint64) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, value: value, expiresAt: c.now().Add(c.ttl)})
+
+	for c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+// invalidate removes the entries with the given keys, or all entries if no key is given.
+func (c *cache) invalidate(keys ...string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	if len(keys) == 0 {
+		c.entries = make(map[string]*list.Element)
+		c.order.Init()
+		return
+	}
+
+	for _, key := range keys {
+		if elem, ok := c.entries[key]; ok {
+			c.remove(elem)
+		}
+	}
+}
+
+func (c *cache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+}
+
+// invalidatingWrapper invalidates the cached and the in-flight query results of a row store whenever it writes,
+// see CacheConfig and GoogleSheetRowStoreConfig.CoalesceReads.
+type invalidatingWrapper struct {
+	SheetsWrapper
+	cache *cache
+	group *flightGroup
+}
+
+// invalidateOnWrite returns the wrapper as is if both the cache and the coalescing are disabled.
+func invalidateOnWrite(wrapper SheetsWrapper, c *cache, g *flightGroup) SheetsWrapper {
+	if c == nil && g == nil {
+		return wrapper
+	}
+	return &invalidatingWrapper{SheetsWrapper: wrapper, cache: c, group: g}
+}
+
+func (w *invalidatingWrapper) invalidate() {
+	w.cache.invalidate()
+	w.group.forget()
+}
+
+func (w *invalidatingWrapper) CreateSheet(ctx context.Context, spreadsheetID string, sheetName string) error {
+	defer w.invalidate()
+	return w.SheetsWrapper.CreateSheet(ctx, spreadsheetID, sheetName)
+}
+
+func (w *invalidatingWrapper) CreateSheetWithGridSize(
+	ctx context.Context,
+	spreadsheetID string,
+	sheetName string,
+	size sheets.GridSize,
+) error {
+	defer w.invalidate()
+	return w.SheetsWrapper.CreateSheetWithGridSize(ctx, spreadsheetID, sheetName, size)
+}
+
+func (w *invalidatingWrapper) DeleteSheets(ctx context.Context, spreadsheetID string, sheetIDs []int64) error {
+	defer w.invalidate()
+	return w.SheetsWrapper.DeleteSheets(ctx, spreadsheetID, sheetIDs)
+}
+
+func (w *invalidatingWrapper) InsertRows(
+	ctx context.Context,
+	spreadsheetID string,
+	a1Range string,
+	values [][]interface{},
+) (sheets.InsertRowsResult, error) {
+	defer w.invalidate()
+	return w.SheetsWrapper.InsertRows(ctx, spreadsheetID, a1Range, values)
+}
+
+func (w *invalidatingWrapper) OverwriteRows(
+	ctx context.Context,
+	spreadsheetID string,
+	a1Range string,
+	values [][]interface{},
+) (sheets.InsertRowsResult, error) {
+	defer w.invalidate()
+	return w.SheetsWrapper.OverwriteRows(ctx, spreadsheetID, a1Range, values)
+}
+
+func (w *invalidatingWrapper) UpdateRows(
+	ctx context.Context,
+	spreadsheetID string,
+	a1Range string,
+	values [][]interface{},
+) (sheets.UpdateRowsResult, error) {
+	defer w.invalidate()
+	return w.SheetsWrapper.UpdateRows(ctx, spreadsheetID, a1Range, values)
+}
+
+func (w *invalidatingWrapper) BatchUpdateRows(
+	ctx context.Context,
+	spreadsheetID string,
+	requests []sheets.BatchUpdateRowsRequest,
+) (sheets.BatchUpdateRowsResult, error) {
+	defer w.invalidate()
+	return w.SheetsWrapper.BatchUpdateRows(ctx, spreadsheetID, requests)
+}
+
+func (w *invalidatingWrapper) BatchUpdateRowsWithOption(
+	ctx context.Context,
+	spreadsheetID string,
+	requests []sheets.BatchUpdateRowsRequest,
+	option sheets.ValueInputOption,
+) (sheets.BatchUpdateRowsResult, error) {
+	defer w.invalidate()
+	return w.SheetsWrapper.BatchUpdateRowsWithOption(ctx, spreadsheetID, requests, option)
+}
+
+func (w *invalidatingWrapper) Clear(ctx context.Context, spreadsheetID string, ranges []string) ([]string, error) {
+	defer w.invalidate()
+	return w.SheetsWrapper.Clear(ctx, spreadsheetID, ranges)
+}
+
+// copyQueryRowsResult copies the rows and the columns, as the statements may modify the returned result.
+func copyQueryRowsResult(result sheets.QueryRowsResult) sheets.QueryRowsResult {
+	rows := make([][]interface{}, len(result.Rows))
+	for i, row := range result.Rows {
+		rows[i] = append([]interface{}(nil), row...)
+	}
+
+	return sheets.QueryRowsResult{
+		Rows:     rows,
+		Columns:  append([]sheets.QueryRowsColumn(nil), result.Columns...),
+		Warnings: append([]sheets.QueryWarning(nil), result.Warnings...),
+	}
+}
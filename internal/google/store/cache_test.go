@@ -0,0 +1,169 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FreeLeh/GoFreeDB/internal/google/memory"
+	"github.com/FreeLeh/GoFreeDB/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	assert.Nil(t, newCache(CacheConfig{}))
+
+	now := time.Now()
+	c := newCache(CacheConfig{TTL: time.Minute, MaxEntries: 2})
+	c.now = func() time.Time { return now }
+
+	_, generation, ok := c.get("a")
+	assert.False(t, ok)
+	c.put("a", 1, generation)
+	c.put("b", 2, generation)
+
+	value, _, ok := c.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	// "b" is the least recently used entry.
+	c.put("c", 3, generation)
+	_, _, ok = c.get("b")
+	assert.False(t, ok)
+	_, _, ok = c.get("c")
+	assert.True(t, ok)
+
+	now = now.Add(time.Minute)
+	_, _, ok = c.get("a")
+	assert.False(t, ok)
+
+	// A value read before an invalidation must not be stored.
+	_, generation, _ = c.get("d")
+	c.invalidate("e")
+	c.put("d", 4, generation)
+	_, _, ok = c.get("d")
+	assert.False(t, ok)
+
+	_, generation, _ = c.get("d")
+	c.put("d", 4, generation)
+	c.invalidate()
+	_, _, ok = c.get("d")
+	assert.False(t, ok)
+}
+
+func newOperationCounter(counts map[string]int) Interceptor {
+	var mu sync.Mutex
+	return func(ctx context.Context, op Operation, next Invoker) error {
+		mu.Lock()
+		counts[op.Name]++
+		mu.Unlock()
+		return next(ctx)
+	}
+}
+
+func TestGoogleSheetRowStore_Cache(t *testing.T) {
+	counts := make(map[string]int)
+	ctx := context.Background()
+	db, err := NewGoogleSheetRowStoreWithContext(
+		ctx,
+		nil,
+		"spreadsheet",
+		"people",
+		GoogleSheetRowStoreConfig{
+			Columns:       []string{"name", "age", "dob"},
+			MemoryBackend: memory.NewBackend(),
+			Interceptors:  []Interceptor{newOperationCounter(counts)},
+			Cache:         CacheConfig{TTL: time.Minute},
+		},
+	)
+	assert.Nil(t, err)
+	assert.Nil(t, db.Insert(testPerson{"name1", 10, "1999-01-01"}).Exec(ctx))
+
+	for i := 0; i < 2; i++ {
+		var out []testPerson
+		assert.Nil(t, db.Select(&out).Where("name = ?", "name1").Exec(ctx))
+		assert.Equal(t, []testPerson{{"name1", 10, "1999-01-01"}}, out)
+	}
+	assert.Equal(t, 1, counts["QueryRows"])
+
+	var other []testPerson
+	assert.Nil(t, db.Select(&other).Where("name = ?", "name2").Exec(ctx))
+	assert.Empty(t, other)
+	assert.Equal(t, 2, counts["QueryRows"])
+
+	assert.Nil(t, db.Update(map[string]interface{}{"age": 11}).Where("name = ?", "name1").Exec(ctx))
+
+	var out []testPerson
+	assert.Nil(t, db.Select(&out).Where("name = ?", "name1").Exec(ctx))
+	assert.Equal(t, []testPerson{{"name1", 11, "1999-01-01"}}, out)
+}
+
+func TestGoogleSheetRowStore_CacheNotUsedForWrites(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.NewBackend()
+	newStore := func(cache CacheConfig) *GoogleSheetRowStore {
+		db, err := NewGoogleSheetRowStoreWithContext(
+			ctx,
+			nil,
+			"spreadsheet",
+			"people",
+			GoogleSheetRowStoreConfig{Columns: []string{"name", "age", "dob"}, MemoryBackend: backend, Cache: cache},
+		)
+		assert.Nil(t, err)
+		return db
+	}
+	first := newStore(CacheConfig{TTL: time.Minute})
+	second := newStore(CacheConfig{})
+
+	assert.Nil(t, first.Insert(testPerson{"a", 10, "1999-01-01"}).Exec(ctx))
+	_, err := first.Delete().Where("name = ?", "a").Explain(ctx)
+	assert.Nil(t, err)
+
+	// The row no longer matches, so the delete must not use the row numbers looked up by the explanation.
+	assert.Nil(t, second.Update(map[string]interface{}{"name": "b"}).Where("name = ?", "a").Exec(ctx))
+	assert.Nil(t, first.Delete().Where("name = ?", "a").Exec(ctx))
+
+	var out []testPerson
+	assert.Nil(t, second.Select(&out).Exec(ctx))
+	assert.Equal(t, []testPerson{{"b", 10, "1999-01-01"}}, out)
+}
+
+func TestGoogleSheetKVStore_Cache(t *testing.T) {
+	counts := make(map[string]int)
+	ctx := context.Background()
+	kv, err := NewGoogleSheetKVStoreWithContext(
+		ctx,
+		nil,
+		"spreadsheet",
+		"kv",
+		GoogleSheetKVStoreConfig{
+			Mode:          models.KVModeDefault,
+			MemoryBackend: memory.NewBackend(),
+			Interceptors:  []Interceptor{newOperationCounter(counts)},
+			Cache:         CacheConfig{TTL: time.Minute},
+		},
+	)
+	assert.Nil(t, err)
+	assert.Nil(t, kv.Set(ctx, "k1", []byte("test")))
+
+	counts["UpdateRows"] = 0
+	for i := 0; i < 2; i++ {
+		value, err := kv.Get(ctx, "k1")
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("test"), value)
+
+		// Modifying the returned value must not modify the cached one.
+		value[0] = 'x'
+	}
+	assert.Equal(t, 1, counts["UpdateRows"])
+
+	assert.Nil(t, kv.Set(ctx, "k1", []byte("updated")))
+	value, err := kv.Get(ctx, "k1")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("updated"), value)
+
+	assert.Nil(t, kv.Delete(ctx, "k1"))
+	_, err = kv.Get(ctx, "k1")
+	assert.ErrorIs(t, err, models.ErrKeyNotFound)
+}
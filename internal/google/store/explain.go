@@ -0,0 +1,97 @@
+package store
+
+import "context"
+
+// Explanation describes how a statement is executed, without executing its writes.
+type Explanation struct {
+	// Query is the generated Google Visualization query, using column letters instead of column names.
+	Query string
+
+	// Columns maps every column name of the store (including the internal row index column) into its column letter.
+	Columns map[string]string
+
+	// Ranges are the A1 notation ranges that would be written (for update statements)
+	// or cleared (for delete statements). It is empty for select and count statements.
+	Ranges []string
+}
+
+func (s *GoogleSheetRowStore) explain(query string, ranges []string) Explanation {
+	return Explanation{
+		Query:   query,
+		Columns: s.colsMapping.NameMap(),
+		Ranges:  ranges,
+	}
+}
+
+// ToQuery returns the generated Google Visualization query without executing the statement.
+func (s *GoogleSheetSelectStmt) ToQuery() (string, error) {
+	if s.single {
+		s.queryBuilder.Limit(1)
+	}
+	return s.queryBuilder.Generate()
+}
+
+// Explain returns the generated query and the column mapping without executing the statement.
+func (s *GoogleSheetSelectStmt) Explain(ctx context.Context) (Explanation, error) {
+	query, err := s.ToQuery()
+	if err != nil {
+		return Explanation{}, err
+	}
+	return s.store.explain(query, nil), nil
+}
+
+// ToQuery returns the generated Google Visualization query for finding the rows to update.
+func (s *GoogleSheetUpdateStmt) ToQuery() (string, error) {
+	return s.queryBuilder.Generate()
+}
+
+// Explain finds the rows matching the condition and returns the ranges that would be updated,
+// without updating them. There is only 1 API call behind the scene.
+func (s *GoogleSheetUpdateStmt) Explain(ctx context.Context) (Explanation, error) {
+	query, err := s.ToQuery()
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	requests, rawRequests, err := s.plan(ctx)
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	ranges := append(requestRanges(requests), requestRanges(rawRequests)...)
+	return s.store.explain(query, ranges), nil
+}
+
+// ToQuery returns the generated Google Visualization query for finding the rows to delete.
+func (s *GoogleSheetDeleteStmt) ToQuery() (string, error) {
+	return s.queryBuilder.Generate()
+}
+
+// Explain finds the rows matching the condition and returns the ranges that would be cleared,
+// without clearing them. There is only 1 API call behind the scene.
+func (s *GoogleSheetDeleteStmt) Explain(ctx context.Context) (Explanation, error) {
+	query, err := s.ToQuery()
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	ranges, err := s.plan(ctx)
+	if err != nil {
+		return Explanation{}, err
+	}
+	return s.store.explain(query, ranges), nil
+}
+
+// ToQuery returns the generated Google Visualization query without executing the statement.
+func (s *GoogleSheetCountStmt) ToQuery() (string, error) {
+	return s.queryBuilder.Generate()
+}
+
+// Explain returns the generated query and the column mapping without executing the statement.
+func (s *GoogleSheetCountStmt) Explain(ctx context.Context) (Explanation, error) {
+	query, err := s.ToQuery()
+	if err != nil {
+		return Explanation{}, err
+	}
+	return s.store.explain(query, nil), nil
+}
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreeLeh/GoFreeDB/internal/google/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStmt_Explain(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.NewBackend()
+	db, err := NewGoogleSheetRowStoreWithContext(
+		ctx,
+		nil,
+		"spreadsheet",
+		"people",
+		GoogleSheetRowStoreConfig{Columns: []string{"name", "age", "dob"}, MemoryBackend: backend},
+	)
+	assert.Nil(t, err)
+	assert.Nil(t, db.Insert(
+		testPerson{"name1", 10, "1999-01-01"},
+		testPerson{"name2", 11, "2000-01-01"},
+		testPerson{"name3", 10, "2001-01-01"},
+	).Exec(ctx))
+
+	columns := map[string]string{rowIdxCol: "A", "name": "B", "age": "C", "dob": "D"}
+
+	var out []testPerson
+	query, err := db.Select(&out, "name").Where("age = ?", 10).Limit(5).ToQuery()
+	assert.Nil(t, err)
+	assert.Equal(t, "select B where A is not null AND C = 10  limit 5", query)
+
+	var one testPerson
+	explanation, err := db.SelectOne(&one).Where("name = ?", "name1").Explain(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, Explanation{
+		Query:   `select A, B, C, D where A is not null AND B = "name1"  limit 1`,
+		Columns: columns,
+	}, explanation)
+
+	explanation, err = db.Count().Explain(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "select COUNT(A) where A is not null", explanation.Query)
+	assert.Empty(t, explanation.Ranges)
+
+	explanation, err = db.Update(map[string]interface{}{"name": "updated"}).Where("age = ?", 10).Explain(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, Explanation{
+		Query:   "select A where A is not null AND C = 10 ",
+		Columns: columns,
+		Ranges:  []string{"people!B2", "people!B4"},
+	}, explanation)
+
+	explanation, err = db.Delete().Where("name = ?", "name2").Explain(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"people!A3:Z3"}, explanation.Ranges)
+
+	// Nothing is changed by Explain.
+	count, err := db.Count().Where("name = ?", "updated").Exec(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), count)
+}
+
+func TestGoogleSheetRowStore_DryRun(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.NewBackend()
+	db, err := NewGoogleSheetRowStoreWithContext(
+		ctx,
+		nil,
+		"spreadsheet",
+		"people",
+		GoogleSheetRowStoreConfig{Columns: []string{"name", "age", "dob"}, MemoryBackend: backend},
+	)
+	assert.Nil(t, err)
+	assert.Nil(t, db.Insert(testPerson{"name1", 10, "1999-01-01"}).Exec(ctx))
+
+	dryRun, err := NewGoogleSheetRowStoreWithContext(
+		ctx,
+		nil,
+		"spreadsheet",
+		"people",
+		GoogleSheetRowStoreConfig{Columns: []string{"name", "age", "dob"}, MemoryBackend: backend, DryRun: true},
+	)
+	assert.Nil(t, err)
+
+	assert.Nil(t, dryRun.Insert(testPerson{"name2", 11, "2000-01-01"}).Exec(ctx))
+	assert.Nil(t, dryRun.Update(map[string]interface{}{"name": "updated"}).Exec(ctx))
+	assert.Nil(t, dryRun.Delete().Exec(ctx))
+	assert.NotNil(t, dryRun.Insert(nil).Exec(ctx))
+
+	var out []testPerson
+	assert.Nil(t, dryRun.Select(&out).Exec(ctx))
+	assert.Equal(t, []testPerson{{"name1", 10, "1999-01-01"}}, out)
+}
@@ -0,0 +1,319 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/FreeLeh/GoFreeDB/internal/models"
+)
+
+const (
+	defaultInserterBatchSize     = 500
+	defaultInserterFlushInterval = time.Second
+)
+
+// GoogleSheetInserterConfig defines the batching behaviour of a GoogleSheetInserter.
+type GoogleSheetInserterConfig struct {
+	// BatchSize defines the maximum number of rows appended by a single write.
+	// The buffered rows are written as soon as there are BatchSize of them. The default value is 500.
+	BatchSize int
+
+	// FlushInterval defines how long a row stays buffered at most before being written.
+	// The default value is 1 second.
+	FlushInterval time.Duration
+
+	// BufferSize defines the number of rows accepted by GoogleSheetInserter.Insert while a write is in progress.
+	// Insert blocks once the buffer is full (backpressure). The default value is BatchSize.
+	BufferSize int
+
+	// OnError is called from the background goroutine with the error and the rows of every failed write,
+	// e.g. for logging or for saving the rows somewhere else. The rows are not retried by the inserter.
+	// Configure GoogleSheetRowStoreConfig.RetryPolicy to retry the failed Google Sheets API calls instead.
+	OnError func(err error, rows []interface{})
+}
+
+func (c GoogleSheetInserterConfig) withDefaults() GoogleSheetInserterConfig {
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaultInserterBatchSize
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = defaultInserterFlushInterval
+	}
+	if c.BufferSize <= 0 {
+		c.BufferSize = c.BatchSize
+	}
+	return c
+}
+
+// GoogleSheetInserter inserts rows into a GoogleSheetRowStore asynchronously.
+//
+// The rows are buffered and written by a background goroutine in batches, each batch being a single insertion
+// (see GoogleSheetInsertStmt.Exec). A batch is written once it has GoogleSheetInserterConfig.BatchSize rows, or once
+// its first row has been buffered for GoogleSheetInserterConfig.FlushInterval.
+//
+// The usage looks like this:
+//
+//	inserter := store.NewInserter(freedb.GoogleSheetInserterConfig{BatchSize: 100})
+//	defer inserter.Close(ctx)
+//
+//	if err := inserter.Insert(ctx, Event{Name: "login"}); err != nil {
+//		return err
+//	}
+//
+// Use Flush to wait until the buffered rows have been written. Close must be called to write the remaining rows
+// and to stop the background goroutine.
+type GoogleSheetInserter struct {
+	stmt   *GoogleSheetInsertStmt
+	config GoogleSheetInserterConfig
+
+	rows     chan inserterRow
+	flushes  chan inserterFlush
+	closing  chan struct{}
+	stopped  chan struct{}
+	closeCtx context.Context
+	closeErr error
+
+	// writeCtx is used by the writes made in the background, cancelWrites is called by Close once its context is done.
+	writeCtx     context.Context
+	cancelWrites context.CancelFunc
+
+	mu     sync.Mutex
+	closed bool
+	// senders tracks the Insert calls sending rows, so that the rows they send before Close are not lost.
+	senders sync.WaitGroup
+
+	// The failure fields are only accessed by the background goroutine.
+	failedRows int
+	failure    error
+}
+
+type inserterRow struct {
+	row       interface{}
+	converted []interface{}
+}
+
+type inserterFlush struct {
+	ctx    context.Context
+	result chan error
+}
+
+// NewInserter creates a GoogleSheetInserter writing into the store, and starts its background goroutine.
+func (s *GoogleSheetRowStore) NewInserter(config GoogleSheetInserterConfig) *GoogleSheetInserter {
+	config = config.withDefaults()
+	writeCtx, cancelWrites := context.WithCancel(context.Background())
+	inserter := &GoogleSheetInserter{
+		stmt:         newGoogleSheetInsertStmt(s, nil),
+		config:       config,
+		rows:         make(chan inserterRow, config.BufferSize),
+		flushes:      make(chan inserterFlush),
+		closing:      make(chan struct{}),
+		stopped:      make(chan struct{}),
+		writeCtx:     writeCtx,
+		cancelWrites: cancelWrites,
+	}
+	go inserter.run()
+	return inserter
+}
+
+// Insert buffers the rows to be written by the background goroutine.
+// The rows follow the same rules as GoogleSheetRowStore.Insert, and are converted before Insert returns,
+// so a row that cannot be converted is reported here without buffering any of the rows.
+//
+// Insert blocks while the buffer is full. If the context is done or Close is called in the meantime, the context
+// error or ErrInserterClosed is returned, and only the rows before the first one that could not be buffered are
+// written. ErrInserterClosed is also returned once Close has been called.
+func (i *GoogleSheetInserter) Insert(ctx context.Context, rows ...interface{}) error {
+	converted, err := i.stmt.convertRows(rows)
+	if err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	if i.closed {
+		i.mu.Unlock()
+		return models.ErrInserterClosed
+	}
+	i.senders.Add(1)
+	i.mu.Unlock()
+	defer i.senders.Done()
+
+	for idx, row := range rows {
+		select {
+		case i.rows <- inserterRow{row: row, converted: converted[idx]}:
+		case <-i.closing:
+			return models.ErrInserterClosed
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Flush writes all rows buffered by the Insert calls returned before Flush is called, and waits for the writes.
+//
+// It returns nil only if all such rows have been written, including the rows written in the background
+// since the previous Flush call. Otherwise, the returned error contains the number of rows that failed.
+// The writes use the given context, and the context error is returned if the context is done before they finish.
+func (i *GoogleSheetInserter) Flush(ctx context.Context) error {
+	i.mu.Lock()
+	closed := i.closed
+	i.mu.Unlock()
+
+	if closed {
+		return models.ErrInserterClosed
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	req := inserterFlush{ctx: ctx, result: make(chan error, 1)}
+	select {
+	case i.flushes <- req:
+	case <-i.stopped:
+		return models.ErrInserterClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-req.result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting new rows, writes all buffered rows and stops the background goroutine.
+// The returned error follows the same rules as Flush. Calling Close more than once returns nil.
+//
+// The remaining writes use the given context. If the context is done before they finish, the context error is
+// returned and the write in progress in the background is cancelled, so that the background goroutine stops
+// without waiting for it.
+func (i *GoogleSheetInserter) Close(ctx context.Context) error {
+	i.mu.Lock()
+	if i.closed {
+		i.mu.Unlock()
+		return nil
+	}
+	i.closed = true
+	i.closeCtx = ctx
+	close(i.closing)
+	i.mu.Unlock()
+
+	select {
+	case <-i.stopped:
+		return i.closeErr
+	case <-ctx.Done():
+		i.cancelWrites()
+		return ctx.Err()
+	}
+}
+
+func (i *GoogleSheetInserter) run() {
+	defer close(i.stopped)
+	defer i.cancelWrites()
+
+	var (
+		buffer []inserterRow
+		timer  *time.Timer
+		timerC <-chan time.Time
+	)
+	stopTimer := func() {
+		if timer != nil {
+			timer.Stop()
+			timer, timerC = nil, nil
+		}
+	}
+
+	for {
+		select {
+		case row := <-i.rows:
+			buffer = append(buffer, row)
+			if len(buffer) >= i.config.BatchSize {
+				stopTimer()
+				i.writeBatch(i.writeCtx, buffer)
+				buffer = nil
+			} else if timer == nil {
+				timer = time.NewTimer(i.config.FlushInterval)
+				timerC = timer.C
+			}
+		case <-timerC:
+			timer, timerC = nil, nil
+			i.writeBatch(i.writeCtx, buffer)
+			buffer = nil
+		case req := <-i.flushes:
+			stopTimer()
+			req.result <- i.flush(req.ctx, i.drain(buffer))
+			buffer = nil
+		case <-i.closing:
+			stopTimer()
+			// The Insert calls return as soon as they observe the closing, the rows they sent before are drained.
+			i.senders.Wait()
+			i.closeErr = i.flush(i.closeCtx, i.drain(buffer))
+			return
+		}
+	}
+}
+
+// drain moves the rows accepted by Insert into the buffer without blocking.
+func (i *GoogleSheetInserter) drain(buffer []inserterRow) []inserterRow {
+	for {
+		select {
+		case row := <-i.rows:
+			buffer = append(buffer, row)
+		default:
+			return buffer
+		}
+	}
+}
+
+// flush writes the buffer in batches and reports the failures since the previous flush.
+func (i *GoogleSheetInserter) flush(ctx context.Context, buffer []inserterRow) error {
+	for start := 0; start < len(buffer); start += i.config.BatchSize {
+		end := start + i.config.BatchSize
+		if end > len(buffer) {
+			end = len(buffer)
+		}
+		i.writeBatch(ctx, buffer[start:end])
+	}
+
+	if i.failure == nil {
+		return nil
+	}
+
+	err := fmt.Errorf("failed inserting %d rows: %w", i.failedRows, i.failure)
+	i.failedRows, i.failure = 0, nil
+	return err
+}
+
+func (i *GoogleSheetInserter) writeBatch(ctx context.Context, batch []inserterRow) {
+	if len(batch) == 0 {
+		return
+	}
+
+	converted := make([][]interface{}, len(batch))
+	for idx, row := range batch {
+		converted[idx] = row.converted
+	}
+
+	err := i.stmt.store.intercept(ctx, "Insert", nil, func(ctx context.Context) error {
+		return i.stmt.write(ctx, converted)
+	})
+	if err == nil {
+		return
+	}
+
+	i.failedRows += len(batch)
+	if i.failure == nil {
+		i.failure = err
+	}
+	if i.config.OnError != nil {
+		rows := make([]interface{}, len(batch))
+		for idx, row := range batch {
+			rows[idx] = row.row
+		}
+		i.config.OnError(err, rows)
+	}
+}
@@ -0,0 +1,220 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FreeLeh/GoFreeDB/internal/google/memory"
+	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
+	"github.com/FreeLeh/GoFreeDB/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoogleSheetInserter(t *testing.T) {
+	counts := make(map[string]int)
+	ctx := context.Background()
+	db, err := NewGoogleSheetRowStoreWithContext(
+		ctx,
+		nil,
+		"spreadsheet",
+		"people",
+		GoogleSheetRowStoreConfig{
+			Columns:       []string{"name", "age", "dob"},
+			MemoryBackend: memory.NewBackend(),
+			Interceptors:  []Interceptor{newOperationCounter(counts)},
+		},
+	)
+	assert.Nil(t, err)
+
+	counts["OverwriteRows"] = 0
+	inserter := db.NewInserter(GoogleSheetInserterConfig{BatchSize: 2, FlushInterval: time.Hour})
+	assert.NotNil(t, inserter.Insert(ctx, nil))
+
+	for _, p := range []testPerson{
+		{"name1", 10, "1999-01-01"},
+		{"name2", 11, "2000-01-01"},
+		{"name3", 12, "2001-01-01"},
+	} {
+		assert.Nil(t, inserter.Insert(ctx, p))
+	}
+	assert.Nil(t, inserter.Insert(ctx, testPerson{"name4", 13, "2002-01-01"}, testPerson{"name5", 14, "2003-01-01"}))
+	assert.Nil(t, inserter.Flush(ctx))
+	assert.Equal(t, 3, counts["OverwriteRows"])
+
+	var out []testPerson
+	assert.Nil(t, db.Select(&out).Exec(ctx))
+	assert.Equal(t, []testPerson{
+		{"name1", 10, "1999-01-01"},
+		{"name2", 11, "2000-01-01"},
+		{"name3", 12, "2001-01-01"},
+		{"name4", 13, "2002-01-01"},
+		{"name5", 14, "2003-01-01"},
+	}, out)
+
+	assert.Nil(t, inserter.Insert(ctx, testPerson{"name6", 15, "2004-01-01"}))
+	assert.Nil(t, inserter.Close(ctx))
+	assert.Nil(t, inserter.Close(ctx))
+	assert.ErrorIs(t, inserter.Insert(ctx, testPerson{"name7", 16, "2005-01-01"}), models.ErrInserterClosed)
+	assert.ErrorIs(t, inserter.Flush(ctx), models.ErrInserterClosed)
+
+	count, err := db.Count().Exec(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(6), count)
+}
+
+func TestGoogleSheetInserter_FlushInterval(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewGoogleSheetRowStoreWithContext(
+		ctx,
+		nil,
+		"spreadsheet",
+		"people",
+		GoogleSheetRowStoreConfig{Columns: []string{"name", "age", "dob"}, MemoryBackend: memory.NewBackend()},
+	)
+	assert.Nil(t, err)
+
+	inserter := db.NewInserter(GoogleSheetInserterConfig{FlushInterval: 10 * time.Millisecond})
+	defer inserter.Close(ctx)
+	assert.Nil(t, inserter.Insert(ctx, testPerson{"name1", 10, "1999-01-01"}))
+
+	assert.Eventually(t, func() bool {
+		count, err := db.Count().Exec(ctx)
+		return err == nil && count == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+type failingInsertWrapper struct {
+	SheetsWrapper
+	err error
+}
+
+func (w *failingInsertWrapper) OverwriteRows(
+	ctx context.Context,
+	spreadsheetID string,
+	a1Range string,
+	values [][]interface{},
+) (sheets.InsertRowsResult, error) {
+	if w.err != nil {
+		return sheets.InsertRowsResult{}, w.err
+	}
+	return w.SheetsWrapper.OverwriteRows(ctx, spreadsheetID, a1Range, values)
+}
+
+func TestGoogleSheetInserter_Failure(t *testing.T) {
+	ctx := context.Background()
+	wrapper := &failingInsertWrapper{
+		SheetsWrapper: memory.NewWrapper(memory.NewBackend(), sheets.ValueRenderFormatted),
+	}
+	db, err := NewGoogleSheetRowStoreWithContext(
+		ctx,
+		nil,
+		"spreadsheet",
+		"people",
+		GoogleSheetRowStoreConfig{Columns: []string{"name", "age", "dob"}, Wrapper: wrapper},
+	)
+	assert.Nil(t, err)
+
+	var failedRows []interface{}
+	inserter := db.NewInserter(GoogleSheetInserterConfig{
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+		OnError: func(err error, rows []interface{}) {
+			failedRows = append(failedRows, rows...)
+		},
+	})
+
+	expectedErr := errors.New("error")
+	wrapper.err = expectedErr
+	rows := []interface{}{
+		testPerson{"name1", 10, "1999-01-01"},
+		testPerson{"name2", 11, "2000-01-01"},
+		testPerson{"name3", 12, "2001-01-01"},
+	}
+	assert.Nil(t, inserter.Insert(ctx, rows...))
+
+	err = inserter.Flush(ctx)
+	assert.ErrorIs(t, err, expectedErr)
+	assert.Contains(t, err.Error(), "failed inserting 3 rows")
+	assert.Equal(t, rows, failedRows)
+
+	// The failures are reported only once.
+	assert.Nil(t, inserter.Flush(ctx))
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, inserter.Flush(cancelled), context.Canceled)
+
+	wrapper.err = nil
+	assert.Nil(t, inserter.Insert(ctx, testPerson{"name4", 13, "2002-01-01"}))
+	assert.Nil(t, inserter.Close(ctx))
+
+	count, err := db.Count().Exec(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), count)
+}
+
+type blockingInsertWrapper struct {
+	SheetsWrapper
+	started chan struct{}
+}
+
+func (w *blockingInsertWrapper) OverwriteRows(
+	ctx context.Context,
+	spreadsheetID string,
+	a1Range string,
+	values [][]interface{},
+) (sheets.InsertRowsResult, error) {
+	select {
+	case w.started <- struct{}{}:
+	default:
+	}
+	<-ctx.Done()
+	return sheets.InsertRowsResult{}, ctx.Err()
+}
+
+func TestGoogleSheetInserter_CloseTimeout(t *testing.T) {
+	ctx := context.Background()
+	wrapper := &blockingInsertWrapper{
+		SheetsWrapper: memory.NewWrapper(memory.NewBackend(), sheets.ValueRenderFormatted),
+		started:       make(chan struct{}, 1),
+	}
+	db, err := NewGoogleSheetRowStoreWithContext(
+		ctx,
+		nil,
+		"spreadsheet",
+		"people",
+		GoogleSheetRowStoreConfig{Columns: []string{"name", "age", "dob"}, Wrapper: wrapper},
+	)
+	assert.Nil(t, err)
+
+	inserter := db.NewInserter(GoogleSheetInserterConfig{BatchSize: 1, BufferSize: 1, FlushInterval: time.Hour})
+
+	// The first row is being written, the second one fills the buffer and the third one blocks.
+	assert.Nil(t, inserter.Insert(ctx, testPerson{"name1", 10, "1999-01-01"}))
+	<-wrapper.started
+	assert.Nil(t, inserter.Insert(ctx, testPerson{"name2", 11, "2000-01-01"}))
+
+	blocked := make(chan error, 1)
+	go func() {
+		blocked <- inserter.Insert(ctx, testPerson{"name3", 12, "2001-01-01"})
+	}()
+
+	closeCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, inserter.Close(closeCtx), context.DeadlineExceeded)
+
+	select {
+	case err := <-blocked:
+		assert.ErrorIs(t, err, models.ErrInserterClosed)
+	case <-time.After(time.Second):
+		t.Fatal("Insert is still blocked after Close")
+	}
+
+	select {
+	case <-inserter.stopped:
+	case <-time.After(time.Second):
+		t.Fatal("the background goroutine did not stop after Close")
+	}
+}
@@ -0,0 +1,301 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
+)
+
+// OperationKind defines whether an Operation is a statement execution or a SheetsWrapper call.
+type OperationKind string
+
+const (
+	// OperationKindStatement is the execution of a row store statement, e.g. GoogleSheetSelectStmt.Exec.
+	// A statement execution consists of one or more wrapper operations.
+	OperationKindStatement OperationKind = "statement"
+
+	// OperationKindWrapper is a single SheetsWrapper method call, which usually is a single Google Sheets API call.
+	OperationKindWrapper OperationKind = "wrapper"
+)
+
+// Operation describes an operation observed by an Interceptor.
+type Operation struct {
+	Kind OperationKind
+
+	// Name is either the statement name (e.g. "Select") or the SheetsWrapper method name (e.g. "QueryRows").
+	Name string
+
+	SpreadsheetID string
+
+	// SheetName is empty for wrapper operations working on A1 notation ranges, see Ranges.
+	SheetName string
+
+	// Query is the generated Google Visualization query, if the operation has one.
+	Query string
+
+	// Ranges are the A1 notation ranges read or written by the operation, if any.
+	Ranges []string
+}
+
+// Attribute is a key-value pair describing an operation, e.g. for span attributes or metric labels.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attributes returns the non-empty operation fields as attributes prefixed with "freedb.".
+func (o Operation) Attributes() []Attribute {
+	attrs := []Attribute{
+		{Key: "freedb.kind", Value: string(o.Kind)},
+		{Key: "freedb.operation", Value: o.Name},
+		{Key: "freedb.spreadsheet_id", Value: o.SpreadsheetID},
+	}
+	if o.SheetName != "" {
+		attrs = append(attrs, Attribute{Key: "freedb.sheet", Value: o.SheetName})
+	}
+	if o.Query != "" {
+		attrs = append(attrs, Attribute{Key: "freedb.query", Value: o.Query})
+	}
+	if len(o.Ranges) > 0 {
+		attrs = append(attrs, Attribute{Key: "freedb.ranges", Value: o.Ranges})
+	}
+	return attrs
+}
+
+// Invoker runs the intercepted operation, or the next interceptor in the chain.
+type Invoker func(ctx context.Context) error
+
+// Interceptor is called around every statement execution and SheetsWrapper call of a store.
+//
+// It must call next exactly once to run the operation, optionally with a derived context (e.g. containing a span),
+// and should return the error returned by next. The interceptors are called in the configured order,
+// i.e. the first interceptor is the outermost one.
+type Interceptor func(ctx context.Context, op Operation, next Invoker) error
+
+// runInterceptors runs the operation through the interceptors.
+func runInterceptors(ctx context.Context, interceptors []Interceptor, op Operation, invoke Invoker) error {
+	if len(interceptors) == 0 {
+		return invoke(ctx)
+	}
+	return interceptors[0](ctx, op, func(ctx context.Context) error {
+		return runInterceptors(ctx, interceptors[1:], op, invoke)
+	})
+}
+
+// Span is the subset of an OpenTelemetry span used by NewTracingInterceptor.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Tracer is the subset of an OpenTelemetry tracer used by NewTracingInterceptor.
+// An adapter for go.opentelemetry.io/otel/trace.Tracer only needs to convert the attributes.
+type Tracer interface {
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// NewTracingInterceptor creates an Interceptor starting a span named "freedb.<operation name>" for every operation.
+// The span has the Operation.Attributes and records the error, if any.
+// The wrapper operation spans are children of the statement span, as the span context is passed down.
+func NewTracingInterceptor(tracer Tracer) Interceptor {
+	return func(ctx context.Context, op Operation, next Invoker) error {
+		ctx, span := tracer.Start(ctx, "freedb."+op.Name)
+		defer span.End()
+
+		span.SetAttributes(op.Attributes()...)
+		err := next(ctx)
+		if err != nil {
+			span.RecordError(err)
+		}
+		return err
+	}
+}
+
+// MetricsRecorder records the outcome of every operation, e.g. into an OpenTelemetry counter and histogram.
+type MetricsRecorder interface {
+	RecordOperation(ctx context.Context, op Operation, duration time.Duration, err error)
+}
+
+// NewMetricsInterceptor creates an Interceptor recording the duration and the error of every operation.
+func NewMetricsInterceptor(recorder MetricsRecorder) Interceptor {
+	return func(ctx context.Context, op Operation, next Invoker) error {
+		start := time.Now()
+		err := next(ctx)
+		recorder.RecordOperation(ctx, op, time.Since(start), err)
+		return err
+	}
+}
+
+// interceptedWrapper runs every SheetsWrapper call through the interceptors.
+type interceptedWrapper struct {
+	wrapper      SheetsWrapper
+	interceptors []Interceptor
+}
+
+// interceptWrapper returns the wrapper as is if there is no interceptor.
+func interceptWrapper(wrapper SheetsWrapper, interceptors []Interceptor) SheetsWrapper {
+	if len(interceptors) == 0 {
+		return wrapper
+	}
+	return &interceptedWrapper{wrapper: wrapper, interceptors: interceptors}
+}
+
+func (w *interceptedWrapper) run(ctx context.Context, op Operation, invoke Invoker) error {
+	op.Kind = OperationKindWrapper
+	return runInterceptors(ctx, w.interceptors, op, invoke)
+}
+
+func (w *interceptedWrapper) CreateSpreadsheet(ctx context.Context, title string) (string, error) {
+	var result string
+	err := w.run(ctx, Operation{Name: "CreateSpreadsheet"}, func(ctx context.Context) (err error) {
+		result, err = w.wrapper.CreateSpreadsheet(ctx, title)
+		return err
+	})
+	return result, err
+}
+
+func (w *interceptedWrapper) GetSheetNameToID(ctx context.Context, spreadsheetID string) (map[string]int64, error) {
+	var result map[string]int64
+	op := Operation{Name: "GetSheetNameToID", SpreadsheetID: spreadsheetID}
+	err := w.run(ctx, op, func(ctx context.Context) (err error) {
+		result, err = w.wrapper.GetSheetNameToID(ctx, spreadsheetID)
+		return err
+	})
+	return result, err
+}
+
+func (w *interceptedWrapper) CreateSheet(ctx context.Context, spreadsheetID string, sheetName string) error {
+	op := Operation{Name: "CreateSheet", SpreadsheetID: spreadsheetID, SheetName: sheetName}
+	return w.run(ctx, op, func(ctx context.Context) error {
+		return w.wrapper.CreateSheet(ctx, spreadsheetID, sheetName)
+	})
+}
+
+func (w *interceptedWrapper) CreateSheetWithGridSize(
+	ctx context.Context,
+	spreadsheetID string,
+	sheetName string,
+	size sheets.GridSize,
+) error {
+	op := Operation{Name: "CreateSheetWithGridSize", SpreadsheetID: spreadsheetID, SheetName: sheetName}
+	return w.run(ctx, op, func(ctx context.Context) error {
+		return w.wrapper.CreateSheetWithGridSize(ctx, spreadsheetID, sheetName, size)
+	})
+}
+
+func (w *interceptedWrapper) DeleteSheets(ctx context.Context, spreadsheetID string, sheetIDs []int64) error {
+	op := Operation{Name: "DeleteSheets", SpreadsheetID: spreadsheetID}
+	return w.run(ctx, op, func(ctx context.Context) error {
+		return w.wrapper.DeleteSheets(ctx, spreadsheetID, sheetIDs)
+	})
+}
+
+func (w *interceptedWrapper) InsertRows(
+	ctx context.Context,
+	spreadsheetID string,
+	a1Range string,
+	values [][]interface{},
+) (sheets.InsertRowsResult, error) {
+	var result sheets.InsertRowsResult
+	op := Operation{Name: "InsertRows", SpreadsheetID: spreadsheetID, Ranges: []string{a1Range}}
+	err := w.run(ctx, op, func(ctx context.Context) (err error) {
+		result, err = w.wrapper.InsertRows(ctx, spreadsheetID, a1Range, values)
+		return err
+	})
+	return result, err
+}
+
+func (w *interceptedWrapper) OverwriteRows(
+	ctx context.Context,
+	spreadsheetID string,
+	a1Range string,
+	values [][]interface{},
+) (sheets.InsertRowsResult, error) {
+	var result sheets.InsertRowsResult
+	op := Operation{Name: "OverwriteRows", SpreadsheetID: spreadsheetID, Ranges: []string{a1Range}}
+	err := w.run(ctx, op, func(ctx context.Context) (err error) {
+		result, err = w.wrapper.OverwriteRows(ctx, spreadsheetID, a1Range, values)
+		return err
+	})
+	return result, err
+}
+
+func (w *interceptedWrapper) UpdateRows(
+	ctx context.Context,
+	spreadsheetID string,
+	a1Range string,
+	values [][]interface{},
+) (sheets.UpdateRowsResult, error) {
+	var result sheets.UpdateRowsResult
+	op := Operation{Name: "UpdateRows", SpreadsheetID: spreadsheetID, Ranges: []string{a1Range}}
+	err := w.run(ctx, op, func(ctx context.Context) (err error) {
+		result, err = w.wrapper.UpdateRows(ctx, spreadsheetID, a1Range, values)
+		return err
+	})
+	return result, err
+}
+
+func (w *interceptedWrapper) BatchUpdateRows(
+	ctx context.Context,
+	spreadsheetID string,
+	requests []sheets.BatchUpdateRowsRequest,
+) (sheets.BatchUpdateRowsResult, error) {
+	var result sheets.BatchUpdateRowsResult
+	op := Operation{Name: "BatchUpdateRows", SpreadsheetID: spreadsheetID, Ranges: requestRanges(requests)}
+	err := w.run(ctx, op, func(ctx context.Context) (err error) {
+		result, err = w.wrapper.BatchUpdateRows(ctx, spreadsheetID, requests)
+		return err
+	})
+	return result, err
+}
+
+func (w *interceptedWrapper) BatchUpdateRowsWithOption(
+	ctx context.Context,
+	spreadsheetID string,
+	requests []sheets.BatchUpdateRowsRequest,
+	option sheets.ValueInputOption,
+) (sheets.BatchUpdateRowsResult, error) {
+	var result sheets.BatchUpdateRowsResult
+	op := Operation{Name: "BatchUpdateRowsWithOption", SpreadsheetID: spreadsheetID, Ranges: requestRanges(requests)}
+	err := w.run(ctx, op, func(ctx context.Context) (err error) {
+		result, err = w.wrapper.BatchUpdateRowsWithOption(ctx, spreadsheetID, requests, option)
+		return err
+	})
+	return result, err
+}
+
+func (w *interceptedWrapper) QueryRows(
+	ctx context.Context,
+	spreadsheetID string,
+	sheetName string,
+	query string,
+	skipHeader bool,
+) (sheets.QueryRowsResult, error) {
+	var result sheets.QueryRowsResult
+	op := Operation{Name: "QueryRows", SpreadsheetID: spreadsheetID, SheetName: sheetName, Query: query}
+	err := w.run(ctx, op, func(ctx context.Context) (err error) {
+		result, err = w.wrapper.QueryRows(ctx, spreadsheetID, sheetName, query, skipHeader)
+		return err
+	})
+	return result, err
+}
+
+func (w *interceptedWrapper) Clear(ctx context.Context, spreadsheetID string, ranges []string) ([]string, error) {
+	var result []string
+	op := Operation{Name: "Clear", SpreadsheetID: spreadsheetID, Ranges: ranges}
+	err := w.run(ctx, op, func(ctx context.Context) (err error) {
+		result, err = w.wrapper.Clear(ctx, spreadsheetID, ranges)
+		return err
+	})
+	return result, err
+}
+
+func requestRanges(requests []sheets.BatchUpdateRowsRequest) []string {
+	ranges := make([]string, len(requests))
+	for i, req := range requests {
+		ranges[i] = req.A1Range
+	}
+	return ranges
+}
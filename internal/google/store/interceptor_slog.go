@@ -0,0 +1,35 @@
+//go:build go1.21
+
+package store
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// NewSlogInterceptor creates an Interceptor logging every operation with the given logger.
+//
+// Successful operations are logged at the given level, while failed operations are logged at slog.LevelError.
+// The record contains the Operation.Attributes, the duration and the error, if any.
+func NewSlogInterceptor(logger *slog.Logger, level slog.Level) Interceptor {
+	return func(ctx context.Context, op Operation, next Invoker) error {
+		start := time.Now()
+		err := next(ctx)
+
+		opAttrs := op.Attributes()
+		attrs := make([]slog.Attr, 0, len(opAttrs)+2)
+		for _, attr := range opAttrs {
+			attrs = append(attrs, slog.Any(attr.Key, attr.Value))
+		}
+		attrs = append(attrs, slog.Duration("freedb.duration", time.Since(start)))
+
+		logLevel := level
+		if err != nil {
+			logLevel = slog.LevelError
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+		logger.LogAttrs(ctx, logLevel, "freedb operation", attrs...)
+		return err
+	}
+}
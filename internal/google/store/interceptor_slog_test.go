@@ -0,0 +1,40 @@
+//go:build go1.21
+
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSlogInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	interceptor := NewSlogInterceptor(logger, slog.LevelInfo)
+
+	op := Operation{
+		Kind:          OperationKindWrapper,
+		Name:          "QueryRows",
+		SpreadsheetID: "spreadsheet",
+		SheetName:     "sheet",
+		Query:         "select A",
+	}
+	expectedErr := errors.New("query error")
+	err := interceptor(context.Background(), op, func(ctx context.Context) error { return expectedErr })
+	assert.ErrorIs(t, err, expectedErr)
+
+	var record map[string]interface{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, "freedb operation", record["msg"])
+	assert.Equal(t, "QueryRows", record["freedb.operation"])
+	assert.Equal(t, "select A", record["freedb.query"])
+	assert.Equal(t, "query error", record["error"])
+	assert.Contains(t, record, "freedb.duration")
+}
@@ -0,0 +1,167 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FreeLeh/GoFreeDB/internal/google/memory"
+	"github.com/FreeLeh/GoFreeDB/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunInterceptors(t *testing.T) {
+	var calls []string
+	newInterceptor := func(name string) Interceptor {
+		return func(ctx context.Context, op Operation, next Invoker) error {
+			calls = append(calls, name+" before "+op.Name)
+			err := next(ctx)
+			calls = append(calls, name+" after")
+			return err
+		}
+	}
+
+	expectedErr := errors.New("error")
+	err := runInterceptors(
+		context.Background(),
+		[]Interceptor{newInterceptor("first"), newInterceptor("second")},
+		Operation{Name: "op"},
+		func(ctx context.Context) error {
+			calls = append(calls, "invoke")
+			return expectedErr
+		},
+	)
+	assert.ErrorIs(t, err, expectedErr)
+	assert.Equal(t, []string{"first before op", "second before op", "invoke", "second after", "first after"}, calls)
+}
+
+func TestGoogleSheetRowStore_Interceptors(t *testing.T) {
+	var ops []Operation
+	recorder := func(ctx context.Context, op Operation, next Invoker) error {
+		ops = append(ops, op)
+		return next(ctx)
+	}
+
+	ctx := context.Background()
+	db, err := NewGoogleSheetRowStoreWithContext(
+		ctx,
+		nil,
+		"spreadsheet",
+		"people",
+		GoogleSheetRowStoreConfig{
+			Columns:       []string{"name", "age", "dob"},
+			MemoryBackend: memory.NewBackend(),
+			Interceptors:  []Interceptor{recorder},
+		},
+	)
+	assert.Nil(t, err)
+
+	ops = nil
+	count, err := db.Count().Where("name = ?", "name1").Exec(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), count)
+
+	query := "select COUNT(A) where A is not null AND B = \"name1\" "
+	assert.Equal(t, []Operation{
+		{
+			Kind:          OperationKindStatement,
+			Name:          "Count",
+			SpreadsheetID: "spreadsheet",
+			SheetName:     "people",
+			Query:         query,
+		},
+		{
+			Kind:          OperationKindWrapper,
+			Name:          "QueryRows",
+			SpreadsheetID: "spreadsheet",
+			SheetName:     "people",
+			Query:         query,
+		},
+	}, ops)
+
+	ops = nil
+	assert.Nil(t, db.Insert(testPerson{"name1", 10, "1999-01-01"}).Exec(ctx))
+	assert.Equal(t, []Operation{
+		{Kind: OperationKindStatement, Name: "Insert", SpreadsheetID: "spreadsheet", SheetName: "people"},
+		{Kind: OperationKindWrapper, Name: "OverwriteRows", SpreadsheetID: "spreadsheet", Ranges: []string{"people!A2:Z"}},
+	}, ops)
+}
+
+type fakeSpan struct {
+	name  string
+	attrs []Attribute
+	err   error
+	ended bool
+}
+
+func (s *fakeSpan) SetAttributes(attrs ...Attribute) { s.attrs = append(s.attrs, attrs...) }
+func (s *fakeSpan) RecordError(err error)            { s.err = err }
+func (s *fakeSpan) End()                             { s.ended = true }
+
+type fakeTracer struct {
+	spans []*fakeSpan
+}
+
+func (t *fakeTracer) Start(ctx context.Context, spanName string) (context.Context, Span) {
+	span := &fakeSpan{name: spanName}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+type fakeMetricsRecorder struct {
+	ops  []string
+	errs []error
+}
+
+func (r *fakeMetricsRecorder) RecordOperation(ctx context.Context, op Operation, duration time.Duration, err error) {
+	r.ops = append(r.ops, op.Name)
+	r.errs = append(r.errs, err)
+}
+
+func TestTracingAndMetricsInterceptors(t *testing.T) {
+	tracer := &fakeTracer{}
+	recorder := &fakeMetricsRecorder{}
+
+	ctx := context.Background()
+	kv, err := NewGoogleSheetKVStoreV2WithContext(
+		ctx,
+		nil,
+		"spreadsheet",
+		"kv",
+		GoogleSheetKVStoreV2Config{
+			Mode:          models.KVModeDefault,
+			MemoryBackend: memory.NewBackend(),
+			Interceptors:  []Interceptor{NewTracingInterceptor(tracer), NewMetricsInterceptor(recorder)},
+		},
+	)
+	assert.Nil(t, err)
+
+	tracer.spans = nil
+	recorder.ops, recorder.errs = nil, nil
+
+	_, err = kv.Get(ctx, "unknown")
+	assert.ErrorIs(t, err, models.ErrKeyNotFound)
+
+	// The metrics are recorded once the operation completes, so the wrapper operation comes first.
+	assert.Equal(t, []string{"QueryRows", "Select"}, recorder.ops)
+	assert.Equal(t, []error{nil, nil}, recorder.errs)
+
+	assert.Len(t, tracer.spans, 2)
+	assert.Equal(t, "freedb.Select", tracer.spans[0].name)
+	assert.True(t, tracer.spans[0].ended)
+	assert.Contains(t, tracer.spans[0].attrs, Attribute{Key: "freedb.sheet", Value: "kv"})
+	assert.Equal(t, "freedb.QueryRows", tracer.spans[1].name)
+
+	expectedErr := errors.New("error")
+	err = runInterceptors(
+		ctx,
+		[]Interceptor{NewTracingInterceptor(tracer), NewMetricsInterceptor(recorder)},
+		Operation{Name: "Failing"},
+		func(ctx context.Context) error { return expectedErr },
+	)
+	assert.ErrorIs(t, err, expectedErr)
+	assert.ErrorIs(t, tracer.spans[2].err, expectedErr)
+	assert.True(t, tracer.spans[2].ended)
+	assert.ErrorIs(t, recorder.errs[2], expectedErr)
+}
@@ -0,0 +1,212 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
+	"github.com/FreeLeh/GoFreeDB/internal/models"
+)
+
+// GoogleSheetRowIterator iterates through the rows matching a GoogleSheetSelectStmt page by page.
+//
+// The rows are paginated using the row index (keyset pagination) instead of an offset.
+// This guarantees a stable ordering (the insertion order), even if new rows are appended concurrently.
+// Rows appended during the iteration may or may not be returned, but an existing row is never returned twice.
+//
+// The usage is similar to sql.Rows:
+//
+//	rows, err := store.Select(&out, "name", "age").Where("age > ?", 10).PageSize(500).Rows(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	defer rows.Close()
+//
+//	for rows.Next() {
+//		var p Person
+//		if err := rows.Scan(&p); err != nil {
+//			return err
+//		}
+//	}
+//	return rows.Err()
+type GoogleSheetRowIterator struct {
+	ctx       context.Context
+	stmt      *GoogleSheetSelectStmt
+	pageSize  uint64
+	remaining uint64
+	hasLimit  bool
+
+	page    [][]interface{}
+	current []interface{}
+	lastRid int64
+	done    bool
+	closed  bool
+	err     error
+}
+
+// Next prepares the next row to be read using Scan.
+// It returns false if there are no more rows or an error happens (check Err to differentiate both).
+//
+// A new API call is made every time all rows in the current page have been consumed.
+func (r *GoogleSheetRowIterator) Next() bool {
+	if r.closed || r.err != nil {
+		return false
+	}
+	if r.hasLimit && r.remaining == 0 {
+		return false
+	}
+
+	if len(r.page) == 0 {
+		if r.done {
+			return false
+		}
+		if err := r.fetchPage(); err != nil {
+			r.err = err
+			return false
+		}
+		if len(r.page) == 0 {
+			return false
+		}
+	}
+
+	r.current = r.page[0]
+	r.page = r.page[1:]
+	if r.hasLimit {
+		r.remaining--
+	}
+	return true
+}
+
+// Scan decodes the current row into the output, which must be a non-nil pointer to a single value.
+// The supported output types are the same as the slice element types supported by GoogleSheetRowStore.Select.
+func (r *GoogleSheetRowIterator) Scan(output interface{}) error {
+	if r.closed {
+		return errors.New("rows iterator is already closed")
+	}
+	if r.current == nil {
+		return errors.New("scan must be called after a successful next")
+	}
+	if output == nil || reflect.TypeOf(output).Kind() != reflect.Ptr || reflect.ValueOf(output).IsNil() {
+		return errors.New("scan output must be a non-nil pointer")
+	}
+
+	_, err := r.stmt.decodeSingle(sheets.QueryRowsResult{Rows: [][]interface{}{r.current}}, output)
+	return err
+}
+
+// Err returns the error encountered during the iteration, if any.
+func (r *GoogleSheetRowIterator) Err() error {
+	return r.err
+}
+
+// Close stops the iteration.
+// It is safe to call Close multiple times.
+func (r *GoogleSheetRowIterator) Close() error {
+	r.closed = true
+	r.page = nil
+	r.current = nil
+	return nil
+}
+
+func (r *GoogleSheetRowIterator) fetchPage() error {
+	pageSize := r.pageSize
+	if r.hasLimit && r.remaining < pageSize {
+		pageSize = r.remaining
+	}
+
+	query, err := r.pageQueryBuilder(pageSize).Generate()
+	if err != nil {
+		return err
+	}
+
+	result, err := r.stmt.store.wrapper.QueryRows(
+		r.ctx,
+		r.stmt.store.spreadsheetID,
+		r.stmt.store.sheetName,
+		query,
+		true,
+	)
+	if err != nil {
+		return err
+	}
+
+	r.page = make([][]interface{}, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) != len(r.stmt.columns)+1 {
+			return fmt.Errorf("unexpected number of columns in the query result: %d", len(row))
+		}
+
+		rid, ok := row[len(row)-1].(float64)
+		if !ok {
+			return fmt.Errorf("error converting row index, value: %+v", row[len(row)-1])
+		}
+
+		r.lastRid = int64(rid)
+		r.page = append(r.page, row[:len(row)-1])
+	}
+
+	r.done = uint64(len(result.Rows)) < pageSize
+	return nil
+}
+
+// pageQueryBuilder creates a copy of the statement query builder for fetching the next page.
+// The row index column is selected as the last column, so that the next page can start after it.
+func (r *GoogleSheetRowIterator) pageQueryBuilder(pageSize uint64) *queryBuilder {
+	builder := *r.stmt.queryBuilder
+
+	// The statement columns are the aliases, so the page columns are built from the select expressions instead.
+	builder.columns = make([]string, 0, len(r.stmt.queryBuilder.columns)+1)
+	builder.columns = append(builder.columns, r.stmt.queryBuilder.columns...)
+	builder.columns = append(builder.columns, rowIdxCol)
+
+	builder.labels = make([]string, len(builder.columns))
+	copy(builder.labels, r.stmt.queryBuilder.labels)
+
+	lastRid := r.lastRid
+	builder.whereInterceptor = func(where string) string {
+		if where != "" {
+			where = "(" + where + ")"
+		}
+		return fmt.Sprintf("%s AND %s > %d", ridWhereClauseInterceptor(where), rowIdxCol, lastRid)
+	}
+
+	builder.OrderBy([]models.ColumnOrderBy{{Column: rowIdxCol, OrderBy: models.OrderByAsc}})
+	builder.Offset(0)
+	builder.Limit(pageSize)
+	return &builder
+}
+
+// Rows returns an iterator going through the rows matching the statement conditions page by page.
+// This is useful for going through a large number of rows without loading all of them into memory.
+//
+// The rows are always returned in their insertion order, hence OrderBy and Offset cannot be used together with Rows.
+// Limit can still be used to limit the total number of rows returned by the iterator.
+// The "output" provided to GoogleSheetRowStore.Select is not used, use GoogleSheetRowIterator.Scan instead.
+//
+// No API call is made until GoogleSheetRowIterator.Next is called.
+func (s *GoogleSheetSelectStmt) Rows(ctx context.Context) (*GoogleSheetRowIterator, error) {
+	if len(s.queryBuilder.orderBy) > 0 {
+		return nil, errors.New("rows iterator does not support order by, rows are always ordered by insertion order")
+	}
+	if s.queryBuilder.offset > 0 {
+		return nil, errors.New("rows iterator does not support offset")
+	}
+	if s.queryBuilder.distinct {
+		return nil, errors.New("rows iterator does not support distinct")
+	}
+
+	pageSize := s.pageSize
+	if pageSize == 0 {
+		pageSize = defaultRowsPageSize
+	}
+
+	return &GoogleSheetRowIterator{
+		ctx:       ctx,
+		stmt:      s,
+		pageSize:  pageSize,
+		remaining: s.queryBuilder.limit,
+		hasLimit:  s.queryBuilder.limit > 0,
+	}, nil
+}
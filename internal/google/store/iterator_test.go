@@ -0,0 +1,192 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/FreeLeh/GoFreeDB/internal/common"
+	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
+	"github.com/FreeLeh/GoFreeDB/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type pagedQueryWrapper struct {
+	sheets.MockWrapper
+	pages   []sheets.QueryRowsResult
+	err     error
+	queries []string
+}
+
+func (w *pagedQueryWrapper) QueryRows(ctx context.Context, spreadsheetID string, sheetName string, query string, skipHeader bool) (sheets.QueryRowsResult, error) {
+	w.queries = append(w.queries, query)
+	if w.err != nil {
+		return sheets.QueryRowsResult{}, w.err
+	}
+	if len(w.pages) == 0 {
+		return sheets.QueryRowsResult{}, nil
+	}
+
+	page := w.pages[0]
+	w.pages = w.pages[1:]
+	return page, nil
+}
+
+func TestGoogleSheetRowIterator(t *testing.T) {
+	newStore := func(wrapper SheetsWrapper) *GoogleSheetRowStore {
+		return &GoogleSheetRowStore{
+			wrapper:     wrapper,
+			sheetName:   "sheet1",
+			colsMapping: map[string]common.ColIdx{rowIdxCol: {Name: "A", Idx: 0}, "name": {Name: "B", Idx: 1}, "age": {Name: "C", Idx: 2}},
+			config:      GoogleSheetRowStoreConfig{Columns: []string{rowIdxCol, "name", "age"}},
+		}
+	}
+
+	t.Run("multiple_pages", func(t *testing.T) {
+		wrapper := &pagedQueryWrapper{pages: []sheets.QueryRowsResult{
+			{Rows: [][]interface{}{{"name1", float64(10), float64(2)}, {"name2", float64(11), float64(5)}}},
+			{Rows: [][]interface{}{{"name3", float64(12), float64(7)}}},
+		}}
+
+		rows, err := newGoogleSheetSelectStmt(newStore(wrapper), nil, []string{"name", "age"}).
+			Where("age > ? OR name = ?", 1, "name3").
+			PageSize(2).
+			Rows(context.Background())
+		assert.Nil(t, err)
+
+		var out []person
+		for rows.Next() {
+			var p person
+			assert.Nil(t, rows.Scan(&p))
+			out = append(out, p)
+		}
+		assert.Nil(t, rows.Err())
+		assert.Nil(t, rows.Close())
+
+		assert.Equal(t, []person{{Name: "name1", Age: 10}, {Name: "name2", Age: 11}, {Name: "name3", Age: 12}}, out)
+		assert.Equal(t, []string{
+			"select B, C, A where A is not null AND (C > 1 OR B = \"name3\" ) AND A > 0 order by A ASC limit 2",
+			"select B, C, A where A is not null AND (C > 1 OR B = \"name3\" ) AND A > 5 order by A ASC limit 2",
+		}, wrapper.queries)
+	})
+
+	t.Run("exact_page_boundary", func(t *testing.T) {
+		wrapper := &pagedQueryWrapper{pages: []sheets.QueryRowsResult{
+			{Rows: [][]interface{}{{"name1", float64(2)}}},
+		}}
+
+		rows, err := newGoogleSheetSelectStmt(newStore(wrapper), nil, []string{"name"}).PageSize(1).Rows(context.Background())
+		assert.Nil(t, err)
+
+		var names []string
+		for rows.Next() {
+			var name string
+			assert.Nil(t, rows.Scan(&name))
+			names = append(names, name)
+		}
+		assert.Nil(t, rows.Err())
+		assert.Equal(t, []string{"name1"}, names)
+		assert.Equal(t, []string{
+			"select B, A where A is not null AND A > 0 order by A ASC limit 1",
+			"select B, A where A is not null AND A > 2 order by A ASC limit 1",
+		}, wrapper.queries)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		wrapper := &pagedQueryWrapper{pages: []sheets.QueryRowsResult{
+			{Rows: [][]interface{}{{"name1", float64(2)}, {"name2", float64(3)}}},
+			{Rows: [][]interface{}{{"name3", float64(4)}}},
+		}}
+
+		rows, err := newGoogleSheetSelectStmt(newStore(wrapper), nil, []string{"name"}).
+			PageSize(2).
+			Limit(3).
+			Rows(context.Background())
+		assert.Nil(t, err)
+
+		count := 0
+		for rows.Next() {
+			count++
+		}
+		assert.Nil(t, rows.Err())
+		assert.Equal(t, 3, count)
+		assert.Equal(t, []string{
+			"select B, A where A is not null AND A > 0 order by A ASC limit 2",
+			"select B, A where A is not null AND A > 3 order by A ASC limit 1",
+		}, wrapper.queries)
+	})
+
+	t.Run("query_error", func(t *testing.T) {
+		wrapper := &pagedQueryWrapper{err: errors.New("some error")}
+
+		rows, err := newGoogleSheetSelectStmt(newStore(wrapper), nil, []string{"name"}).Rows(context.Background())
+		assert.Nil(t, err)
+		assert.False(t, rows.Next())
+		assert.NotNil(t, rows.Err())
+	})
+
+	t.Run("scan_errors", func(t *testing.T) {
+		wrapper := &pagedQueryWrapper{pages: []sheets.QueryRowsResult{
+			{Rows: [][]interface{}{{"name1", float64(2)}}},
+		}}
+
+		rows, err := newGoogleSheetSelectStmt(newStore(wrapper), nil, []string{"name"}).Rows(context.Background())
+		assert.Nil(t, err)
+
+		var name string
+		assert.NotNil(t, rows.Scan(&name))
+
+		assert.True(t, rows.Next())
+		assert.NotNil(t, rows.Scan(name))
+		assert.Nil(t, rows.Scan(&name))
+
+		assert.Nil(t, rows.Close())
+		assert.False(t, rows.Next())
+		assert.NotNil(t, rows.Scan(&name))
+	})
+
+	t.Run("unsupported_clauses", func(t *testing.T) {
+		store := newStore(&pagedQueryWrapper{})
+
+		_, err := newGoogleSheetSelectStmt(store, nil, []string{"name"}).
+			OrderBy([]models.ColumnOrderBy{{Column: "name", OrderBy: models.OrderByAsc}}).
+			Rows(context.Background())
+		assert.NotNil(t, err)
+
+		_, err = newGoogleSheetSelectStmt(store, nil, []string{"name"}).Offset(10).Rows(context.Background())
+		assert.NotNil(t, err)
+	})
+
+	t.Run("computed_columns", func(t *testing.T) {
+		wrapper := &pagedQueryWrapper{pages: []sheets.QueryRowsResult{
+			{Rows: [][]interface{}{{"name1", float64(20), float64(2)}}},
+			{Rows: [][]interface{}{{"name2", float64(22), float64(3)}}},
+		}}
+
+		type output struct {
+			N      string `db:"n"`
+			Double int64  `db:"double"`
+		}
+
+		rows, err := newGoogleSheetSelectStmt(newStore(wrapper), nil, []string{"name AS n", "age * 2 AS double"}).
+			PageSize(1).
+			Rows(context.Background())
+		assert.Nil(t, err)
+
+		var out []output
+		for rows.Next() {
+			var o output
+			assert.Nil(t, rows.Scan(&o))
+			out = append(out, o)
+		}
+		assert.Nil(t, rows.Err())
+		assert.Nil(t, rows.Close())
+
+		assert.Equal(t, []output{{"name1", 20}, {"name2", 22}}, out)
+		assert.Equal(t, []string{
+			"select B, C * 2, A where A is not null AND A > 0 order by A ASC limit 1 label B 'n', C * 2 'double'",
+			"select B, C * 2, A where A is not null AND A > 2 order by A ASC limit 1 label B 'n', C * 2 'double'",
+			"select B, C * 2, A where A is not null AND A > 3 order by A ASC limit 1 label B 'n', C * 2 'double'",
+		}, wrapper.queries)
+	})
+}
@@ -4,16 +4,68 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/FreeLeh/GoFreeDB/internal/codec"
 	"github.com/FreeLeh/GoFreeDB/internal/common"
 	"github.com/FreeLeh/GoFreeDB/internal/models"
 
+	"github.com/FreeLeh/GoFreeDB/internal/google/file"
+	"github.com/FreeLeh/GoFreeDB/internal/google/memory"
 	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
 )
 
 // GoogleSheetKVStoreConfig defines a list of configurations that can be used to customise how the GoogleSheetKVStore works.
 type GoogleSheetKVStoreConfig struct {
-	Mode  models.KVMode
+	Mode models.KVMode
+
+	// RetryPolicy defines how failed Google Sheets API calls are retried.
+	// The default value disables retries. Use sheets.DefaultRetryPolicy for the recommended policy.
+	RetryPolicy sheets.RetryPolicy
+
+	// RateLimiter limits the number of Google Sheets API calls made by the store.
+	// Share the same sheets.RateLimiter across stores to keep all of them within the same quota.
+	// The default value (nil) disables rate limiting.
+	RateLimiter *sheets.RateLimiter
+
+	// Endpoints overrides the Google Sheets API and query endpoints, e.g. to target a local emulator.
+	// The default value uses the Google endpoints.
+	Endpoints sheets.Endpoints
+
+	// MemoryBackend stores the data in the given memory.Backend instead of Google Sheets, e.g. for tests.
+	// The auth client is not used in this case and may be nil.
+	// The default value (nil) uses Google Sheets.
+	MemoryBackend *memory.Backend
+
+	// FileBackend stores the data in local CSV or XLSX files through the given file.Backend instead of Google Sheets,
+	// e.g. for offline development. The auth client is not used in this case and may be nil.
+	// It cannot be used together with MemoryBackend.
+	FileBackend *file.Backend
+
+	// Wrapper replaces the default sheets.Wrapper used for calling Google Sheets, e.g. with a decorator adding
+	// caching, logging or fault injection. The auth client and the other wrapper options (RetryPolicy, RateLimiter,
+	// Endpoints, MemoryBackend and FileBackend) are not used in this case.
+	// The wrapper must render the values with sheets.ValueRenderUnformatted and sheets.DateTimeRenderSerialNumber.
+	Wrapper SheetsWrapper
+
+	// Interceptors are called around every Google Sheets operation made by the store,
+	// e.g. for logging, tracing or metrics. See NewTracingInterceptor and NewMetricsInterceptor.
+	Interceptors []Interceptor
+
+	// Cache enables caching the values returned by GoogleSheetKVStore.Get keyed by the key.
+	// A cached key is invalidated whenever the store sets or deletes it. The default value disables the cache.
+	Cache CacheConfig
+
+	// CoalesceReads makes the concurrent GoogleSheetKVStore.Get calls with the same key share a single
+	// in-flight Google Sheets API call and its result. The shared call uses the context of the first caller,
+	// so its cancellation fails the call for the other callers as well.
+	CoalesceReads bool
+
+	// SkipProvisioning skips creating the sheet and its scratchpad sheet when the store is created.
+	// Both sheets must already exist.
+	SkipProvisioning bool
+
 	codec Codec
 }
 
@@ -24,12 +76,14 @@ type GoogleSheetKVStoreConfig struct {
 // For more details on how they differ, please read the explanations for each method or the protocol page:
 // https://github.com/FreeLeh/docs/blob/main/freedb/protocols.md.
 type GoogleSheetKVStore struct {
-	wrapper             sheetsWrapper
+	wrapper             SheetsWrapper
 	spreadsheetID       string
 	sheetName           string
 	scratchpadSheetName string
 	scratchpadLocation  sheets.A1Range
 	config              GoogleSheetKVStoreConfig
+	cache               *cache
+	group               *flightGroup
 }
 
 // Get retrieves the value associated with the given key.
@@ -46,7 +100,32 @@ type GoogleSheetKVStore struct {
 //   - Note that deletion using append only mode results in a new row with a tombstone value.
 //     This method will also recognise and handle such cases.
 //   - There is only 1 API call behind the scene.
+//
+// If GoogleSheetKVStoreConfig.Cache is enabled, a cached value is returned without any API call.
 func (s *GoogleSheetKVStore) Get(ctx context.Context, key string) ([]byte, error) {
+	cached, generation, ok := s.cache.get(key)
+	if ok {
+		return append([]byte(nil), cached.([]byte)...), nil
+	}
+
+	result, shared, err := s.group.do(ctx, key, func() (interface{}, error) {
+		return s.get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	value := result.([]byte)
+	if s.cache != nil {
+		s.cache.put(key, append([]byte(nil), value...), generation)
+	}
+	if shared {
+		return append([]byte(nil), value...), nil
+	}
+	return value, nil
+}
+
+func (s *GoogleSheetKVStore) get(ctx context.Context, key string) ([]byte, error) {
 	query := fmt.Sprintf(kvGetDefaultQueryTemplate, key, common.GetA1Range(s.sheetName, defaultKVTableRange))
 	if s.config.Mode == models.KVModeAppendOnly {
 		query = fmt.Sprintf(kvGetAppendQueryTemplate, key, common.GetA1Range(s.sheetName, defaultKVTableRange))
@@ -65,11 +144,11 @@ func (s *GoogleSheetKVStore) Get(ctx context.Context, key string) ([]byte, error
 		return nil, fmt.Errorf("%w: %s", models.ErrKeyNotFound, key)
 	}
 
-	value := result.UpdatedValues[0][0]
-	if value == models.NAValue || value == "" {
+	value, ok := convertKVValue(result.UpdatedValues[0][0])
+	if !ok {
 		return nil, fmt.Errorf("%w: %s", models.ErrKeyNotFound, key)
 	}
-	return s.config.codec.Decode(value.(string))
+	return s.config.codec.Decode(value)
 }
 
 // Set inserts the key-value pair into the key-value store.
@@ -83,6 +162,8 @@ func (s *GoogleSheetKVStore) Get(ctx context.Context, key string) ([]byte, error
 //   - It always creates a new row at the bottom of the sheet with the latest value and timestamp.
 //   - There is only 1 API call behind the scene.
 func (s *GoogleSheetKVStore) Set(ctx context.Context, key string, value []byte) error {
+	defer s.invalidate(key)
+
 	encoded, err := s.config.codec.Encode(value)
 	if err != nil {
 		return err
@@ -142,8 +223,8 @@ func (s *GoogleSheetKVStore) findKeyA1Range(ctx context.Context, key string) (sh
 		return sheets.A1Range{}, fmt.Errorf("%w: %s", models.ErrKeyNotFound, key)
 	}
 
-	offset := result.UpdatedValues[0][0].(string)
-	if offset == models.NAValue || offset == "" {
+	offset, err := convertRowOffset(result.UpdatedValues[0][0])
+	if err != nil {
 		return sheets.A1Range{}, fmt.Errorf("%w: %s", models.ErrKeyNotFound, key)
 	}
 
@@ -151,7 +232,7 @@ func (s *GoogleSheetKVStore) findKeyA1Range(ctx context.Context, key string) (sh
 	// Here we need to return the full range where the key is found.
 	// Hence, we need to get the row offset first, and assume that each row has only 3 rows: A B C.
 	// Otherwise, the DELETE() function will not work properly (we need to clear the full row, not just the key cell).
-	a1Range := common.GetA1Range(s.sheetName, fmt.Sprintf("A%s:C%s", offset, offset))
+	a1Range := common.GetA1Range(s.sheetName, fmt.Sprintf("A%d:C%d", offset, offset))
 	return sheets.NewA1Range(a1Range), nil
 }
 
@@ -166,6 +247,8 @@ func (s *GoogleSheetKVStore) findKeyA1Range(ctx context.Context, key string) (sh
 //   - It creates a new row at the bottom of the sheet with a tombstone value and timestamp.
 //   - There is only 1 API call behind the scene.
 func (s *GoogleSheetKVStore) Delete(ctx context.Context, key string) error {
+	defer s.invalidate(key)
+
 	if s.config.Mode == models.KVModeAppendOnly {
 		return s.deleteAppendOnly(ctx, key)
 	}
@@ -189,6 +272,11 @@ func (s *GoogleSheetKVStore) deleteDefault(ctx context.Context, key string) erro
 	return err
 }
 
+func (s *GoogleSheetKVStore) invalidate(key string) {
+	s.cache.invalidate(key)
+	s.group.forget(key)
+}
+
 // Close cleans up all held resources like the scratchpad cell booked for this specific GoogleSheetKVStore instance.
 func (s *GoogleSheetKVStore) Close(ctx context.Context) error {
 	_, err := s.wrapper.Clear(ctx, s.spreadsheetID, []string{s.scratchpadLocation.Original})
@@ -197,16 +285,49 @@ func (s *GoogleSheetKVStore) Close(ctx context.Context) error {
 
 // NewGoogleSheetKVStore creates an instance of the key-value store with the given configuration.
 // It will also try to create the sheet, in case it does not exist yet.
+//
+// It panics if the sheet cannot be provisioned. Use NewGoogleSheetKVStoreWithContext to handle such errors instead.
 func NewGoogleSheetKVStore(
 	auth sheets.AuthClient,
 	spreadsheetID string,
 	sheetName string,
 	config GoogleSheetKVStoreConfig,
 ) *GoogleSheetKVStore {
-	wrapper, err := sheets.NewWrapper(auth)
+	ctx, cancel := context.WithTimeout(context.Background(), defaultProvisionTimeout)
+	defer cancel()
+
+	store, err := NewGoogleSheetKVStoreWithContext(ctx, auth, spreadsheetID, sheetName, config)
+	if err != nil {
+		panic(err)
+	}
+	return store
+}
+
+// NewGoogleSheetKVStoreWithContext works just like NewGoogleSheetKVStore, but it uses the given context
+// for provisioning the sheets and returns an error instead of panicking.
+//
+// Set GoogleSheetKVStoreConfig.SkipProvisioning to skip creating the sheets, e.g. for sheets provisioned beforehand.
+// Note that a scratchpad cell is still booked, as it is required by every operation.
+func NewGoogleSheetKVStoreWithContext(
+	ctx context.Context,
+	auth sheets.AuthClient,
+	spreadsheetID string,
+	sheetName string,
+	config GoogleSheetKVStoreConfig,
+) (*GoogleSheetKVStore, error) {
+	// The values are read from the scratchpad cell formula results, so they must not depend on the spreadsheet locale.
+	// Otherwise, a row offset of 1234 may be returned as "1.234" or "1,234".
+	wrapper, err := newSheetsWrapper(auth, config.Wrapper, config.MemoryBackend, config.FileBackend, sheets.WrapperConfig{
+		ValueRenderOption:    sheets.ValueRenderUnformatted,
+		DateTimeRenderOption: sheets.DateTimeRenderSerialNumber,
+		RetryPolicy:          config.RetryPolicy,
+		RateLimiter:          config.RateLimiter,
+		Endpoints:            config.Endpoints,
+	})
 	if err != nil {
-		panic(fmt.Errorf("error creating sheets wrapper: %w", err))
+		return nil, err
 	}
+	wrapper = interceptWrapper(wrapper, config.Interceptors)
 
 	scratchpadSheetName := sheetName + scratchpadSheetNameSuffix
 	config = applyGoogleSheetKVStoreConfig(config)
@@ -217,18 +338,59 @@ func NewGoogleSheetKVStore(
 		sheetName:           sheetName,
 		scratchpadSheetName: scratchpadSheetName,
 		config:              config,
+		cache:               newCache(config.Cache),
+		group:               newFlightGroup(config.CoalesceReads),
 	}
 
-	_ = ensureSheets(store.wrapper, store.spreadsheetID, store.sheetName)
-	_ = ensureSheets(store.wrapper, store.spreadsheetID, store.scratchpadSheetName)
+	if !config.SkipProvisioning {
+		for _, name := range []string{store.sheetName, store.scratchpadSheetName} {
+			if err := ensureSheets(ctx, store.wrapper, store.spreadsheetID, name, sheets.GridSize{ColumnCount: kvColumnCount}); err != nil {
+				return nil, fmt.Errorf("error ensuring sheet %s: %w", name, err)
+			}
+		}
+	}
 
-	scratchpadLocation, err := findScratchpadLocation(store.wrapper, store.spreadsheetID, store.scratchpadSheetName)
+	scratchpadLocation, err := findScratchpadLocation(ctx, store.wrapper, store.spreadsheetID, store.scratchpadSheetName)
 	if err != nil {
-		panic(fmt.Errorf("error finding a scratchpad location in sheet %s: %w", store.scratchpadSheetName, err))
+		return nil, fmt.Errorf("error finding a scratchpad location in sheet %s: %w", store.scratchpadSheetName, err)
 	}
 	store.scratchpadLocation = scratchpadLocation
 
-	return store
+	return store, nil
+}
+
+// convertRowOffset converts the MATCH() formula result into a row offset.
+// The result is a number when rendered without formatting, but it can also be a string for formatted values.
+func convertRowOffset(value interface{}) (int64, error) {
+	switch converted := value.(type) {
+	case float64:
+		return int64(converted), nil
+	case string:
+		if converted == models.NAValue || converted == "" {
+			return 0, errors.New("row offset not found")
+		}
+		return strconv.ParseInt(converted, 10, 64)
+	default:
+		return 0, fmt.Errorf("unsupported row offset value: %v", value)
+	}
+}
+
+// convertKVValue converts the VLOOKUP() formula result into the encoded value.
+// The result is a string for formatted values, but it can also be a number or a boolean when rendered without
+// formatting. The second return value is false if the key is not found, i.e. the result is empty or #N/A.
+func convertKVValue(value interface{}) (string, bool) {
+	switch converted := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		return converted, converted != models.NAValue && converted != ""
+	case float64:
+		return strconv.FormatFloat(converted, 'f', -1, 64), true
+	case bool:
+		return strings.ToUpper(strconv.FormatBool(converted)), true
+	default:
+		return fmt.Sprint(value), true
+	}
 }
 
 func applyGoogleSheetKVStoreConfig(config GoogleSheetKVStoreConfig) GoogleSheetKVStoreConfig {
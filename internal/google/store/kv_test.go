@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"github.com/FreeLeh/GoFreeDB/internal/common"
+	"github.com/FreeLeh/GoFreeDB/internal/google/memory"
+	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
 	"github.com/FreeLeh/GoFreeDB/internal/models"
 	"testing"
 	"time"
@@ -60,6 +62,44 @@ func TestGoogleSheetKVStore_AppendOnly_Integration(t *testing.T) {
 	assert.ErrorIs(t, err, models.ErrKeyNotFound)
 }
 
+func TestGoogleSheetKVStore_Memory(t *testing.T) {
+	for _, mode := range []models.KVMode{models.KVModeDefault, models.KVModeAppendOnly} {
+		ctx := context.Background()
+		kv, err := NewGoogleSheetKVStoreWithContext(
+			ctx,
+			nil,
+			"spreadsheet",
+			"kv",
+			GoogleSheetKVStoreConfig{Mode: mode, MemoryBackend: memory.NewBackend()},
+		)
+		assert.Nil(t, err)
+
+		_, err = kv.Get(ctx, "k1")
+		assert.ErrorIs(t, err, models.ErrKeyNotFound)
+
+		assert.Nil(t, kv.Set(ctx, "k1", []byte("test")))
+		assert.Nil(t, kv.Set(ctx, "k2", []byte("other")))
+		// The append only mode picks the latest value based on the millisecond timestamp.
+		time.Sleep(2 * time.Millisecond)
+		assert.Nil(t, kv.Set(ctx, "k1", []byte("updated")))
+
+		value, err := kv.Get(ctx, "k1")
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("updated"), value)
+
+		value, err = kv.Get(ctx, "k2")
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("other"), value)
+
+		time.Sleep(2 * time.Millisecond)
+		assert.Nil(t, kv.Delete(ctx, "k1"))
+		_, err = kv.Get(ctx, "k1")
+		assert.ErrorIs(t, err, models.ErrKeyNotFound)
+
+		assert.Nil(t, kv.Close(ctx))
+	}
+}
+
 func TestNewGoogleSheetKVStore_Default_Integration(t *testing.T) {
 	spreadsheetID, authJSON, shouldRun := getIntegrationTestInfo()
 	if !shouldRun {
@@ -111,3 +151,80 @@ func TestNewGoogleSheetKVStore_Default_Integration(t *testing.T) {
 	assert.Nil(t, value)
 	assert.ErrorIs(t, err, models.ErrKeyNotFound)
 }
+
+func TestConvertRowOffset(t *testing.T) {
+	offset, err := convertRowOffset(float64(1234))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1234), offset)
+
+	offset, err = convertRowOffset("12")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(12), offset)
+
+	_, err = convertRowOffset(models.NAValue)
+	assert.NotNil(t, err)
+
+	_, err = convertRowOffset("")
+	assert.NotNil(t, err)
+
+	_, err = convertRowOffset("1.234")
+	assert.NotNil(t, err)
+
+	_, err = convertRowOffset(true)
+	assert.NotNil(t, err)
+}
+
+func TestConvertKVValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected string
+		ok       bool
+	}{
+		{name: "string", input: "value", expected: "value", ok: true},
+		{name: "integer", input: float64(123), expected: "123", ok: true},
+		{name: "float", input: 1.5, expected: "1.5", ok: true},
+		{name: "bool", input: true, expected: "TRUE", ok: true},
+		{name: "not_available", input: models.NAValue, expected: models.NAValue, ok: false},
+		{name: "empty", input: "", expected: "", ok: false},
+		{name: "nil", input: nil, expected: "", ok: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			value, ok := convertKVValue(tc.input)
+			assert.Equal(t, tc.ok, ok)
+			if ok {
+				assert.Equal(t, tc.expected, value)
+			}
+		})
+	}
+}
+
+func TestGoogleSheetKVStore_GetUnformattedValue(t *testing.T) {
+	newStore := func(value interface{}) *GoogleSheetKVStore {
+		return &GoogleSheetKVStore{
+			wrapper: &sheets.MockWrapper{UpdateRowsResult: sheets.UpdateRowsResult{
+				UpdatedValues: [][]interface{}{{value}},
+			}},
+			sheetName: "kv",
+			config:    applyGoogleSheetKVStoreConfig(GoogleSheetKVStoreConfig{}),
+		}
+	}
+
+	// The stored values always have the codec prefix, so a number or a boolean is reported as a decode failure.
+	_, err := newStore(float64(123)).get(context.Background(), "k1")
+	assert.NotNil(t, err)
+	assert.NotErrorIs(t, err, models.ErrKeyNotFound)
+
+	_, err = newStore(false).get(context.Background(), "k1")
+	assert.NotNil(t, err)
+	assert.NotErrorIs(t, err, models.ErrKeyNotFound)
+
+	value, err := newStore("!123").get(context.Background(), "k1")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("123"), value)
+
+	_, err = newStore(models.NAValue).get(context.Background(), "k1")
+	assert.ErrorIs(t, err, models.ErrKeyNotFound)
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/FreeLeh/GoFreeDB/internal/codec"
+	"github.com/FreeLeh/GoFreeDB/internal/google/file"
+	"github.com/FreeLeh/GoFreeDB/internal/google/memory"
 	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
 	"github.com/FreeLeh/GoFreeDB/internal/models"
 )
@@ -17,7 +19,53 @@ type googleSheetKVStoreV2Row struct {
 // GoogleSheetKVStoreV2Config defines a list of configurations that can be used to customise
 // how the GoogleSheetKVStoreV2 works.
 type GoogleSheetKVStoreV2Config struct {
-	Mode  models.KVMode
+	Mode models.KVMode
+
+	// RetryPolicy defines how failed Google Sheets API calls are retried.
+	// The default value disables retries. Use sheets.DefaultRetryPolicy for the recommended policy.
+	RetryPolicy sheets.RetryPolicy
+
+	// RateLimiter limits the number of Google Sheets API calls made by the store.
+	// Share the same sheets.RateLimiter across stores to keep all of them within the same quota.
+	// The default value (nil) disables rate limiting.
+	RateLimiter *sheets.RateLimiter
+
+	// Endpoints overrides the Google Sheets API and query endpoints, e.g. to target a local emulator.
+	// The default value uses the Google endpoints.
+	Endpoints sheets.Endpoints
+
+	// MemoryBackend stores the data in the given memory.Backend instead of Google Sheets, e.g. for tests.
+	// The auth client is not used in this case and may be nil.
+	// The default value (nil) uses Google Sheets.
+	MemoryBackend *memory.Backend
+
+	// FileBackend stores the data in local CSV or XLSX files through the given file.Backend instead of Google Sheets,
+	// e.g. for offline development. The auth client is not used in this case and may be nil.
+	// It cannot be used together with MemoryBackend.
+	FileBackend *file.Backend
+
+	// Wrapper replaces the default sheets.Wrapper used for calling Google Sheets, e.g. with a decorator adding
+	// caching, logging or fault injection. The auth client and the other wrapper options (RetryPolicy, RateLimiter,
+	// Endpoints, MemoryBackend and FileBackend) are not used in this case.
+	// The wrapper must render the values with sheets.ValueRenderFormatted.
+	Wrapper SheetsWrapper
+
+	// Interceptors are called around every statement execution of the underlying row store and every
+	// Google Sheets operation, e.g. for logging, tracing or metrics. See NewTracingInterceptor and NewMetricsInterceptor.
+	Interceptors []Interceptor
+
+	// Cache enables caching the values returned by GoogleSheetKVStoreV2.Get, see GoogleSheetRowStoreConfig.Cache.
+	// The default value disables the cache.
+	Cache CacheConfig
+
+	// CoalesceReads makes the concurrent GoogleSheetKVStoreV2.Get calls with the same key share a single
+	// in-flight Google Sheets API call, see GoogleSheetRowStoreConfig.CoalesceReads.
+	CoalesceReads bool
+
+	// SkipProvisioning skips creating the sheet and writing the header row when the store is created.
+	// The sheet must already exist with the "key" and "value" header columns.
+	SkipProvisioning bool
+
 	codec Codec
 }
 
@@ -104,27 +152,62 @@ func (s *GoogleSheetKVStoreV2) Close(ctx context.Context) error {
 
 // NewGoogleSheetKVStoreV2 creates a new instance of the key-value store using row store.
 // You cannot use this V2 store with the V1 store as the sheet format is different.
+//
+// It panics if the sheet cannot be provisioned. Use NewGoogleSheetKVStoreV2WithContext to handle such errors instead.
 func NewGoogleSheetKVStoreV2(
 	auth sheets.AuthClient,
 	spreadsheetID string,
 	sheetName string,
 	config GoogleSheetKVStoreV2Config,
 ) *GoogleSheetKVStoreV2 {
-	rowStore := NewGoogleSheetRowStore(
+	ctx, cancel := context.WithTimeout(context.Background(), defaultProvisionTimeout)
+	defer cancel()
+
+	store, err := NewGoogleSheetKVStoreV2WithContext(ctx, auth, spreadsheetID, sheetName, config)
+	if err != nil {
+		panic(err)
+	}
+	return store
+}
+
+// NewGoogleSheetKVStoreV2WithContext works just like NewGoogleSheetKVStoreV2, but it uses the given context
+// for provisioning the sheet and returns an error instead of panicking.
+func NewGoogleSheetKVStoreV2WithContext(
+	ctx context.Context,
+	auth sheets.AuthClient,
+	spreadsheetID string,
+	sheetName string,
+	config GoogleSheetKVStoreV2Config,
+) (*GoogleSheetKVStoreV2, error) {
+	rowStore, err := NewGoogleSheetRowStoreWithContext(
+		ctx,
 		auth,
 		spreadsheetID,
 		sheetName,
 		GoogleSheetRowStoreConfig{
-			Columns: []string{"key", "value"},
+			Columns:          []string{"key", "value"},
+			RetryPolicy:      config.RetryPolicy,
+			RateLimiter:      config.RateLimiter,
+			Endpoints:        config.Endpoints,
+			MemoryBackend:    config.MemoryBackend,
+			FileBackend:      config.FileBackend,
+			Wrapper:          config.Wrapper,
+			Interceptors:     config.Interceptors,
+			Cache:            config.Cache,
+			CoalesceReads:    config.CoalesceReads,
+			SkipProvisioning: config.SkipProvisioning,
 		},
 	)
+	if err != nil {
+		return nil, err
+	}
 
 	config = applyGoogleSheetKVStoreV2Config(config)
 	return &GoogleSheetKVStoreV2{
 		rowStore: rowStore,
 		mode:     config.Mode,
 		codec:    config.codec,
-	}
+	}, nil
 }
 
 func applyGoogleSheetKVStoreV2Config(config GoogleSheetKVStoreV2Config) GoogleSheetKVStoreV2Config {
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/FreeLeh/GoFreeDB/internal/common"
+	"github.com/FreeLeh/GoFreeDB/internal/google/memory"
 	"github.com/FreeLeh/GoFreeDB/internal/models"
 
 	"github.com/FreeLeh/GoFreeDB/google/auth"
@@ -61,6 +62,44 @@ func TestGoogleSheetKVStoreV2_AppendOnly_Integration(t *testing.T) {
 	assert.ErrorIs(t, err, models.ErrKeyNotFound)
 }
 
+func TestGoogleSheetKVStoreV2_Memory(t *testing.T) {
+	for _, mode := range []models.KVMode{models.KVModeDefault, models.KVModeAppendOnly} {
+		ctx := context.Background()
+		kv, err := NewGoogleSheetKVStoreV2WithContext(
+			ctx,
+			nil,
+			"spreadsheet",
+			"kv",
+			GoogleSheetKVStoreV2Config{Mode: mode, MemoryBackend: memory.NewBackend()},
+		)
+		assert.Nil(t, err)
+
+		_, err = kv.Get(ctx, "k1")
+		assert.ErrorIs(t, err, models.ErrKeyNotFound)
+
+		assert.Nil(t, kv.Set(ctx, "k1", []byte("test")))
+		assert.Nil(t, kv.Set(ctx, "k2", []byte("other")))
+		// The append only mode picks the latest value based on the millisecond timestamp.
+		time.Sleep(2 * time.Millisecond)
+		assert.Nil(t, kv.Set(ctx, "k1", []byte("updated")))
+
+		value, err := kv.Get(ctx, "k1")
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("updated"), value)
+
+		value, err = kv.Get(ctx, "k2")
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("other"), value)
+
+		time.Sleep(2 * time.Millisecond)
+		assert.Nil(t, kv.Delete(ctx, "k1"))
+		_, err = kv.Get(ctx, "k1")
+		assert.ErrorIs(t, err, models.ErrKeyNotFound)
+
+		assert.Nil(t, kv.Close(ctx))
+	}
+}
+
 func TestNewGoogleSheetKVStoreV2_Default_Integration(t *testing.T) {
 	spreadsheetID, authJSON, shouldRun := getIntegrationTestInfo()
 	if !shouldRun {
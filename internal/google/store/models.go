@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/FreeLeh/GoFreeDB/internal/common"
 	"regexp"
+	"time"
 
 	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
 )
@@ -17,6 +18,9 @@ const (
 	scratchpadBooked          = "BOOKED"
 	scratchpadSheetNameSuffix = "_scratch"
 
+	// Each key-value row has 3 columns: key, value and the last updated timestamp.
+	kvColumnCount = 3
+
 	defaultKVTableRange    = "A1:C5000000"
 	defaultKVKeyColRange   = "A1:A5000000"
 	defaultKVFirstRowRange = "A1:C1"
@@ -27,6 +31,11 @@ const (
 
 	rowIdxCol     = "_rid"
 	rowIdxFormula = "=ROW()"
+
+	defaultRowsPageSize = 1000
+
+	// defaultProvisionTimeout is used by the constructors that do not accept a context.
+	defaultProvisionTimeout = time.Minute
 )
 
 var (
@@ -40,6 +49,12 @@ var (
 	rowWhereEmptyConditionTemplate    = rowIdxCol + " is not null"
 
 	googleSheetSelectStmtStringKeyword = regexp.MustCompile("^(date|datetime|timeofday)")
+
+	// Matches "<expression> AS <alias>", e.g. "upper(name) AS name_upper" (case-insensitive).
+	selectColumnAliasRegex = regexp.MustCompile(`(?i)^(.+)\s+as\s+([A-Za-z_][A-Za-z0-9_]*)\s*$`)
+
+	// Matches the end of an equality comparison against a column before its placeholder, e.g. "B = " in "B = ?".
+	stringNumberComparisonRegex = regexp.MustCompile(`\b([A-Z]+)\s*(?:=|!=|<>)\s*$`)
 )
 
 // Codec is an interface for encoding and decoding the data provided by the client.
@@ -49,15 +64,22 @@ type Codec interface {
 	Decode(value string) ([]byte, error)
 }
 
-type sheetsWrapper interface {
+// SheetsWrapper defines the Google Sheets operations used by the stores.
+//
+// sheets.Wrapper is the default implementation. Set it in the store config (e.g. GoogleSheetRowStoreConfig.Wrapper)
+// to use a decorator (e.g. for caching, logging or fault injection) or an alternative implementation.
+// The values returned by the wrapper must be rendered based on the render options documented in the store config.
+type SheetsWrapper interface {
 	CreateSpreadsheet(ctx context.Context, title string) (string, error)
 	GetSheetNameToID(ctx context.Context, spreadsheetID string) (map[string]int64, error)
 	CreateSheet(ctx context.Context, spreadsheetID string, sheetName string) error
+	CreateSheetWithGridSize(ctx context.Context, spreadsheetID string, sheetName string, size sheets.GridSize) error
 	DeleteSheets(ctx context.Context, spreadsheetID string, sheetIDs []int64) error
 	InsertRows(ctx context.Context, spreadsheetID string, a1Range string, values [][]interface{}) (sheets.InsertRowsResult, error)
 	OverwriteRows(ctx context.Context, spreadsheetID string, a1Range string, values [][]interface{}) (sheets.InsertRowsResult, error)
 	UpdateRows(ctx context.Context, spreadsheetID string, a1Range string, values [][]interface{}) (sheets.UpdateRowsResult, error)
 	BatchUpdateRows(ctx context.Context, spreadsheetID string, requests []sheets.BatchUpdateRowsRequest) (sheets.BatchUpdateRowsResult, error)
+	BatchUpdateRowsWithOption(ctx context.Context, spreadsheetID string, requests []sheets.BatchUpdateRowsRequest, option sheets.ValueInputOption) (sheets.BatchUpdateRowsResult, error)
 	QueryRows(ctx context.Context, spreadsheetID string, sheetName string, query string, skipHeader bool) (sheets.QueryRowsResult, error)
 	Clear(ctx context.Context, spreadsheetID string, ranges []string) ([]string, error)
 }
@@ -13,7 +13,7 @@ func getIntegrationTestInfo() (string, string, bool) {
 	return spreadsheetID, authJSON, isGithubActions && spreadsheetID != "" && authJSON != ""
 }
 
-func deleteSheet(t *testing.T, wrapper sheetsWrapper, spreadsheetID string, sheetNames []string) {
+func deleteSheet(t *testing.T, wrapper SheetsWrapper, spreadsheetID string, sheetNames []string) {
 	sheetNameToID, err := wrapper.GetSheetNameToID(context.Background(), spreadsheetID)
 	if err != nil {
 		t.Fatalf("failed getting mapping of sheet names to IDs: %s", err)
@@ -0,0 +1,340 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/FreeLeh/GoFreeDB/internal/common"
+	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
+)
+
+type rawQueryTokenKind int
+
+const (
+	rawQueryTokenWord rawQueryTokenKind = iota
+	rawQueryTokenString
+	rawQueryTokenBacktick
+	rawQueryTokenOther
+)
+
+type rawQueryToken struct {
+	kind rawQueryTokenKind
+	text string
+}
+
+// rawQueryClauses are the clauses that may follow the "where" clause.
+// The "group" and "order" keywords must be followed by "by".
+var rawQueryClauses = map[string]bool{
+	"group":   true,
+	"pivot":   true,
+	"order":   true,
+	"limit":   true,
+	"offset":  true,
+	"label":   true,
+	"format":  true,
+	"options": true,
+}
+
+// rawQueryKeywords are the reserved words of the query language, which are never translated into column letters.
+// The clause keywords are handled by isRawQueryClause.
+var rawQueryKeywords = map[string]bool{
+	"select":   true,
+	"where":    true,
+	"by":       true,
+	"and":      true,
+	"or":       true,
+	"not":      true,
+	"is":       true,
+	"null":     true,
+	"true":     true,
+	"false":    true,
+	"asc":      true,
+	"desc":     true,
+	"contains": true,
+	"starts":   true,
+	"ends":     true,
+	"with":     true,
+	"matches":  true,
+	"like":     true,
+}
+
+// rawQueryLiteralKeywords are the keywords prefixing a date and time literal, e.g. "date '2020-01-01'".
+var rawQueryLiteralKeywords = map[string]bool{
+	"date":      true,
+	"datetime":  true,
+	"timeofday": true,
+	"timestamp": true,
+}
+
+// rawQuery translates a Google Visualization query written using column names, see GoogleSheetRowStore.Query.
+//
+// The column names are translated into column letters and the "?" placeholders are replaced by the arguments,
+// just like queryBuilder does for the "where" clause. Quoted strings are kept as is.
+// The function names, keywords and date and time literal prefixes are not translated, see isRawQueryReservedWord.
+// The "where" clause is guarded with the row index column condition, so that the empty rows are excluded.
+type rawQuery struct {
+	query    string
+	args     []interface{}
+	mapping  common.ColsMapping
+	ridCol   string
+	argsConv *queryBuilder
+}
+
+func (s *GoogleSheetRowStore) newRawQuery(query string, args []interface{}) *rawQuery {
+	return &rawQuery{
+		query:    query,
+		args:     args,
+		mapping:  s.colsMapping,
+		ridCol:   s.colsMapping[rowIdxCol].Name,
+		argsConv: s.newQueryBuilder(nil),
+	}
+}
+
+func (q *rawQuery) Generate() (string, error) {
+	tokens, err := tokenizeRawQuery(q.query)
+	if err != nil {
+		return "", err
+	}
+
+	nArgs := 0
+	for _, token := range tokens {
+		if token.kind == rawQueryTokenOther && token.text == "?" {
+			nArgs++
+		}
+	}
+	if nArgs != len(q.args) {
+		return "", fmt.Errorf("number of arguments required in the query (%d) is not the same as the number of provided arguments (%d)", nArgs, len(q.args))
+	}
+
+	translated, err := q.translate(tokens)
+	if err != nil {
+		return "", err
+	}
+
+	whereIdx, clauseIdx := findWhereClause(tokens)
+	ridCondition := q.ridCol + " is not null"
+	if whereIdx == -1 {
+		return joinQueryParts(renderTokens(translated[:clauseIdx]), "where "+ridCondition, renderTokens(translated[clauseIdx:])), nil
+	}
+
+	condition := strings.TrimSpace(renderTokens(translated[whereIdx+1 : clauseIdx]))
+	if condition == "" {
+		return "", errors.New("the 'where' clause of the query is empty")
+	}
+	return joinQueryParts(
+		renderTokens(translated[:whereIdx]),
+		fmt.Sprintf("where %s AND (%s)", ridCondition, condition),
+		renderTokens(translated[clauseIdx:]),
+	), nil
+}
+
+// translate replaces the column names and the placeholders, keeping the tokens positions.
+func (q *rawQuery) translate(tokens []rawQueryToken) ([]rawQueryToken, error) {
+	result := make([]rawQueryToken, len(tokens))
+	argIdx := 0
+
+	for i, token := range tokens {
+		result[i] = token
+		switch {
+		case token.kind == rawQueryTokenWord && !isRawQueryReservedWord(tokens, i):
+			if col, ok := q.mapping[token.text]; ok {
+				result[i].text = col.Name
+			}
+		case token.kind == rawQueryTokenBacktick:
+			if col, ok := q.mapping[strings.Trim(token.text, "`")]; ok {
+				result[i].text = col.Name
+			}
+		case token.kind == rawQueryTokenOther && token.text == "?":
+			arg, err := q.argsConv.convertArg(q.args[argIdx])
+			if err != nil {
+				return nil, fmt.Errorf("failed converting query arguments: %v, %w", q.args[argIdx], err)
+			}
+			result[i].text = arg
+			argIdx++
+		}
+	}
+	return result, nil
+}
+
+// findWhereClause returns the index of the top level "where" keyword (or -1 if there is none) and the index of
+// the clause following it. If there is no "where" clause, the index of the first clause after "select" is returned.
+// The indices refer to the given tokens, where the end of the query is len(tokens).
+func findWhereClause(tokens []rawQueryToken) (int, int) {
+	whereIdx := -1
+	depth := 0
+
+	for i, token := range tokens {
+		switch {
+		case token.kind == rawQueryTokenOther && token.text == "(":
+			depth++
+		case token.kind == rawQueryTokenOther && token.text == ")":
+			depth--
+		case token.kind != rawQueryTokenWord || depth != 0:
+			continue
+		case strings.EqualFold(token.text, "where") && whereIdx == -1:
+			whereIdx = i
+		case isRawQueryClause(tokens, i):
+			return whereIdx, i
+		}
+	}
+	return whereIdx, len(tokens)
+}
+
+func isRawQueryClause(tokens []rawQueryToken, idx int) bool {
+	keyword := strings.ToLower(tokens[idx].text)
+	if !rawQueryClauses[keyword] {
+		return false
+	}
+	if keyword != "group" && keyword != "order" {
+		return true
+	}
+
+	next, ok := nextRawQueryToken(tokens, idx)
+	return ok && next.kind == rawQueryTokenWord && strings.EqualFold(next.text, "by")
+}
+
+// isRawQueryReservedWord returns true if the word at the given index is a part of the query language instead of
+// a column name, i.e. a function call (e.g. "count(age)"), a keyword or a date and time literal prefix.
+// A column named after them must be quoted with backticks, e.g. "select `count`, count(age) group by `count`".
+func isRawQueryReservedWord(tokens []rawQueryToken, idx int) bool {
+	keyword := strings.ToLower(tokens[idx].text)
+	next, ok := nextRawQueryToken(tokens, idx)
+
+	switch {
+	case ok && next.kind == rawQueryTokenOther && next.text == "(":
+		return true
+	case rawQueryLiteralKeywords[keyword]:
+		return ok && next.kind == rawQueryTokenString
+	default:
+		return rawQueryKeywords[keyword] || isRawQueryClause(tokens, idx)
+	}
+}
+
+// nextRawQueryToken returns the first token after the given index that is not a whitespace.
+func nextRawQueryToken(tokens []rawQueryToken, idx int) (rawQueryToken, bool) {
+	for _, token := range tokens[idx+1:] {
+		if token.kind == rawQueryTokenOther && strings.TrimSpace(token.text) == "" {
+			continue
+		}
+		return token, true
+	}
+	return rawQueryToken{}, false
+}
+
+func tokenizeRawQuery(query string) ([]rawQueryToken, error) {
+	runes := []rune(query)
+	tokens := make([]rawQueryToken, 0)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case isRawQueryWordRune(r):
+			start := i
+			for i < len(runes) && isRawQueryWordRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, rawQueryToken{kind: rawQueryTokenWord, text: string(runes[start:i])})
+		case r == '\'' || r == '"' || r == '`':
+			start := i
+			i++
+			for i < len(runes) && runes[i] != r {
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated quoted string in the query: %s", string(runes[start:]))
+			}
+			i++
+
+			kind := rawQueryTokenString
+			if r == '`' {
+				kind = rawQueryTokenBacktick
+			}
+			tokens = append(tokens, rawQueryToken{kind: kind, text: string(runes[start:i])})
+		default:
+			tokens = append(tokens, rawQueryToken{kind: rawQueryTokenOther, text: string(r)})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func isRawQueryWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func renderTokens(tokens []rawQueryToken) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteString(token.text)
+	}
+	return b.String()
+}
+
+func joinQueryParts(parts ...string) string {
+	result := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return strings.Join(result, " ")
+}
+
+// decodeByLabel decodes the query result into the output, which must be a pointer to a slice.
+// The columns are matched with the struct fields or map keys based on their labels.
+func (s *GoogleSheetRowStore) decodeByLabel(result sheets.QueryRowsResult, output interface{}) error {
+	if output == nil {
+		return errors.New("query output cannot be empty or nil")
+	}
+
+	t := reflect.TypeOf(output)
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("query output must be a pointer to a slice of something; current output type: %s", t.String())
+	}
+
+	var input interface{}
+	elem := t.Elem().Elem()
+	switch {
+	case isRawRowType(elem):
+		input = result.Rows
+	case isScalarType(elem):
+		if len(result.Columns) != 1 {
+			return fmt.Errorf("query output of type %s requires exactly 1 column, got %d", elem, len(result.Columns))
+		}
+
+		values := make([]interface{}, len(result.Rows))
+		for i, row := range result.Rows {
+			if len(row) > 0 {
+				values[i] = row[0]
+			}
+		}
+		input = values
+	default:
+		labels := make([]string, len(result.Columns))
+		for i, col := range result.Columns {
+			labels[i] = col.Label
+			if labels[i] == "" {
+				labels[i] = col.ID
+			}
+		}
+
+		rows := make([]map[string]interface{}, len(result.Rows))
+		for i, row := range result.Rows {
+			rows[i] = make(map[string]interface{}, len(row))
+			for j, value := range row {
+				if j < len(labels) {
+					rows[i][labels[j]] = value
+				}
+			}
+		}
+		input = rows
+	}
+
+	if s.config.LosslessNumbers {
+		return common.MapStructureDecode(input, output, common.LosslessNumberDecodeHook)
+	}
+	return common.MapStructureDecode(input, output)
+}
@@ -0,0 +1,177 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreeLeh/GoFreeDB/internal/common"
+	"github.com/FreeLeh/GoFreeDB/internal/google/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRawQuery_Generate(t *testing.T) {
+	mapping := common.GenerateColumnMapping([]string{rowIdxCol, "name", "age", "order_id", "count", "date", "limit"})
+
+	tests := []struct {
+		name     string
+		query    string
+		args     []interface{}
+		expected string
+		hasErr   bool
+	}{
+		{
+			name:     "no_where",
+			query:    "select name, age order by age desc limit 10",
+			expected: "select B, C where A is not null order by C desc limit 10",
+		},
+		{
+			name:     "no_clause",
+			query:    "select name",
+			expected: "select B where A is not null",
+		},
+		{
+			name:     "where_with_args",
+			query:    "select name where age > ? or name = ? group by name",
+			args:     []interface{}{10, "or name"},
+			expected: `select B where A is not null AND (C > 10 or B = "or name") group by B`,
+		},
+		{
+			name:     "quoted_strings_are_kept",
+			query:    "select `order_id`, upper(name) where name != 'name ?' label upper(name) 'name'",
+			expected: "select D, upper(B) where A is not null AND (B != 'name ?') label upper(B) 'name'",
+		},
+		{
+			name:     "nested_clause_keywords",
+			query:    "select sum(age) where (name = 'limit') pivot name",
+			expected: "select sum(C) where A is not null AND ((B = 'limit')) pivot B",
+		},
+		{
+			name:     "column_named_after_function",
+			query:    "select `count`, count(age) where count > 1 group by count",
+			expected: "select E, count(C) where A is not null AND (E > 1) group by E",
+		},
+		{
+			name:     "function_with_space_before_parenthesis",
+			query:    "select count (age), max(count)",
+			expected: "select count (C), max(E) where A is not null",
+		},
+		{
+			name:     "date_literal",
+			query:    "select date where date > date '2020-01-01' and `date` < DATE '2021-01-01'",
+			expected: "select F where A is not null AND (F > date '2020-01-01' and F < DATE '2021-01-01')",
+		},
+		{
+			name:     "column_named_after_keyword",
+			query:    "select `limit` where `limit` is not null limit 1",
+			expected: "select G where A is not null AND (G is not null) limit 1",
+		},
+		{
+			name:   "args_mismatch",
+			query:  "select name where age > ?",
+			hasErr: true,
+		},
+		{
+			name:   "unsupported_arg",
+			query:  "select name where age > ?",
+			args:   []interface{}{struct{}{}},
+			hasErr: true,
+		},
+		{
+			name:   "unterminated_string",
+			query:  "select name where name = 'abc",
+			hasErr: true,
+		},
+		{
+			name:   "empty_where",
+			query:  "select name where order by name",
+			hasErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			q := &rawQuery{
+				query:    tc.query,
+				args:     tc.args,
+				mapping:  mapping,
+				ridCol:   "A",
+				argsConv: newQueryBuilder(nil, nil, nil),
+			}
+
+			result, err := q.Generate()
+			assert.Equal(t, tc.hasErr, err != nil, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestGoogleSheetRowStore_Query(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewGoogleSheetRowStoreWithContext(
+		ctx,
+		nil,
+		"spreadsheet",
+		"people",
+		GoogleSheetRowStoreConfig{Columns: []string{"name", "age", "dob"}, MemoryBackend: memory.NewBackend()},
+	)
+	assert.Nil(t, err)
+	assert.Nil(t, db.Insert(
+		testPerson{"name1", 10, "1999-01-01"},
+		testPerson{"name2", 11, "2000-01-01"},
+		testPerson{"name1", 12, "2001-01-01"},
+	).Exec(ctx))
+
+	type total struct {
+		Name  string `db:"name"`
+		Total int64  `db:"total"`
+	}
+
+	var totals []total
+	err = db.Query(
+		ctx,
+		&totals,
+		"select name, sum(age) where age >= ? group by name order by name label sum(age) 'total'",
+		10,
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, []total{{"name1", 22}, {"name2", 11}}, totals)
+
+	var names []string
+	assert.Nil(t, db.Query(ctx, &names, "select upper(name) where dob > ?", "2000-01-01"))
+	assert.Equal(t, []string{"NAME1"}, names)
+
+	var rows [][]interface{}
+	assert.Nil(t, db.Query(ctx, &rows, "select name, age order by age desc limit 1"))
+	assert.Equal(t, [][]interface{}{{"name1", float64(12)}}, rows)
+
+	assert.NotNil(t, db.Query(ctx, &names, "select name, age"))
+	assert.NotNil(t, db.Query(ctx, names, "select name"))
+}
+
+func TestGoogleSheetRowStore_QueryColumnNamedAfterFunction(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewGoogleSheetRowStoreWithContext(
+		ctx,
+		nil,
+		"spreadsheet",
+		"items",
+		GoogleSheetRowStoreConfig{Columns: []string{"name", "count"}, MemoryBackend: memory.NewBackend()},
+	)
+	assert.Nil(t, err)
+
+	type item struct {
+		Name  string `db:"name"`
+		Count int64  `db:"count"`
+	}
+	assert.Nil(t, db.Insert(item{"item1", 3}, item{"item2", 5}, item{"item3", 5}).Exec(ctx))
+
+	type total struct {
+		Count int64 `db:"count"`
+		Total int64 `db:"total"`
+	}
+
+	var totals []total
+	err = db.Query(ctx, &totals, "select `count`, count(name) where count > ? group by count label count(name) 'total'", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []total{{3, 1}, {5, 2}}, totals)
+}
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"github.com/FreeLeh/GoFreeDB/internal/common"
-	"time"
+	"github.com/FreeLeh/GoFreeDB/internal/models"
 
+	"github.com/FreeLeh/GoFreeDB/internal/google/file"
+	"github.com/FreeLeh/GoFreeDB/internal/google/memory"
 	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
 )
 
@@ -21,6 +23,103 @@ type GoogleSheetRowStoreConfig struct {
 	// ColumnsWithFormula defines the list of column names containing a Google Sheet formula.
 	// Note that only string fields can have a formula.
 	ColumnsWithFormula []string
+
+	// LosslessNumbers enables storing integers outside the IEEE 754 safe integer boundary and math/big values
+	// (*big.Int, *big.Float and *big.Rat) without losing precision.
+	//
+	// Such values are stored as escaped strings, while values that can be represented exactly as a number
+	// are still stored as numbers, so numeric comparisons keep working for them.
+	// GoogleSheetSelectStmt.Exec will parse both representations back into the integer and math/big field types.
+	//
+	// Note that Google Sheets query treats a column with mixed value types based on the majority type,
+	// so comparing a value against a column containing both large and small values may fail.
+	// Use ColumnsWithStringNumbers for such columns, e.g. IDs that can be larger than 2^53.
+	LosslessNumbers bool
+
+	// ColumnsWithStringNumbers defines the list of column names whose numbers are always stored as strings,
+	// so that all values in the column have the same type. It requires LosslessNumbers.
+	//
+	// A placeholder compared against such a column with "=", "!=" or "<>" (e.g. Where("id = ?", id)) is converted
+	// into a string as well. Note that the other comparisons (e.g. "<" or ">") are lexicographic for such a column.
+	ColumnsWithStringNumbers []string
+
+	// ValueRenderOption defines how the values read from the Google Sheet are rendered.
+	// The default value is sheets.ValueRenderFormatted, which depends on the spreadsheet locale.
+	//
+	// Set it to sheets.ValueRenderUnformatted together with sheets.DateTimeRenderSerialNumber to read date, datetime
+	// and timeofday columns as serial numbers, independent of the spreadsheet locale.
+	ValueRenderOption sheets.ValueRenderOption
+
+	// DateTimeRenderOption defines how dates, times and durations read from the Google Sheet are rendered.
+	// It is ignored if ValueRenderOption is sheets.ValueRenderFormatted.
+	DateTimeRenderOption sheets.DateTimeRenderOption
+
+	// ValueInputOption defines how the values of all columns are written into the Google Sheet.
+	// The default value is sheets.ValueInputUserEntered.
+	//
+	// With sheets.ValueInputUserEntered, string values are prefixed with an apostrophe to prevent Google Sheets
+	// from converting them automatically (e.g. "1" into a number or "2020-01-01" into a date).
+	// With sheets.ValueInputRaw, values are written verbatim without any escaping.
+	//
+	// Columns listed in ColumnsWithFormula are always written as user entered values, so that the formula is evaluated.
+	// Note that writing RAW values requires 1 additional API call for each insert or update operation.
+	ValueInputOption sheets.ValueInputOption
+
+	// ColumnsWithRawInput defines the list of column names that are written as RAW values,
+	// even if ValueInputOption is sheets.ValueInputUserEntered. Each of them must be one of Columns.
+	ColumnsWithRawInput []string
+
+	// RetryPolicy defines how failed Google Sheets API calls are retried.
+	// The default value disables retries. Use sheets.DefaultRetryPolicy for the recommended policy.
+	RetryPolicy sheets.RetryPolicy
+
+	// RateLimiter limits the number of Google Sheets API calls made by the store.
+	// Share the same sheets.RateLimiter across stores to keep all of them within the same quota.
+	// The default value (nil) disables rate limiting.
+	RateLimiter *sheets.RateLimiter
+
+	// Endpoints overrides the Google Sheets API and query endpoints, e.g. to target a local emulator.
+	// The default value uses the Google endpoints.
+	Endpoints sheets.Endpoints
+
+	// MemoryBackend stores the data in the given memory.Backend instead of Google Sheets, e.g. for tests.
+	// The auth client is not used in this case and may be nil.
+	// The default value (nil) uses Google Sheets.
+	MemoryBackend *memory.Backend
+
+	// FileBackend stores the data in local CSV or XLSX files through the given file.Backend instead of Google Sheets,
+	// e.g. for offline development. The auth client is not used in this case and may be nil.
+	// It cannot be used together with MemoryBackend.
+	FileBackend *file.Backend
+
+	// Wrapper replaces the default sheets.Wrapper used for calling Google Sheets, e.g. with a decorator adding
+	// caching, logging or fault injection. The auth client and the other wrapper options (RetryPolicy, RateLimiter,
+	// Endpoints, MemoryBackend and FileBackend) are not used in this case.
+	// The wrapper must render the values based on ValueRenderOption and DateTimeRenderOption.
+	Wrapper SheetsWrapper
+
+	// Interceptors are called around every statement execution and Google Sheets operation made by the store,
+	// e.g. for logging, tracing or metrics. See NewTracingInterceptor and NewMetricsInterceptor.
+	Interceptors []Interceptor
+
+	// Cache enables caching the results of GoogleSheetSelectStmt.Exec and GoogleSheetCountStmt.Exec keyed by the
+	// generated query. The whole cache is invalidated whenever the store writes. The default value disables the cache.
+	Cache CacheConfig
+
+	// CoalesceReads makes the concurrent identical queries of GoogleSheetSelectStmt.Exec and
+	// GoogleSheetCountStmt.Exec share a single in-flight Google Sheets API call and its result.
+	// The shared call uses the context of the first caller, so its cancellation fails the call for the other callers.
+	// A query started after a write made by the store does not join the calls started before the write.
+	CoalesceReads bool
+
+	// SkipProvisioning skips creating the sheet and writing the header row when the store is created.
+	// The sheet must already exist with the header row matching Columns.
+	SkipProvisioning bool
+
+	// DryRun makes the insert, update and delete statements perform their reads (e.g. finding the matching rows),
+	// but skip their writes. Use the statement Explain methods to see what would have been written.
+	// Note that provisioning still creates the sheet, unless SkipProvisioning is set as well.
+	DryRun bool
 }
 
 func (c GoogleSheetRowStoreConfig) validate() error {
@@ -30,17 +129,72 @@ func (c GoogleSheetRowStoreConfig) validate() error {
 	if len(c.Columns) > maxColumn {
 		return fmt.Errorf("you can only have up to %d columns", maxColumn)
 	}
+
+	switch c.ValueInputOption {
+	case "", sheets.ValueInputUserEntered, sheets.ValueInputRaw:
+	default:
+		return fmt.Errorf("unsupported value input option: %s", c.ValueInputOption)
+	}
+
+	columns := common.NewSet(c.Columns)
+	colsWithFormula := common.NewSet(c.ColumnsWithFormula)
+	for _, col := range c.ColumnsWithRawInput {
+		if col == rowIdxCol {
+			return fmt.Errorf("column %s is managed by the store and cannot use RAW input", rowIdxCol)
+		}
+		if !columns.Contains(col) {
+			return fmt.Errorf("RAW input column %s is not one of the columns", col)
+		}
+		if colsWithFormula.Contains(col) {
+			return fmt.Errorf("column %s cannot contain a formula and use RAW input at the same time", col)
+		}
+	}
+	for _, col := range c.ColumnsWithStringNumbers {
+		if !c.LosslessNumbers {
+			return errors.New("columns with string numbers require LosslessNumbers")
+		}
+		if col == rowIdxCol {
+			return fmt.Errorf("column %s is managed by the store and cannot store numbers as strings", rowIdxCol)
+		}
+		if !columns.Contains(col) {
+			return fmt.Errorf("string number column %s is not one of the columns", col)
+		}
+		if colsWithFormula.Contains(col) {
+			return fmt.Errorf("column %s cannot contain a formula and store numbers as strings at the same time", col)
+		}
+	}
 	return nil
 }
 
+// rawInputColumns returns the list of columns written as RAW values.
+// The rowIdxCol column and the formula columns must always be evaluated as user entered values.
+func (c GoogleSheetRowStoreConfig) rawInputColumns() []string {
+	if c.ValueInputOption != sheets.ValueInputRaw {
+		return c.ColumnsWithRawInput
+	}
+
+	colsWithFormula := common.NewSet(c.ColumnsWithFormula)
+	result := make([]string, 0, len(c.Columns))
+	for _, col := range c.Columns {
+		if col != rowIdxCol && !colsWithFormula.Contains(col) {
+			result = append(result, col)
+		}
+	}
+	return result
+}
+
 // GoogleSheetRowStore encapsulates row store functionality on top of a Google Sheet.
 type GoogleSheetRowStore struct {
-	wrapper         sheetsWrapper
+	wrapper         SheetsWrapper
 	spreadsheetID   string
 	sheetName       string
 	colsMapping     common.ColsMapping
 	colsWithFormula *common.Set[string]
+	colsWithRaw     *common.Set[string]
+	colsWithStrNum  *common.Set[string]
 	config          GoogleSheetRowStoreConfig
+	cache           *cache
+	group           *flightGroup
 }
 
 // Select specifies which columns to return from the Google Sheet when querying and the output variable
@@ -50,8 +204,16 @@ type GoogleSheetRowStore struct {
 // If "columns" is an empty slice of string, then all columns will be returned.
 // If a column is not found in the provided list of columns in `GoogleSheetRowStoreConfig.Columns`, that column will be ignored.
 //
+// A column can also be an expression supported by Google Sheets query, e.g. "upper(name)", "year(dob)" or "price * qty".
+// An expression can be given an alias using "AS" (e.g. "upper(name) AS name_upper").
+// The alias is used as the column name when decoding the output and can be used in GoogleSheetSelectStmt.OrderBy.
+//
 // "output" must be a pointer to a slice of a data type.
 // The conversion from the Google Sheet data into the slice will be done using https://github.com/mitchellh/mapstructure.
+// The supported slice element types are:
+//   - A struct or a map (e.g. *[]Person or *[]map[string]interface{}), each row is keyed by the column name.
+//   - A scalar value (e.g. *[]string or *[]int), only if exactly 1 column is selected.
+//   - A slice (e.g. *[][]interface{}), each row contains the raw values following the selected columns ordering.
 //
 // If you are providing a slice of structs into the "output" parameter, and you want to define the mapping between the
 // column name with the field name, you should add a "db" struct tag.
@@ -68,6 +230,29 @@ func (s *GoogleSheetRowStore) Select(output interface{}, columns ...string) *Goo
 	return newGoogleSheetSelectStmt(s, output, columns)
 }
 
+// SelectOne works just like Select, but only the first matching row is decoded into the "output".
+//
+// "output" must be a non-nil pointer to a single value (e.g. a pointer to a struct, a map or a scalar value).
+// If there is no matching row, GoogleSheetSelectStmt.Exec returns a wrapped ErrRowNotFound.
+//
+// Please note that calling SelectOne() does not execute the query yet.
+// Call GoogleSheetSelectStmt.Exec to actually execute the query.
+func (s *GoogleSheetRowStore) SelectOne(output interface{}, columns ...string) *GoogleSheetSelectStmt {
+	stmt := newGoogleSheetSelectStmt(s, output, columns)
+	stmt.single = true
+	return stmt
+}
+
+// First works just like SelectOne, but the rows are ordered based on their insertion order by default.
+// Hence, the earliest inserted matching row is returned, unless GoogleSheetSelectStmt.OrderBy is called.
+//
+// Please note that calling First() does not execute the query yet.
+// Call GoogleSheetSelectStmt.Exec to actually execute the query.
+func (s *GoogleSheetRowStore) First(output interface{}, columns ...string) *GoogleSheetSelectStmt {
+	return s.SelectOne(output, columns...).
+		OrderBy([]models.ColumnOrderBy{{Column: rowIdxCol, OrderBy: models.OrderByAsc}})
+}
+
 // Insert specifies the rows to be inserted into the Google Sheet.
 //
 // The underlying data type of each row must be a struct or a pointer to a struct.
@@ -113,10 +298,121 @@ func (s *GoogleSheetRowStore) Close(_ context.Context) error {
 	return nil
 }
 
-func (s *GoogleSheetRowStore) ensureHeaders() error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
-	defer cancel()
+// Query runs a raw Google Visualization query and decodes the result into the "output".
+// It is useful for queries not supported by the statements, e.g. "pivot" or scalar functions.
+//
+// The query uses the column names instead of the column letters, e.g. "select name, sum(age) group by name".
+// Values should be replaced by a placeholder "?", just like in GoogleSheetSelectStmt.Where,
+// while quoted strings are never translated. The condition excluding the empty rows is added to the "where" clause.
+//
+// Function names, keywords and date and time literal prefixes (e.g. "date '2020-01-01'") are not translated either.
+// Quote a column named after them with backticks, e.g. "select `count`, count(age) group by `count`".
+//
+// "output" must be a pointer to a slice. The columns are matched with the struct fields (or map keys) by their labels,
+// which are the column names unless the "label" clause is used, e.g. "label sum(age) 'total'".
+//
+// There is only 1 API call behind the scene.
+func (s *GoogleSheetRowStore) Query(ctx context.Context, output interface{}, rawQuery string, args ...interface{}) error {
+	query := s.newRawQuery(rawQuery, args)
+	return s.intercept(ctx, "Query", query, func(ctx context.Context) error {
+		stmt, err := query.Generate()
+		if err != nil {
+			return err
+		}
+
+		result, err := s.wrapper.QueryRows(ctx, s.spreadsheetID, s.sheetName, stmt, true)
+		if err != nil {
+			return err
+		}
+		return s.decodeByLabel(result, output)
+	})
+}
+
+// cachedQueryRows runs the query of a read-only statement (GoogleSheetSelectStmt or GoogleSheetCountStmt)
+// through the store cache and the coalesced in-flight calls, see GoogleSheetRowStoreConfig.Cache and CoalesceReads.
+// Statements looking up the rows to write must call the wrapper directly, as they need the latest data.
+func (s *GoogleSheetRowStore) cachedQueryRows(ctx context.Context, query string) (sheets.QueryRowsResult, error) {
+	cached, generation, ok := s.cache.get(query)
+	if ok {
+		return copyQueryRowsResult(cached.(sheets.QueryRowsResult)), nil
+	}
+
+	value, shared, err := s.group.do(ctx, query, func() (interface{}, error) {
+		return s.wrapper.QueryRows(ctx, s.spreadsheetID, s.sheetName, query, true)
+	})
+	if err != nil {
+		return sheets.QueryRowsResult{}, err
+	}
+
+	result := value.(sheets.QueryRowsResult)
+	if s.cache != nil {
+		s.cache.put(query, copyQueryRowsResult(result), generation)
+	}
+	if shared {
+		return copyQueryRowsResult(result), nil
+	}
+	return result, nil
+}
+
+// newQueryBuilder creates a queryBuilder for the store columns with the `_rid is not null` guard applied.
+func (s *GoogleSheetRowStore) newQueryBuilder(colSelected []string) *queryBuilder {
+	builder := newQueryBuilder(s.colsMapping.NameMap(), ridWhereClauseInterceptor, colSelected)
+	builder.losslessNumbers = s.config.LosslessNumbers
+
+	if len(s.config.ColumnsWithStringNumbers) > 0 {
+		strNumCols := make([]string, 0, len(s.config.ColumnsWithStringNumbers))
+		for _, col := range s.config.ColumnsWithStringNumbers {
+			strNumCols = append(strNumCols, s.colsMapping[col].Name)
+		}
+		builder.strNumCols = common.NewSet(strNumCols)
+	}
+	return builder
+}
 
+// encodeValue converts the value provided by the client into the value written into the Google Sheet.
+func (s *GoogleSheetRowStore) encodeValue(col string, value interface{}) (interface{}, error) {
+	if s.colsWithStrNum != nil && s.colsWithStrNum.Contains(col) {
+		if str, ok := common.NumberString(value); ok {
+			value = str
+		}
+	}
+	if s.isRawInputColumn(col) {
+		return s.encodeRawValue(value)
+	}
+
+	escapedValue, err := escapeValue(col, value, s.colsWithFormula)
+	if err != nil {
+		return nil, err
+	}
+	if s.config.LosslessNumbers {
+		if encoded, ok := common.EncodeLosslessNumber(escapedValue); ok {
+			return encoded, nil
+		}
+	}
+	if err = common.CheckIEEE754SafeInteger(escapedValue); err != nil {
+		return nil, err
+	}
+	return escapedValue, nil
+}
+
+// encodeRawValue works just like encodeValue, but without escaping the string values.
+func (s *GoogleSheetRowStore) encodeRawValue(value interface{}) (interface{}, error) {
+	if s.config.LosslessNumbers {
+		if converted, ok := common.LosslessNumber(value); ok {
+			return converted, nil
+		}
+	}
+	if err := common.CheckIEEE754SafeInteger(value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (s *GoogleSheetRowStore) isRawInputColumn(col string) bool {
+	return s.colsWithRaw != nil && s.colsWithRaw.Contains(col)
+}
+
+func (s *GoogleSheetRowStore) ensureHeaders(ctx context.Context) error {
 	if _, err := s.wrapper.Clear(
 		ctx,
 		s.spreadsheetID,
@@ -143,36 +439,107 @@ func (s *GoogleSheetRowStore) ensureHeaders() error {
 
 // NewGoogleSheetRowStore creates an instance of the row based store with the given configuration.
 // It will also try to create the sheet, in case it does not exist yet.
+//
+// It panics if the configuration is invalid or the sheet cannot be provisioned.
+// Use NewGoogleSheetRowStoreWithContext to handle such errors instead.
 func NewGoogleSheetRowStore(
 	auth sheets.AuthClient,
 	spreadsheetID string,
 	sheetName string,
 	config GoogleSheetRowStoreConfig,
 ) *GoogleSheetRowStore {
-	if err := config.validate(); err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultProvisionTimeout)
+	defer cancel()
+
+	store, err := NewGoogleSheetRowStoreWithContext(ctx, auth, spreadsheetID, sheetName, config)
+	if err != nil {
 		panic(err)
 	}
+	return store
+}
 
-	wrapper, err := sheets.NewWrapper(auth)
+// NewGoogleSheetRowStoreWithContext works just like NewGoogleSheetRowStore, but it uses the given context
+// for provisioning the sheet and returns an error instead of panicking.
+//
+// Set GoogleSheetRowStoreConfig.SkipProvisioning to skip creating the sheet and writing the header row,
+// e.g. for read-only access or for sheets provisioned beforehand.
+func NewGoogleSheetRowStoreWithContext(
+	ctx context.Context,
+	auth sheets.AuthClient,
+	spreadsheetID string,
+	sheetName string,
+	config GoogleSheetRowStoreConfig,
+) (*GoogleSheetRowStore, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	wrapper, err := newSheetsWrapper(auth, config.Wrapper, config.MemoryBackend, config.FileBackend, sheets.WrapperConfig{
+		ValueRenderOption:    config.ValueRenderOption,
+		DateTimeRenderOption: config.DateTimeRenderOption,
+		RetryPolicy:          config.RetryPolicy,
+		RateLimiter:          config.RateLimiter,
+		Endpoints:            config.Endpoints,
+	})
 	if err != nil {
-		panic(fmt.Errorf("error creating sheets wrapper: %w", err))
+		return nil, err
 	}
+	cache := newCache(config.Cache)
+	group := newFlightGroup(config.CoalesceReads)
+	wrapper = invalidateOnWrite(interceptWrapper(wrapper, config.Interceptors), cache, group)
 
 	config = injectTimestampCol(config)
 	store := &GoogleSheetRowStore{
 		wrapper:         wrapper,
+		cache:           cache,
+		group:           group,
 		spreadsheetID:   spreadsheetID,
 		sheetName:       sheetName,
 		colsMapping:     common.GenerateColumnMapping(config.Columns),
 		colsWithFormula: common.NewSet(config.ColumnsWithFormula),
+		colsWithRaw:     common.NewSet(config.rawInputColumns()),
+		colsWithStrNum:  common.NewSet(config.ColumnsWithStringNumbers),
 		config:          config,
 	}
 
-	_ = ensureSheets(store.wrapper, store.spreadsheetID, store.sheetName)
-	if err := store.ensureHeaders(); err != nil {
-		panic(fmt.Errorf("error checking headers: %w", err))
+	if config.SkipProvisioning {
+		return store, nil
 	}
-	return store
+	if err := store.provision(ctx); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// queryGenerator generates the query of a statement, e.g. queryBuilder.
+type queryGenerator interface {
+	Generate() (string, error)
+}
+
+// intercept runs the statement execution through the configured interceptors.
+// The query is included in the Operation if the statement has one.
+func (s *GoogleSheetRowStore) intercept(ctx context.Context, name string, query queryGenerator, invoke Invoker) error {
+	op := Operation{
+		Kind:          OperationKindStatement,
+		Name:          name,
+		SpreadsheetID: s.spreadsheetID,
+		SheetName:     s.sheetName,
+	}
+	if query != nil {
+		// The generation error is returned by the statement execution itself.
+		op.Query, _ = query.Generate()
+	}
+	return runInterceptors(ctx, s.config.Interceptors, op, invoke)
+}
+
+func (s *GoogleSheetRowStore) provision(ctx context.Context) error {
+	if err := ensureSheets(ctx, s.wrapper, s.spreadsheetID, s.sheetName, sheets.GridSize{ColumnCount: maxColumn}); err != nil {
+		return fmt.Errorf("error ensuring sheet %s: %w", s.sheetName, err)
+	}
+	if err := s.ensureHeaders(ctx); err != nil {
+		return fmt.Errorf("error checking headers: %w", err)
+	}
+	return nil
 }
 
 // The additional rowIdxCol column is needed to differentiate which row is truly empty and which one is not.
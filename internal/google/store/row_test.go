@@ -8,6 +8,10 @@ import (
 	"time"
 
 	"github.com/FreeLeh/GoFreeDB/internal/common"
+	"github.com/FreeLeh/GoFreeDB/internal/google/file"
+	"github.com/FreeLeh/GoFreeDB/internal/google/fixtures"
+	"github.com/FreeLeh/GoFreeDB/internal/google/memory"
+	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
 	"github.com/FreeLeh/GoFreeDB/internal/models"
 
 	"github.com/FreeLeh/GoFreeDB/google/auth"
@@ -108,6 +112,161 @@ func TestGoogleSheetRowStore_Integration(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestGoogleSheetRowStore_Memory(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewGoogleSheetRowStoreWithContext(
+		ctx,
+		nil,
+		"spreadsheet",
+		"people",
+		GoogleSheetRowStoreConfig{
+			Columns:       []string{"name", "age", "dob"},
+			MemoryBackend: memory.NewBackend(),
+		},
+	)
+	assert.Nil(t, err)
+
+	count, err := db.Count().Exec(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), count)
+
+	var out []testPerson
+	err = db.Select(&out, "name", "age").Offset(10).Limit(10).Exec(ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, out)
+
+	err = db.Insert(
+		testPerson{"name1", 10, "1999-01-01"},
+		testPerson{"name2", 11, "2000-01-01"},
+		testPerson{"name3", 9007199254740992, "2001-01-01"},
+	).Exec(ctx)
+	assert.Nil(t, err)
+
+	err = db.Update(map[string]interface{}{"name": "name4"}).Where("age = ?", 10).Exec(ctx)
+	assert.Nil(t, err)
+
+	err = db.Select(&out, "name", "age", "dob").
+		Where("name = ? OR name = ?", "name2", "name3").
+		OrderBy([]models.ColumnOrderBy{{"name", models.OrderByDesc}}).
+		Limit(2).
+		Exec(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []testPerson{
+		{"name3", 9007199254740992, "2001-01-01"},
+		{"name2", 11, "2000-01-01"},
+	}, out)
+
+	count, err = db.Count().Where("name = ? OR name = ?", "name2", "name3").Exec(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), count)
+
+	err = db.Delete().Where("name = ?", "name4").Exec(ctx)
+	assert.Nil(t, err)
+
+	var remaining []testPerson
+	err = db.Select(&remaining, "name").OrderBy([]models.ColumnOrderBy{{"age", models.OrderByAsc}}).Offset(1).Exec(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []testPerson{{Name: "name3"}}, remaining)
+
+	err = db.Select(&remaining, "name").Where("unknown = ?", 1).Exec(ctx)
+	assert.NotNil(t, err)
+}
+
+func TestGoogleSheetRowStore_File(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	var backend *file.Backend
+	newStore := func() *GoogleSheetRowStore {
+		var err error
+		backend, err = file.NewBackend(dir, file.FormatCSV)
+		assert.Nil(t, err)
+
+		db, err := NewGoogleSheetRowStoreWithContext(
+			ctx,
+			nil,
+			"spreadsheet",
+			"people",
+			GoogleSheetRowStoreConfig{Columns: []string{"name", "age", "dob"}, FileBackend: backend},
+		)
+		assert.Nil(t, err)
+		return db
+	}
+
+	db := newStore()
+	err := db.Insert(
+		testPerson{"name1", 10, "1999-01-01"},
+		testPerson{"name2", 11, "2000-01-01"},
+	).Exec(ctx)
+	assert.Nil(t, err)
+	assert.Nil(t, db.Delete().Where("name = ?", "name1").Exec(ctx))
+
+	// The rows are read back from the files by a new store.
+	db = newStore()
+	var out []testPerson
+	assert.Nil(t, db.Select(&out).Exec(ctx))
+	assert.Equal(t, []testPerson{{"name2", 11, "2000-01-01"}}, out)
+
+	_, err = NewGoogleSheetRowStoreWithContext(
+		ctx,
+		nil,
+		"spreadsheet",
+		"people",
+		GoogleSheetRowStoreConfig{
+			Columns:       []string{"name"},
+			MemoryBackend: memory.NewBackend(),
+			FileBackend:   backend,
+		},
+	)
+	assert.NotNil(t, err)
+}
+
+type countingWrapper struct {
+	SheetsWrapper
+	queries int
+}
+
+func (w *countingWrapper) QueryRows(
+	ctx context.Context,
+	spreadsheetID string,
+	sheetName string,
+	query string,
+	skipHeader bool,
+) (sheets.QueryRowsResult, error) {
+	w.queries++
+	return w.SheetsWrapper.QueryRows(ctx, spreadsheetID, sheetName, query, skipHeader)
+}
+
+func TestGoogleSheetRowStore_CustomWrapper(t *testing.T) {
+	ctx := context.Background()
+	wrapper := &countingWrapper{SheetsWrapper: memory.NewWrapper(memory.NewBackend(), sheets.ValueRenderFormatted)}
+
+	db, err := NewGoogleSheetRowStoreWithContext(
+		ctx,
+		nil,
+		"spreadsheet",
+		"people",
+		GoogleSheetRowStoreConfig{Columns: []string{"name", "age", "dob"}, Wrapper: wrapper},
+	)
+	assert.Nil(t, err)
+
+	assert.Nil(t, db.Insert(testPerson{"name1", 10, "1999-01-01"}).Exec(ctx))
+
+	count, err := db.Count().Exec(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), count)
+	assert.Equal(t, 1, wrapper.queries)
+
+	_, err = NewGoogleSheetRowStoreWithContext(
+		ctx,
+		nil,
+		"spreadsheet",
+		"people",
+		GoogleSheetRowStoreConfig{Columns: []string{"name"}, Wrapper: wrapper, MemoryBackend: memory.NewBackend()},
+	)
+	assert.NotNil(t, err)
+}
+
 func TestGoogleSheetRowStore_Integration_EdgeCases(t *testing.T) {
 	spreadsheetID, authJSON, shouldRun := getIntegrationTestInfo()
 	if !shouldRun {
@@ -233,13 +392,105 @@ func TestGoogleSheetRowStoreConfig_validate(t *testing.T) {
 		assert.NotNil(t, conf.validate())
 	})
 
+	t.Run("unsupported_value_input_option", func(t *testing.T) {
+		conf := GoogleSheetRowStoreConfig{Columns: []string{"col1"}, ValueInputOption: "something"}
+		assert.NotNil(t, conf.validate())
+	})
+
+	t.Run("raw_input_with_formula", func(t *testing.T) {
+		conf := GoogleSheetRowStoreConfig{
+			Columns:             []string{"col1"},
+			ColumnsWithFormula:  []string{"col1"},
+			ColumnsWithRawInput: []string{"col1"},
+		}
+		assert.NotNil(t, conf.validate())
+	})
+
+	t.Run("unknown_raw_input_column", func(t *testing.T) {
+		conf := GoogleSheetRowStoreConfig{Columns: []string{"col1"}, ColumnsWithRawInput: []string{"col2"}}
+		assert.NotNil(t, conf.validate())
+	})
+
+	t.Run("raw_input_row_index_column", func(t *testing.T) {
+		conf := GoogleSheetRowStoreConfig{Columns: []string{"col1"}, ColumnsWithRawInput: []string{rowIdxCol}}
+		assert.NotNil(t, conf.validate())
+	})
+
+	t.Run("string_numbers_without_lossless_numbers", func(t *testing.T) {
+		conf := GoogleSheetRowStoreConfig{Columns: []string{"col1"}, ColumnsWithStringNumbers: []string{"col1"}}
+		assert.NotNil(t, conf.validate())
+	})
+
+	t.Run("unknown_string_number_column", func(t *testing.T) {
+		conf := GoogleSheetRowStoreConfig{
+			Columns:                  []string{"col1"},
+			LosslessNumbers:          true,
+			ColumnsWithStringNumbers: []string{"col2"},
+		}
+		assert.NotNil(t, conf.validate())
+	})
+
+	t.Run("string_number_formula_column", func(t *testing.T) {
+		conf := GoogleSheetRowStoreConfig{
+			Columns:                  []string{"col1"},
+			ColumnsWithFormula:       []string{"col1"},
+			LosslessNumbers:          true,
+			ColumnsWithStringNumbers: []string{"col1"},
+		}
+		assert.NotNil(t, conf.validate())
+	})
+
 	t.Run("no_error", func(t *testing.T) {
 		columns := make([]string, 0)
 		for i := 0; i < 10; i++ {
 			columns = append(columns, strconv.FormatInt(int64(i), 10))
 		}
 
-		conf := GoogleSheetRowStoreConfig{Columns: columns}
+		conf := GoogleSheetRowStoreConfig{Columns: columns, ColumnsWithRawInput: []string{"1"}}
 		assert.Nil(t, conf.validate())
 	})
 }
+
+func TestNewGoogleSheetRowStoreWithContext(t *testing.T) {
+	googleAuth, err := auth.NewServiceFromFile(fixtures.PathToFixture("service_account.json"), []string{}, auth.ServiceConfig{})
+	assert.Nil(t, err)
+
+	t.Run("invalid_config", func(t *testing.T) {
+		store, err := NewGoogleSheetRowStoreWithContext(
+			context.Background(),
+			googleAuth,
+			"spreadsheet",
+			"sheet",
+			GoogleSheetRowStoreConfig{Columns: []string{}},
+		)
+		assert.Nil(t, store)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("skip_provisioning", func(t *testing.T) {
+		store, err := NewGoogleSheetRowStoreWithContext(
+			context.Background(),
+			googleAuth,
+			"spreadsheet",
+			"sheet",
+			GoogleSheetRowStoreConfig{Columns: []string{"name", "age"}, SkipProvisioning: true},
+		)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{rowIdxCol, "name", "age"}, store.config.Columns)
+	})
+
+	t.Run("provisioning_error", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		store, err := NewGoogleSheetRowStoreWithContext(
+			ctx,
+			googleAuth,
+			"spreadsheet",
+			"sheet",
+			GoogleSheetRowStoreConfig{Columns: []string{"name", "age"}},
+		)
+		assert.Nil(t, store)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"context"
+	"sync"
+)
+
+// flightGroup coalesces concurrent calls with the same key into a single call whose result is shared.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+	dups  int
+}
+
+// newFlightGroup returns nil if coalescing is disabled. All flightGroup methods accept a nil receiver.
+func newFlightGroup(enabled bool) *flightGroup {
+	if !enabled {
+		return nil
+	}
+	return &flightGroup{calls: make(map[string]*flightCall)}
+}
+
+// do calls fn, unless there is an in-flight call with the same key, in which case its result is awaited instead.
+// The returned shared flag tells whether the result was returned to more than one caller.
+// A waiting caller returns early with the context error if its own context is done.
+func (g *flightGroup) do(
+	ctx context.Context,
+	key string,
+	fn func() (interface{}, error),
+) (value interface{}, shared bool, err error) {
+	if g == nil {
+		value, err = fn()
+		return value, false, err
+	}
+
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		g.mu.Unlock()
+
+		select {
+		case <-c.done:
+			return c.value, true, c.err
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+	}
+
+	c := &flightCall{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	c.value, c.err = fn()
+
+	g.mu.Lock()
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+	shared = c.dups > 0
+	g.mu.Unlock()
+
+	close(c.done)
+	return c.value, shared, c.err
+}
+
+// forget makes the following calls with the given keys (or with any key if no key is given)
+// start a new call instead of joining the in-flight one, e.g. after a write.
+func (g *flightGroup) forget(keys ...string) {
+	if g == nil {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(keys) == 0 {
+		g.calls = make(map[string]*flightCall)
+		return
+	}
+	for _, key := range keys {
+		delete(g.calls, key)
+	}
+}
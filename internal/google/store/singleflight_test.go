@@ -0,0 +1,171 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/FreeLeh/GoFreeDB/internal/google/memory"
+	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
+	"github.com/stretchr/testify/assert"
+)
+
+// waitForDups waits until the in-flight call with the given key has the given number of waiting callers.
+func waitForDups(t *testing.T, g *flightGroup, key string, dups int) {
+	for i := 0; i < 1000; i++ {
+		g.mu.Lock()
+		c, ok := g.calls[key]
+		joined := ok && c.dups == dups
+		g.mu.Unlock()
+
+		if joined {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("callers did not join the in-flight call of %s", key)
+}
+
+func TestFlightGroup(t *testing.T) {
+	ctx := context.Background()
+	g := newFlightGroup(true)
+	release := make(chan struct{})
+
+	var calls int32
+	fn := func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return "value", nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]interface{}, 3)
+	sharedFlags := make([]bool, 3)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], sharedFlags[i], _ = g.do(ctx, "key", fn)
+		}(i)
+
+		if i == 0 {
+			waitForDups(t, g, "key", 0)
+		}
+	}
+	waitForDups(t, g, "key", 2)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, _, err := g.do(cancelled, "key", fn)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls)
+	assert.Equal(t, []interface{}{"value", "value", "value"}, results)
+	assert.Equal(t, []bool{true, true, true}, sharedFlags)
+
+	// The following call starts a new call, as the previous one has completed.
+	expectedErr := errors.New("error")
+	_, shared, err := g.do(ctx, "key", func() (interface{}, error) { return nil, expectedErr })
+	assert.ErrorIs(t, err, expectedErr)
+	assert.False(t, shared)
+
+	var disabled *flightGroup
+	value, shared, err := disabled.do(ctx, "key", func() (interface{}, error) { return "value", nil })
+	assert.Nil(t, err)
+	assert.False(t, shared)
+	assert.Equal(t, "value", value)
+}
+
+func TestFlightGroup_Forget(t *testing.T) {
+	ctx := context.Background()
+	g := newFlightGroup(true)
+	release := make(chan struct{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _, _ = g.do(ctx, "key", func() (interface{}, error) {
+			<-release
+			return "old", nil
+		})
+	}()
+	waitForDups(t, g, "key", 0)
+
+	g.forget("key")
+	value, shared, err := g.do(ctx, "key", func() (interface{}, error) { return "new", nil })
+	assert.Nil(t, err)
+	assert.False(t, shared)
+	assert.Equal(t, "new", value)
+
+	close(release)
+	<-done
+}
+
+type blockingWrapper struct {
+	SheetsWrapper
+	release chan struct{}
+	queries int32
+}
+
+func (w *blockingWrapper) QueryRows(
+	ctx context.Context,
+	spreadsheetID string,
+	sheetName string,
+	query string,
+	skipHeader bool,
+) (sheets.QueryRowsResult, error) {
+	atomic.AddInt32(&w.queries, 1)
+	<-w.release
+	return w.SheetsWrapper.QueryRows(ctx, spreadsheetID, sheetName, query, skipHeader)
+}
+
+func TestGoogleSheetRowStore_CoalesceReads(t *testing.T) {
+	ctx := context.Background()
+	wrapper := &blockingWrapper{
+		SheetsWrapper: memory.NewWrapper(memory.NewBackend(), sheets.ValueRenderFormatted),
+		release:       make(chan struct{}),
+	}
+	close(wrapper.release)
+
+	db, err := NewGoogleSheetRowStoreWithContext(
+		ctx,
+		nil,
+		"spreadsheet",
+		"people",
+		GoogleSheetRowStoreConfig{Columns: []string{"name", "age", "dob"}, Wrapper: wrapper, CoalesceReads: true},
+	)
+	assert.Nil(t, err)
+	assert.Nil(t, db.Insert(testPerson{"name1", 10, "1999-01-01"}).Exec(ctx))
+
+	wrapper.release = make(chan struct{})
+	atomic.StoreInt32(&wrapper.queries, 0)
+
+	stmt := db.Select(nil).Where("name = ?", "name1")
+	query, err := stmt.ToQuery()
+	assert.Nil(t, err)
+
+	var wg sync.WaitGroup
+	outputs := make([][]testPerson, 3)
+	for i := range outputs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.Nil(t, db.Select(&outputs[i]).Where("name = ?", "name1").Exec(ctx))
+		}(i)
+	}
+
+	waitForDups(t, db.group, query, 2)
+	close(wrapper.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&wrapper.queries))
+	for _, output := range outputs {
+		assert.Equal(t, []testPerson{{"name1", 10, "1999-01-01"}}, output)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -16,15 +17,26 @@ import (
 
 type whereInterceptorFunc func(where string) string
 
+type columnFormat struct {
+	column  string
+	pattern string
+}
+
 type queryBuilder struct {
 	replacer         *strings.Replacer
 	columns          []string
+	labels           []string
+	formats          []columnFormat
+	options          []models.QueryOption
+	distinct         bool
 	where            string
 	whereArgs        []interface{}
 	whereInterceptor whereInterceptorFunc
 	orderBy          []string
 	limit            uint64
 	offset           uint64
+	losslessNumbers  bool
+	strNumCols       *common.Set[string]
 }
 
 func (q *queryBuilder) Where(condition string, args ...interface{}) *queryBuilder {
@@ -63,6 +75,9 @@ func (q *queryBuilder) Generate() (string, error) {
 	if err := q.writeWhere(stmt); err != nil {
 		return "", err
 	}
+	if err := q.writeGroupBy(stmt); err != nil {
+		return "", err
+	}
 	if err := q.writeOrderBy(stmt); err != nil {
 		return "", err
 	}
@@ -72,22 +87,92 @@ func (q *queryBuilder) Generate() (string, error) {
 	if err := q.writeLimit(stmt); err != nil {
 		return "", err
 	}
+	if err := q.writeLabel(stmt); err != nil {
+		return "", err
+	}
+	if err := q.writeFormat(stmt); err != nil {
+		return "", err
+	}
+	if err := q.writeOptions(stmt); err != nil {
+		return "", err
+	}
 
 	return stmt.String(), nil
 }
 
+// Distinct removes duplicate rows from the result.
+//
+// Google Sheets query does not have a DISTINCT keyword, so all selected columns are grouped instead.
+// As grouping requires an aggregation, a COUNT() of the row index column is selected as the last column.
+// Hence, the caller must remove the last column from the query result.
+func (q *queryBuilder) Distinct() *queryBuilder {
+	q.distinct = true
+	return q
+}
+
+// Format specifies the format pattern of the given column, which affects the formatted values.
+func (q *queryBuilder) Format(column string, pattern string) *queryBuilder {
+	q.formats = append(q.formats, columnFormat{column: column, pattern: pattern})
+	return q
+}
+
+// Options specifies the options clause of the query.
+func (q *queryBuilder) Options(options ...models.QueryOption) *queryBuilder {
+	q.options = options
+	return q
+}
+
 func (q *queryBuilder) writeCols(stmt *strings.Builder) error {
 	stmt.WriteString(" ")
 
+	translated := make([]string, 0, len(q.columns)+1)
+	for _, col := range q.columns {
+		translated = append(translated, q.replacer.Replace(col))
+	}
+	if q.distinct {
+		translated = append(translated, q.replacer.Replace(fmt.Sprintf("COUNT(%s)", rowIdxCol)))
+	}
+
+	stmt.WriteString(strings.Join(translated, ", "))
+	return nil
+}
+
+func (q *queryBuilder) writeGroupBy(stmt *strings.Builder) error {
+	if !q.distinct {
+		return nil
+	}
+
 	translated := make([]string, 0, len(q.columns))
 	for _, col := range q.columns {
 		translated = append(translated, q.replacer.Replace(col))
 	}
 
+	stmt.WriteString(" group by ")
 	stmt.WriteString(strings.Join(translated, ", "))
 	return nil
 }
 
+func (q *queryBuilder) writeLabel(stmt *strings.Builder) error {
+	result := make([]string, 0, len(q.labels))
+	for i, label := range q.labels {
+		if label == "" || i >= len(q.columns) {
+			continue
+		}
+		if strings.ContainsAny(label, "'\"") {
+			return fmt.Errorf("column alias must not contain any quote: %s", label)
+		}
+		result = append(result, q.replacer.Replace(q.columns[i])+" '"+label+"'")
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+
+	stmt.WriteString(" label ")
+	stmt.WriteString(strings.Join(result, ", "))
+	return nil
+}
+
 func (q *queryBuilder) writeWhere(stmt *strings.Builder) error {
 	where := q.where
 	if q.whereInterceptor != nil {
@@ -106,7 +191,7 @@ func (q *queryBuilder) writeWhere(stmt *strings.Builder) error {
 	result = append(result, strings.TrimSpace(tokens[0]))
 
 	for i, token := range tokens[1:] {
-		arg, err := q.convertArg(q.whereArgs[i])
+		arg, err := q.convertWhereArg(tokens[i], q.whereArgs[i])
 		if err != nil {
 			return fmt.Errorf("failed converting 'where' arguments: %v, %w", arg, err)
 		}
@@ -118,7 +203,63 @@ func (q *queryBuilder) writeWhere(stmt *strings.Builder) error {
 	return nil
 }
 
+func (q *queryBuilder) writeFormat(stmt *strings.Builder) error {
+	if len(q.formats) == 0 {
+		return nil
+	}
+
+	result := make([]string, 0, len(q.formats))
+	for _, f := range q.formats {
+		if strings.ContainsAny(f.pattern, "'\"") {
+			return fmt.Errorf("column format pattern must not contain any quote: %s", f.pattern)
+		}
+		result = append(result, q.replacer.Replace(f.column)+" '"+f.pattern+"'")
+	}
+
+	stmt.WriteString(" format ")
+	stmt.WriteString(strings.Join(result, ", "))
+	return nil
+}
+
+func (q *queryBuilder) writeOptions(stmt *strings.Builder) error {
+	if len(q.options) == 0 {
+		return nil
+	}
+
+	result := make([]string, 0, len(q.options))
+	for _, o := range q.options {
+		switch o {
+		case models.QueryOptionNoFormat, models.QueryOptionNoValues:
+			result = append(result, string(o))
+		default:
+			return fmt.Errorf("unsupported query option: %s", o)
+		}
+	}
+
+	stmt.WriteString(" options ")
+	stmt.WriteString(strings.Join(result, " "))
+	return nil
+}
+
+// convertWhereArg works just like convertArg, but a number compared against a column storing numbers as strings
+// (e.g. "B = ?") is converted into its string representation, so that it matches the stored value.
+func (q *queryBuilder) convertWhereArg(preceding string, arg interface{}) (string, error) {
+	if q.strNumCols != nil {
+		match := stringNumberComparisonRegex.FindStringSubmatch(preceding)
+		if match != nil && q.strNumCols.Contains(match[1]) {
+			if str, ok := common.NumberString(arg); ok {
+				arg = str
+			}
+		}
+	}
+	return q.convertArg(arg)
+}
+
 func (q *queryBuilder) convertArg(arg interface{}) (string, error) {
+	if q.losslessNumbers {
+		arg, _ = common.LosslessNumber(arg)
+	}
+
 	switch converted := arg.(type) {
 	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
 		return q.convertInt(arg)
@@ -244,7 +385,11 @@ type GoogleSheetSelectStmt struct {
 	store        *GoogleSheetRowStore
 	columns      []string
 	queryBuilder *queryBuilder
+	aliases      map[string]string
 	output       interface{}
+	metadata     *[]models.ColumnMetadata
+	single       bool
+	pageSize     uint64
 }
 
 // Where specifies the condition to meet for a row to be included.
@@ -269,7 +414,53 @@ func (s *GoogleSheetSelectStmt) Where(condition string, args ...interface{}) *Go
 //
 // The default value is no ordering specified.
 func (s *GoogleSheetSelectStmt) OrderBy(ordering []models.ColumnOrderBy) *GoogleSheetSelectStmt {
-	s.queryBuilder.OrderBy(ordering)
+	translated := make([]models.ColumnOrderBy, 0, len(ordering))
+	for _, o := range ordering {
+		if expr, ok := s.aliases[o.Column]; ok {
+			o.Column = expr
+		}
+		translated = append(translated, o)
+	}
+
+	s.queryBuilder.OrderBy(translated)
+	return s
+}
+
+// Format specifies the format pattern used for the formatted values of a column (or an alias).
+// The pattern follows the Google Sheets number and date format patterns, e.g. "yyyy-mm-dd" or "#,##0.00".
+//
+// As date, datetime and timeofday columns are returned as formatted values by default, this can be used
+// to return them in a format independent of the spreadsheet locale.
+func (s *GoogleSheetSelectStmt) Format(column string, pattern string) *GoogleSheetSelectStmt {
+	if expr, ok := s.aliases[column]; ok {
+		column = expr
+	}
+	s.queryBuilder.Format(column, pattern)
+	return s
+}
+
+// Options specifies the options clause of the query, i.e. models.QueryOptionNoFormat or models.QueryOptionNoValues.
+//
+// With QueryOptionNoValues, all values are returned as formatted strings.
+// With QueryOptionNoFormat, date, datetime and timeofday values are returned as serial numbers.
+func (s *GoogleSheetSelectStmt) Options(options ...models.QueryOption) *GoogleSheetSelectStmt {
+	s.queryBuilder.Options(options...)
+	return s
+}
+
+// ColumnMetadata specifies where the metadata of the returned columns (e.g. type and label) should be stored.
+// The metadata is stored when Exec is called, so that generic tools can render the result without knowing the schema.
+func (s *GoogleSheetSelectStmt) ColumnMetadata(metadata *[]models.ColumnMetadata) *GoogleSheetSelectStmt {
+	s.metadata = metadata
+	return s
+}
+
+// Distinct specifies that only unique rows (based on all selected columns) should be returned.
+//
+// Please note that the rows will be ordered by the selected columns, unless OrderBy is called.
+// Distinct cannot be used together with GoogleSheetSelectStmt.Rows.
+func (s *GoogleSheetSelectStmt) Distinct() *GoogleSheetSelectStmt {
+	s.queryBuilder.Distinct()
 	return s
 }
 
@@ -289,32 +480,158 @@ func (s *GoogleSheetSelectStmt) Offset(offset uint64) *GoogleSheetSelectStmt {
 	return s
 }
 
+// PageSize specifies the number of rows fetched per API call when iterating the rows using Rows().
+//
+// The default value is 1000.
+func (s *GoogleSheetSelectStmt) PageSize(size uint64) *GoogleSheetSelectStmt {
+	s.pageSize = size
+	return s
+}
+
 // Exec retrieves rows matching with the given condition.
 //
+// If the statement is created using GoogleSheetRowStore.SelectOne or GoogleSheetRowStore.First,
+// only the first matching row is decoded into the output and a wrapped ErrRowNotFound is returned
+// when there is no matching row.
+//
 // There is only 1 API call behind the scene.
 func (s *GoogleSheetSelectStmt) Exec(ctx context.Context) error {
+	if s.single {
+		s.queryBuilder.Limit(1)
+	}
+	return s.store.intercept(ctx, "Select", s.queryBuilder, s.exec)
+}
+
+func (s *GoogleSheetSelectStmt) exec(ctx context.Context) error {
+	if s.single {
+		return s.execSingle(ctx)
+	}
+
 	if err := s.ensureOutputSlice(); err != nil {
 		return err
 	}
 
-	stmt, err := s.queryBuilder.Generate()
+	result, err := s.query(ctx)
 	if err != nil {
 		return err
 	}
+	return s.decode(result, s.output)
+}
 
-	result, err := s.store.wrapper.QueryRows(
-		ctx,
-		s.store.spreadsheetID,
-		s.store.sheetName,
-		stmt,
-		true,
-	)
+func (s *GoogleSheetSelectStmt) execSingle(ctx context.Context) error {
+	if err := s.ensureOutputSingle(); err != nil {
+		return err
+	}
+
+	result, err := s.query(ctx)
 	if err != nil {
 		return err
 	}
 
-	m := s.buildQueryResultMap(result)
-	return common.MapStructureDecode(m, s.output)
+	found, err := s.decodeSingle(result, s.output)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%w: %s", models.ErrRowNotFound, s.store.sheetName)
+	}
+	return nil
+}
+
+// decodeSingle decodes the first row of the query result into the output, which must be a pointer to a single value.
+// It returns false if the query result is empty.
+func (s *GoogleSheetSelectStmt) decodeSingle(result sheets.QueryRowsResult, output interface{}) (bool, error) {
+	outputValue := reflect.ValueOf(output).Elem()
+	rows := reflect.New(reflect.SliceOf(outputValue.Type()))
+	if err := s.decode(result, rows.Interface()); err != nil {
+		return false, err
+	}
+
+	if rows.Elem().Len() == 0 {
+		return false, nil
+	}
+	outputValue.Set(rows.Elem().Index(0))
+	return true, nil
+}
+
+func (s *GoogleSheetSelectStmt) query(ctx context.Context) (sheets.QueryRowsResult, error) {
+	stmt, err := s.queryBuilder.Generate()
+	if err != nil {
+		return sheets.QueryRowsResult{}, err
+	}
+
+	result, err := s.store.cachedQueryRows(ctx, stmt)
+	if err != nil {
+		return sheets.QueryRowsResult{}, err
+	}
+
+	// The last column is the COUNT() column required for grouping the rows, see queryBuilder.Distinct.
+	if s.queryBuilder.distinct {
+		for i, row := range result.Rows {
+			if len(row) > len(s.columns) {
+				result.Rows[i] = row[:len(s.columns)]
+			}
+		}
+		if len(result.Columns) > len(s.columns) {
+			result.Columns = result.Columns[:len(s.columns)]
+		}
+	}
+
+	if s.metadata != nil {
+		*s.metadata = s.buildColumnMetadata(result)
+	}
+	return result, nil
+}
+
+func (s *GoogleSheetSelectStmt) buildColumnMetadata(result sheets.QueryRowsResult) []models.ColumnMetadata {
+	metadata := make([]models.ColumnMetadata, len(s.columns))
+	for i, col := range s.columns {
+		metadata[i].Name = col
+		if i < len(result.Columns) {
+			metadata[i].Label = result.Columns[i].Label
+			metadata[i].Type = result.Columns[i].Type
+			metadata[i].Pattern = result.Columns[i].Pattern
+		}
+	}
+	return metadata
+}
+
+// decode converts the query result based on the slice element type of the output:
+//   - A slice element type (e.g. *[][]interface{}) receives the raw rows, following the selected columns ordering.
+//   - A scalar element type (e.g. *[]string or *[]int) receives the values of the only selected column.
+//   - Other element types (e.g. structs or maps) receive each row as a map from the column name to its value.
+func (s *GoogleSheetSelectStmt) decode(result sheets.QueryRowsResult, output interface{}) error {
+	var input interface{}
+
+	elem := reflect.TypeOf(output).Elem().Elem()
+	switch {
+	case isRawRowType(elem):
+		input = result.Rows
+	case isScalarType(elem):
+		if len(s.columns) != 1 {
+			return fmt.Errorf("select statement output of type %s requires exactly 1 selected column, got %d", elem, len(s.columns))
+		}
+		input = s.buildQueryResultColumn(result)
+	default:
+		input = s.buildQueryResultMap(result)
+	}
+
+	if s.store.config.LosslessNumbers {
+		return common.MapStructureDecode(input, output, common.LosslessNumberDecodeHook)
+	}
+	return common.MapStructureDecode(input, output)
+}
+
+func (s *GoogleSheetSelectStmt) buildQueryResultColumn(original sheets.QueryRowsResult) []interface{} {
+	result := make([]interface{}, 0, len(original.Rows))
+	for _, row := range original.Rows {
+		if len(row) == 0 {
+			result = append(result, nil)
+			continue
+		}
+		result = append(result, row[0])
+	}
+	return result
 }
 
 func (s *GoogleSheetSelectStmt) buildQueryResultMap(original sheets.QueryRowsResult) []map[string]interface{} {
@@ -360,23 +677,90 @@ func (s *GoogleSheetSelectStmt) ensureOutputSlice() error {
 	return nil
 }
 
+func (s *GoogleSheetSelectStmt) ensureOutputSingle() error {
+	if s.output == nil {
+		return errors.New("select statement output cannot be empty or nil")
+	}
+
+	t := reflect.TypeOf(s.output)
+	if t.Kind() != reflect.Ptr || reflect.ValueOf(s.output).IsNil() {
+		return errors.New("select one statement output must be a non-nil pointer")
+	}
+	return nil
+}
+
 func newGoogleSheetSelectStmt(store *GoogleSheetRowStore, output interface{}, columns []string) *GoogleSheetSelectStmt {
 	if len(columns) == 0 {
 		columns = store.config.Columns
 	}
 
+	names := make([]string, 0, len(columns))
+	exprs := make([]string, 0, len(columns))
+	labels := make([]string, 0, len(columns))
+	aliases := make(map[string]string)
+
+	for _, col := range columns {
+		expr, alias := parseSelectColumn(col)
+		exprs = append(exprs, expr)
+		labels = append(labels, alias)
+
+		if alias == "" {
+			names = append(names, expr)
+		} else {
+			names = append(names, alias)
+			aliases[alias] = expr
+		}
+	}
+
+	builder := store.newQueryBuilder(exprs)
+	builder.labels = labels
+
 	return &GoogleSheetSelectStmt{
 		store:        store,
-		columns:      columns,
-		queryBuilder: newQueryBuilder(store.colsMapping.NameMap(), ridWhereClauseInterceptor, columns),
+		columns:      names,
+		aliases:      aliases,
+		queryBuilder: builder,
 		output:       output,
 	}
 }
 
+// parseSelectColumn splits a selected column into its expression and its alias (if any).
+// For example, "upper(name) AS name_upper" returns "upper(name)" and "name_upper".
+func parseSelectColumn(col string) (string, string) {
+	matches := selectColumnAliasRegex.FindStringSubmatch(col)
+	if matches == nil {
+		return strings.TrimSpace(col), ""
+	}
+	return strings.TrimSpace(matches[1]), matches[2]
+}
+
 // GoogleSheetInsertStmt encapsulates information required to insert new rows into the Google Sheet.
 type GoogleSheetInsertStmt struct {
 	store *GoogleSheetRowStore
 	rows  []interface{}
+	bulk  bulkOptions
+}
+
+// ChunkSize defines the maximum number of rows appended by a single API call.
+// The rows are always split into chunks, so that a large insertion does not exceed the Google Sheets request
+// payload limits. By default, a chunk has up to 1000 rows and up to about 2 MB of encoded values.
+func (s *GoogleSheetInsertStmt) ChunkSize(rows int) *GoogleSheetInsertStmt {
+	s.bulk.chunkSize = rows
+	return s
+}
+
+// Concurrency defines the maximum number of chunks appended concurrently (1 by default).
+// Note that the chunks may be appended in any order when the concurrency is more than 1.
+func (s *GoogleSheetInsertStmt) Concurrency(workers int) *GoogleSheetInsertStmt {
+	s.bulk.concurrency = workers
+	return s
+}
+
+// OnProgress registers a callback called after every chunk is appended or fails.
+// The calls are serialised, even if the chunks are appended concurrently.
+func (s *GoogleSheetInsertStmt) OnProgress(fn func(BulkProgress)) *GoogleSheetInsertStmt {
+	s.bulk.onProgress = fn
+	return s
 }
 
 func (s *GoogleSheetInsertStmt) convertRowToSlice(row interface{}) ([]interface{}, error) {
@@ -402,14 +786,11 @@ func (s *GoogleSheetInsertStmt) convertRowToSlice(row interface{}) ([]interface{
 
 	for col, value := range output {
 		if colIdx, ok := s.store.colsMapping[col]; ok {
-			escapedValue, err := escapeValue(col, value, s.store.colsWithFormula)
+			encodedValue, err := s.store.encodeValue(col, value)
 			if err != nil {
 				return nil, err
 			}
-			if err = common.CheckIEEE754SafeInteger(escapedValue); err != nil {
-				return nil, err
-			}
-			result[colIdx.Idx] = escapedValue
+			result[colIdx.Idx] = encodedValue
 		}
 	}
 
@@ -419,28 +800,137 @@ func (s *GoogleSheetInsertStmt) convertRowToSlice(row interface{}) ([]interface{
 // Exec inserts the provided new rows data into Google Sheet.
 // This method calls the relevant Google Sheet APIs to actually insert the new rows.
 //
-// There is only 1 API call behind the scene.
+// There is only 1 API call behind the scene per chunk of rows (see ChunkSize), unless the store has RAW input columns
+// (see GoogleSheetRowStoreConfig.ColumnsWithRawInput). In that case, the RAW values are written by a 2nd API call
+// after the rows are appended. If the 2nd call fails, the appended rows are cleared and the returned error says so.
+// If clearing them fails as well, the returned error says that the rows were appended partially.
+// If some chunks fail while the others are appended, a *BulkError describing the failed chunks is returned.
 func (s *GoogleSheetInsertStmt) Exec(ctx context.Context) error {
 	if len(s.rows) == 0 {
 		return nil
 	}
+	return s.store.intercept(ctx, "Insert", nil, s.exec)
+}
+
+func (s *GoogleSheetInsertStmt) exec(ctx context.Context) error {
+	convertedRows, err := s.convertRows(s.rows)
+	if err != nil {
+		return err
+	}
+	chunks := s.bulk.split(len(convertedRows), func(i int) int { return encodedSize(convertedRows[i]) })
+	return s.bulk.run(ctx, chunks, func(ctx context.Context, idx int) error {
+		return s.write(ctx, convertedRows[chunks[idx].start:chunks[idx].end])
+	})
+}
 
-	convertedRows := make([][]interface{}, 0, len(s.rows))
-	for _, row := range s.rows {
+func (s *GoogleSheetInsertStmt) convertRows(rows []interface{}) ([][]interface{}, error) {
+	convertedRows := make([][]interface{}, 0, len(rows))
+	for _, row := range rows {
 		r, err := s.convertRowToSlice(row)
 		if err != nil {
-			return fmt.Errorf("cannot execute google sheet insert statement due to row conversion error: %w", err)
+			return nil, fmt.Errorf("cannot execute google sheet insert statement due to row conversion error: %w", err)
 		}
 		convertedRows = append(convertedRows, r)
 	}
+	return convertedRows, nil
+}
 
-	_, err := s.store.wrapper.OverwriteRows(
+// write appends the converted rows with a single append call (plus one batch update call for the RAW input columns).
+func (s *GoogleSheetInsertStmt) write(ctx context.Context, convertedRows [][]interface{}) error {
+	if s.store.config.DryRun {
+		return nil
+	}
+
+	rawRows := s.extractRawValues(convertedRows)
+
+	result, err := s.store.wrapper.OverwriteRows(
 		ctx,
 		s.store.spreadsheetID,
 		common.GetA1Range(s.store.sheetName, defaultRowFullTableRange),
 		convertedRows,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	if len(rawRows) == 0 {
+		return nil
+	}
+
+	requests, err := s.generateRawUpdateRequests(result.UpdatedRange, rawRows)
+	if err == nil {
+		_, err = s.store.wrapper.BatchUpdateRowsWithOption(ctx, s.store.spreadsheetID, requests, sheets.ValueInputRaw)
+	}
+	if err != nil {
+		return s.clearAppendedRows(ctx, result.UpdatedRange, err)
+	}
+	return nil
+}
+
+// clearAppendedRows clears the appended rows whose RAW values could not be written, so that they are not left
+// in the sheet without their RAW values. The returned error wraps the cause and tells whether the rows remain.
+func (s *GoogleSheetInsertStmt) clearAppendedRows(ctx context.Context, appendedRange sheets.A1Range, cause error) error {
+	if _, err := s.store.wrapper.Clear(ctx, s.store.spreadsheetID, []string{appendedRange.Original}); err != nil {
+		return fmt.Errorf(
+			"rows appended partially in %s without their RAW input values, clearing them failed (%v): %w",
+			appendedRange.Original,
+			err,
+			cause,
+		)
+	}
+	return fmt.Errorf("failed writing the RAW input values, the appended rows have been cleared: %w", cause)
+}
+
+// extractRawValues moves the values of the RAW input columns out of the converted rows.
+// The RAW values are written separately after the rows are appended, as the rowIdxCol formula must be evaluated.
+// The returned rows are indexed by the column index, with nil rows if there is no RAW input column.
+func (s *GoogleSheetInsertStmt) extractRawValues(convertedRows [][]interface{}) [][]interface{} {
+	if len(s.store.config.rawInputColumns()) == 0 {
+		return nil
+	}
+
+	rawRows := make([][]interface{}, len(convertedRows))
+	for rowIdx, row := range convertedRows {
+		rawRows[rowIdx] = make([]interface{}, len(row))
+		for col, colIdx := range s.store.colsMapping {
+			if !s.store.isRawInputColumn(col) {
+				continue
+			}
+			rawRows[rowIdx][colIdx.Idx] = row[colIdx.Idx]
+			row[colIdx.Idx] = nil
+		}
+	}
+	return rawRows
+}
+
+// generateRawUpdateRequests generates one request per RAW input column covering all the appended rows.
+func (s *GoogleSheetInsertStmt) generateRawUpdateRequests(
+	appendedRange sheets.A1Range,
+	rawRows [][]interface{},
+) ([]sheets.BatchUpdateRowsRequest, error) {
+	firstRow, err := getA1RowNumber(appendedRange.FromCell)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find the appended rows location: %w", err)
+	}
+	lastRow := firstRow + int64(len(rawRows)) - 1
+
+	requests := make([]sheets.BatchUpdateRowsRequest, 0)
+	for col, colIdx := range s.store.colsMapping {
+		if !s.store.isRawInputColumn(col) {
+			continue
+		}
+
+		values := make([][]interface{}, len(rawRows))
+		for rowIdx, row := range rawRows {
+			values[rowIdx] = []interface{}{row[colIdx.Idx]}
+		}
+
+		a1Range := fmt.Sprintf("%s%d:%s%d", colIdx.Name, firstRow, colIdx.Name, lastRow)
+		requests = append(requests, sheets.BatchUpdateRowsRequest{
+			A1Range: common.GetA1Range(s.store.sheetName, a1Range),
+			Values:  values,
+		})
+	}
+	return requests, nil
 }
 
 func newGoogleSheetInsertStmt(store *GoogleSheetRowStore, rows []interface{}) *GoogleSheetInsertStmt {
@@ -455,6 +945,7 @@ type GoogleSheetUpdateStmt struct {
 	store        *GoogleSheetRowStore
 	colToValue   map[string]interface{}
 	queryBuilder *queryBuilder
+	bulk         bulkOptions
 }
 
 // Where specifies the condition to choose which rows are affected.
@@ -466,37 +957,148 @@ func (s *GoogleSheetUpdateStmt) Where(condition string, args ...interface{}) *Go
 	return s
 }
 
+// ChunkSize defines the maximum number of matching rows updated by a single API call.
+// It works just like GoogleSheetInsertStmt.ChunkSize.
+func (s *GoogleSheetUpdateStmt) ChunkSize(rows int) *GoogleSheetUpdateStmt {
+	s.bulk.chunkSize = rows
+	return s
+}
+
+// Concurrency defines the maximum number of chunks updated concurrently (1 by default).
+func (s *GoogleSheetUpdateStmt) Concurrency(workers int) *GoogleSheetUpdateStmt {
+	s.bulk.concurrency = workers
+	return s
+}
+
+// OnProgress registers a callback called after every chunk is updated or fails.
+// It works just like GoogleSheetInsertStmt.OnProgress.
+func (s *GoogleSheetUpdateStmt) OnProgress(fn func(BulkProgress)) *GoogleSheetUpdateStmt {
+	s.bulk.onProgress = fn
+	return s
+}
+
 // Exec updates rows matching the condition with the new values for affected columns.
 //
-// There are 2 API calls behind the scene.
+// There are 2 API calls behind the scene: one for finding the matching rows and one per chunk of rows
+// (see ChunkSize) for updating them. Consecutive rows are updated using a single range per column.
+// If some chunks fail while the others are updated, a *BulkError describing the failed chunks is returned.
 func (s *GoogleSheetUpdateStmt) Exec(ctx context.Context) error {
+	return s.store.intercept(ctx, "Update", s.queryBuilder, s.exec)
+}
+
+type updateChunk struct {
+	requests    []sheets.BatchUpdateRowsRequest
+	rawRequests []sheets.BatchUpdateRowsRequest
+}
+
+func (s *GoogleSheetUpdateStmt) exec(ctx context.Context) error {
+	indices, err := s.findRows(ctx)
+	if err != nil {
+		return err
+	}
+
+	// All requests are generated before writing anything, so that an invalid value does not cause a partial update.
+	// Every matching row is written with the same values.
+	rowBytes := encodedSize(s.colToValue)
+	bounds := s.bulk.split(len(indices), func(int) int { return rowBytes })
+	chunks := make([]updateChunk, 0, len(bounds))
+	for _, bound := range bounds {
+		requests, rawRequests, err := s.generateAllRequests(indices[bound.start:bound.end])
+		if err != nil {
+			return err
+		}
+		chunks = append(chunks, updateChunk{requests: requests, rawRequests: rawRequests})
+	}
+	if s.store.config.DryRun {
+		return nil
+	}
+
+	return s.bulk.run(ctx, bounds, func(ctx context.Context, idx int) error {
+		return s.write(ctx, chunks[idx].requests, chunks[idx].rawRequests)
+	})
+}
+
+func (s *GoogleSheetUpdateStmt) write(
+	ctx context.Context,
+	requests []sheets.BatchUpdateRowsRequest,
+	rawRequests []sheets.BatchUpdateRowsRequest,
+) error {
+	if len(requests) > 0 {
+		if _, err := s.store.wrapper.BatchUpdateRows(ctx, s.store.spreadsheetID, requests); err != nil {
+			return err
+		}
+	}
+	if len(rawRequests) == 0 {
+		return nil
+	}
+
+	_, err := s.store.wrapper.BatchUpdateRowsWithOption(ctx, s.store.spreadsheetID, rawRequests, sheets.ValueInputRaw)
+	return err
+}
+
+// plan finds the rows matching the condition and generates the update requests without executing them.
+// The requests for the columns written as RAW values are returned separately.
+func (s *GoogleSheetUpdateStmt) plan(ctx context.Context) ([]sheets.BatchUpdateRowsRequest, []sheets.BatchUpdateRowsRequest, error) {
+	indices, err := s.findRows(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.generateAllRequests(indices)
+}
+
+// findRows returns the sorted row indices of the rows matching the condition.
+func (s *GoogleSheetUpdateStmt) findRows(ctx context.Context) ([]int64, error) {
 	if len(s.colToValue) == 0 {
-		return errors.New("empty colToValue, at least one column must be updated")
+		return nil, errors.New("empty colToValue, at least one column must be updated")
 	}
 
 	selectStmt, err := s.queryBuilder.Generate()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	indices, err := getRowIndices(ctx, s.store, selectStmt)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+	return indices, nil
+}
+
+// generateAllRequests generates the update requests of the given rows.
+// The requests for the columns written as RAW values are returned separately.
+func (s *GoogleSheetUpdateStmt) generateAllRequests(indices []int64) ([]sheets.BatchUpdateRowsRequest, []sheets.BatchUpdateRowsRequest, error) {
 	if len(indices) == 0 {
-		return nil
+		return nil, nil, nil
 	}
 
 	requests, err := s.generateBatchUpdateRequests(indices)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-
-	_, err = s.store.wrapper.BatchUpdateRows(ctx, s.store.spreadsheetID, requests)
-	return err
+	rawRequests, err := s.generateRawBatchUpdateRequests(indices)
+	if err != nil {
+		return nil, nil, err
+	}
+	return requests, rawRequests, nil
 }
 
+// generateBatchUpdateRequests generates the update requests for columns written as user entered values.
 func (s *GoogleSheetUpdateStmt) generateBatchUpdateRequests(rowIndices []int64) ([]sheets.BatchUpdateRowsRequest, error) {
+	return s.generateRequests(rowIndices, func(col string) bool {
+		return !s.store.isRawInputColumn(col)
+	})
+}
+
+// generateRawBatchUpdateRequests generates the update requests for columns written as RAW values.
+func (s *GoogleSheetUpdateStmt) generateRawBatchUpdateRequests(rowIndices []int64) ([]sheets.BatchUpdateRowsRequest, error) {
+	return s.generateRequests(rowIndices, s.store.isRawInputColumn)
+}
+
+func (s *GoogleSheetUpdateStmt) generateRequests(
+	rowIndices []int64,
+	includeCol func(col string) bool,
+) ([]sheets.BatchUpdateRowsRequest, error) {
 	requests := make([]sheets.BatchUpdateRowsRequest, 0)
 
 	for col, value := range s.colToValue {
@@ -504,20 +1106,29 @@ func (s *GoogleSheetUpdateStmt) generateBatchUpdateRequests(rowIndices []int64)
 		if !ok {
 			return nil, fmt.Errorf("failed to update, unknown column name provided: %s", col)
 		}
+		if !includeCol(col) {
+			continue
+		}
 
-		escapedValue, err := escapeValue(col, value, s.store.colsWithFormula)
+		encodedValue, err := s.store.encodeValue(col, value)
 		if err != nil {
 			return nil, err
 		}
-		if err = common.CheckIEEE754SafeInteger(escapedValue); err != nil {
-			return nil, err
-		}
 
-		for _, rowIdx := range rowIndices {
-			a1Range := colIdx.Name + strconv.FormatInt(rowIdx, 10)
+		for _, rows := range consecutiveRows(rowIndices) {
+			first, last := rows[0], rows[len(rows)-1]
+			a1Range := colIdx.Name + strconv.FormatInt(first, 10)
+			if last != first {
+				a1Range += ":" + colIdx.Name + strconv.FormatInt(last, 10)
+			}
+
+			values := make([][]interface{}, len(rows))
+			for i := range values {
+				values[i] = []interface{}{encodedValue}
+			}
 			requests = append(requests, sheets.BatchUpdateRowsRequest{
 				A1Range: common.GetA1Range(s.store.sheetName, a1Range),
-				Values:  [][]interface{}{{escapedValue}},
+				Values:  values,
 			})
 		}
 	}
@@ -525,11 +1136,24 @@ func (s *GoogleSheetUpdateStmt) generateBatchUpdateRequests(rowIndices []int64)
 	return requests, nil
 }
 
+// consecutiveRows splits the row indices into runs of consecutive rows, e.g. [2 3 4 7] into [[2 3 4] [7]].
+func consecutiveRows(rowIndices []int64) [][]int64 {
+	runs := make([][]int64, 0)
+	start := 0
+	for i := 1; i <= len(rowIndices); i++ {
+		if i == len(rowIndices) || rowIndices[i] != rowIndices[i-1]+1 {
+			runs = append(runs, rowIndices[start:i])
+			start = i
+		}
+	}
+	return runs
+}
+
 func newGoogleSheetUpdateStmt(store *GoogleSheetRowStore, colToValue map[string]interface{}) *GoogleSheetUpdateStmt {
 	return &GoogleSheetUpdateStmt{
 		store:        store,
 		colToValue:   colToValue,
-		queryBuilder: newQueryBuilder(store.colsMapping.NameMap(), ridWhereClauseInterceptor, []string{rowIdxCol}),
+		queryBuilder: store.newQueryBuilder([]string{rowIdxCol}),
 	}
 }
 
@@ -552,27 +1176,43 @@ func (s *GoogleSheetDeleteStmt) Where(condition string, args ...interface{}) *Go
 //
 // There are 2 API calls behind the scene.
 func (s *GoogleSheetDeleteStmt) Exec(ctx context.Context) error {
-	selectStmt, err := s.queryBuilder.Generate()
+	return s.store.intercept(ctx, "Delete", s.queryBuilder, s.exec)
+}
+
+func (s *GoogleSheetDeleteStmt) exec(ctx context.Context) error {
+	ranges, err := s.plan(ctx)
 	if err != nil {
 		return err
 	}
+	if len(ranges) == 0 || s.store.config.DryRun {
+		return nil
+	}
+
+	_, err = s.store.wrapper.Clear(ctx, s.store.spreadsheetID, ranges)
+	return err
+}
+
+// plan finds the rows matching the condition and returns their A1 notation ranges to be cleared.
+func (s *GoogleSheetDeleteStmt) plan(ctx context.Context) ([]string, error) {
+	selectStmt, err := s.queryBuilder.Generate()
+	if err != nil {
+		return nil, err
+	}
 
 	indices, err := getRowIndices(ctx, s.store, selectStmt)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if len(indices) == 0 {
-		return nil
+		return nil, nil
 	}
-
-	_, err = s.store.wrapper.Clear(ctx, s.store.spreadsheetID, generateRowA1Ranges(s.store.sheetName, indices))
-	return err
+	return generateRowA1Ranges(s.store.sheetName, indices), nil
 }
 
 func newGoogleSheetDeleteStmt(store *GoogleSheetRowStore) *GoogleSheetDeleteStmt {
 	return &GoogleSheetDeleteStmt{
 		store:        store,
-		queryBuilder: newQueryBuilder(store.colsMapping.NameMap(), ridWhereClauseInterceptor, []string{rowIdxCol}),
+		queryBuilder: store.newQueryBuilder([]string{rowIdxCol}),
 	}
 }
 
@@ -595,12 +1235,21 @@ func (s *GoogleSheetCountStmt) Where(condition string, args ...interface{}) *Goo
 //
 // There is only 1 API call behind the scene.
 func (s *GoogleSheetCountStmt) Exec(ctx context.Context) (uint64, error) {
+	var count uint64
+	err := s.store.intercept(ctx, "Count", s.queryBuilder, func(ctx context.Context) (err error) {
+		count, err = s.exec(ctx)
+		return err
+	})
+	return count, err
+}
+
+func (s *GoogleSheetCountStmt) exec(ctx context.Context) (uint64, error) {
 	selectStmt, err := s.queryBuilder.Generate()
 	if err != nil {
 		return 0, err
 	}
 
-	result, err := s.store.wrapper.QueryRows(ctx, s.store.spreadsheetID, s.store.sheetName, selectStmt, true)
+	result, err := s.store.cachedQueryRows(ctx, selectStmt)
 	if err != nil {
 		return 0, err
 	}
@@ -621,7 +1270,7 @@ func newGoogleSheetCountStmt(store *GoogleSheetRowStore) *GoogleSheetCountStmt {
 	countClause := fmt.Sprintf("COUNT(%s)", rowIdxCol)
 	return &GoogleSheetCountStmt{
 		store:        store,
-		queryBuilder: newQueryBuilder(store.colsMapping.NameMap(), ridWhereClauseInterceptor, []string{countClause}),
+		queryBuilder: store.newQueryBuilder([]string{countClause}),
 	}
 }
 
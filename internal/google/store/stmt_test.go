@@ -3,12 +3,21 @@ package store
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/FreeLeh/GoFreeDB/internal/common"
 	"github.com/FreeLeh/GoFreeDB/internal/models"
+	"math"
+	"math/big"
+	"net/http"
 	"testing"
 
+	"github.com/FreeLeh/GoFreeDB/google/auth"
+	"github.com/FreeLeh/GoFreeDB/internal/google/fixtures"
+	"github.com/FreeLeh/GoFreeDB/internal/google/memory"
 	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/googleapi"
+	"gopkg.in/h2non/gock.v1"
 )
 
 type person struct {
@@ -259,6 +268,19 @@ func TestSelectStmt_Exec(t *testing.T) {
 		assert.NotNil(t, err)
 	})
 
+	t.Run("has_typed_query_error", func(t *testing.T) {
+		wrapper := &sheets.MockWrapper{QueryRowsError: fmt.Errorf("%w: NO_COLUMN: C", sheets.ErrInvalidQuery)}
+		store := &GoogleSheetRowStore{
+			wrapper:     wrapper,
+			colsMapping: map[string]common.ColIdx{rowIdxCol: {"A", 0}, "col1": {"B", 1}, "col2": {"C", 2}},
+		}
+		var out []map[string]interface{}
+		stmt := newGoogleSheetSelectStmt(store, &out, []string{"col1", "col2"})
+
+		err := stmt.Exec(context.Background())
+		assert.ErrorIs(t, err, sheets.ErrInvalidQuery)
+	})
+
 	t.Run("successful", func(t *testing.T) {
 		wrapper := &sheets.MockWrapper{QueryRowsResult: sheets.QueryRowsResult{Rows: [][]interface{}{
 			{10, "17-01-2001"},
@@ -418,30 +440,30 @@ func TestGoogleSheetUpdateStmt_generateBatchUpdateRequests(t *testing.T) {
 			},
 		)
 
-		requests, err := stmt.generateBatchUpdateRequests([]int64{1, 2})
+		requests, err := stmt.generateBatchUpdateRequests([]int64{1, 2, 4})
 		expected := []sheets.BatchUpdateRowsRequest{
 			{
-				A1Range: common.GetA1Range(store.sheetName, "B1"),
-				Values:  [][]interface{}{{"name1"}},
+				A1Range: common.GetA1Range(store.sheetName, "B1:B2"),
+				Values:  [][]interface{}{{"name1"}, {"name1"}},
 			},
 			{
-				A1Range: common.GetA1Range(store.sheetName, "B2"),
+				A1Range: common.GetA1Range(store.sheetName, "B4"),
 				Values:  [][]interface{}{{"name1"}},
 			},
 			{
-				A1Range: common.GetA1Range(store.sheetName, "C1"),
-				Values:  [][]interface{}{{int64(100)}},
+				A1Range: common.GetA1Range(store.sheetName, "C1:C2"),
+				Values:  [][]interface{}{{int64(100)}, {int64(100)}},
 			},
 			{
-				A1Range: common.GetA1Range(store.sheetName, "C2"),
+				A1Range: common.GetA1Range(store.sheetName, "C4"),
 				Values:  [][]interface{}{{int64(100)}},
 			},
 			{
-				A1Range: common.GetA1Range(store.sheetName, "D1"),
-				Values:  [][]interface{}{{"'hello"}},
+				A1Range: common.GetA1Range(store.sheetName, "D1:D2"),
+				Values:  [][]interface{}{{"'hello"}, {"'hello"}},
 			},
 			{
-				A1Range: common.GetA1Range(store.sheetName, "D2"),
+				A1Range: common.GetA1Range(store.sheetName, "D4"),
 				Values:  [][]interface{}{{"'hello"}},
 			},
 		}
@@ -459,20 +481,12 @@ func TestGoogleSheetUpdateStmt_generateBatchUpdateRequests(t *testing.T) {
 		requests, err := stmt.generateBatchUpdateRequests([]int64{1, 2})
 		expected := []sheets.BatchUpdateRowsRequest{
 			{
-				A1Range: common.GetA1Range(store.sheetName, "B1"),
-				Values:  [][]interface{}{{"name1"}},
+				A1Range: common.GetA1Range(store.sheetName, "B1:B2"),
+				Values:  [][]interface{}{{"name1"}, {"name1"}},
 			},
 			{
-				A1Range: common.GetA1Range(store.sheetName, "B2"),
-				Values:  [][]interface{}{{"name1"}},
-			},
-			{
-				A1Range: common.GetA1Range(store.sheetName, "C1"),
-				Values:  [][]interface{}{{int64(9007199254740992)}},
-			},
-			{
-				A1Range: common.GetA1Range(store.sheetName, "C2"),
-				Values:  [][]interface{}{{int64(9007199254740992)}},
+				A1Range: common.GetA1Range(store.sheetName, "C1:C2"),
+				Values:  [][]interface{}{{int64(9007199254740992)}, {int64(9007199254740992)}},
 			},
 		}
 
@@ -518,3 +532,593 @@ func TestEscapeValue(t *testing.T) {
 		assert.Equal(t, "123", value)
 	})
 }
+
+func TestGoogleSheetRowStore_LosslessNumbers(t *testing.T) {
+	type account struct {
+		ID      uint64   `db:"id"`
+		Balance *big.Rat `db:"balance"`
+	}
+
+	newStore := func(wrapper SheetsWrapper) *GoogleSheetRowStore {
+		return &GoogleSheetRowStore{
+			wrapper:   wrapper,
+			sheetName: "sheet1",
+			colsMapping: map[string]common.ColIdx{
+				rowIdxCol: {Name: "A", Idx: 0},
+				"id":      {Name: "B", Idx: 1},
+				"balance": {Name: "C", Idx: 2},
+			},
+			colsWithFormula: common.NewSet([]string{}),
+			config: GoogleSheetRowStoreConfig{
+				Columns:         []string{rowIdxCol, "id", "balance"},
+				LosslessNumbers: true,
+			},
+		}
+	}
+
+	t.Run("insert", func(t *testing.T) {
+		stmt := newGoogleSheetInsertStmt(newStore(&sheets.MockWrapper{}), nil)
+
+		result, err := stmt.convertRowToSlice(account{ID: 9007199254740993, Balance: big.NewRat(1999, 100)})
+		assert.Nil(t, err)
+		assert.Equal(t, []interface{}{rowIdxFormula, "'9007199254740993", "'19.99"}, result)
+
+		result, err = stmt.convertRowToSlice(account{ID: 10, Balance: big.NewRat(3, 2)})
+		assert.Nil(t, err)
+		assert.Equal(t, []interface{}{rowIdxFormula, int64(10), 1.5}, result)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		stmt := newGoogleSheetUpdateStmt(newStore(&sheets.MockWrapper{}), map[string]interface{}{
+			"id": uint64(9007199254740993),
+		})
+
+		requests, err := stmt.generateBatchUpdateRequests([]int64{2})
+		assert.Nil(t, err)
+		assert.Equal(t, []sheets.BatchUpdateRowsRequest{
+			{A1Range: "sheet1!B2", Values: [][]interface{}{{"'9007199254740993"}}},
+		}, requests)
+	})
+
+	t.Run("where", func(t *testing.T) {
+		stmt := newGoogleSheetSelectStmt(newStore(&sheets.MockWrapper{}), nil, []string{"id"}).
+			Where("id = ? OR id = ?", uint64(9007199254740993), 10)
+
+		result, err := stmt.queryBuilder.Generate()
+		assert.Nil(t, err)
+		assert.Equal(t, "select B where A is not null AND B = \"9007199254740993\" OR B = 10 ", result)
+	})
+
+	t.Run("select", func(t *testing.T) {
+		wrapper := &sheets.MockWrapper{QueryRowsResult: sheets.QueryRowsResult{Rows: [][]interface{}{
+			{"9007199254740993", "19.99"},
+			{float64(10), 1.5},
+		}}}
+
+		var out []account
+		err := newGoogleSheetSelectStmt(newStore(wrapper), &out, []string{"id", "balance"}).Exec(context.Background())
+		assert.Nil(t, err)
+		assert.Len(t, out, 2)
+		assert.Equal(t, uint64(9007199254740993), out[0].ID)
+		assert.Equal(t, 0, big.NewRat(1999, 100).Cmp(out[0].Balance))
+		assert.Equal(t, uint64(10), out[1].ID)
+		assert.Equal(t, 0, big.NewRat(3, 2).Cmp(out[1].Balance))
+	})
+}
+
+func TestGoogleSheetRowStore_StringNumbers(t *testing.T) {
+	type account struct {
+		ID   uint64 `db:"id"`
+		Name string `db:"name"`
+	}
+
+	config := GoogleSheetRowStoreConfig{
+		Columns:                  []string{rowIdxCol, "id", "name"},
+		LosslessNumbers:          true,
+		ColumnsWithStringNumbers: []string{"id"},
+	}
+	store := &GoogleSheetRowStore{
+		wrapper:   &sheets.MockWrapper{},
+		sheetName: "sheet1",
+		colsMapping: map[string]common.ColIdx{
+			rowIdxCol: {Name: "A", Idx: 0},
+			"id":      {Name: "B", Idx: 1},
+			"name":    {Name: "C", Idx: 2},
+		},
+		colsWithFormula: common.NewSet([]string{}),
+		colsWithStrNum:  common.NewSet(config.ColumnsWithStringNumbers),
+		config:          config,
+	}
+
+	t.Run("insert", func(t *testing.T) {
+		stmt := newGoogleSheetInsertStmt(store, nil)
+
+		result, err := stmt.convertRowToSlice(account{ID: 1, Name: "1"})
+		assert.Nil(t, err)
+		assert.Equal(t, []interface{}{rowIdxFormula, "'1", "'1"}, result)
+
+		result, err = stmt.convertRowToSlice(account{ID: math.MaxUint64, Name: "max"})
+		assert.Nil(t, err)
+		assert.Equal(t, []interface{}{rowIdxFormula, "'18446744073709551615", "'max"}, result)
+	})
+
+	t.Run("where", func(t *testing.T) {
+		stmt := newGoogleSheetSelectStmt(store, nil, []string{"id"}).
+			Where("id = ? OR id != ? OR name = ? OR id > ?", uint64(math.MaxUint64), 1, 2, 3)
+
+		result, err := stmt.queryBuilder.Generate()
+		assert.Nil(t, err)
+		assert.Equal(t, "select B where A is not null AND B = \"18446744073709551615\" OR B != \"1\" OR C = 2 OR B > 3 ", result)
+	})
+
+	t.Run("lookup_in_mixed_column", func(t *testing.T) {
+		ctx := context.Background()
+		db, err := NewGoogleSheetRowStoreWithContext(ctx, nil, "spreadsheet", "accounts", GoogleSheetRowStoreConfig{
+			Columns:                  []string{"id", "name"},
+			LosslessNumbers:          true,
+			ColumnsWithStringNumbers: []string{"id"},
+			MemoryBackend:            memory.NewBackend(),
+		})
+		assert.Nil(t, err)
+		assert.Nil(t, db.Insert(account{ID: 1, Name: "small"}, account{ID: math.MaxUint64, Name: "large"}).Exec(ctx))
+
+		for _, expected := range []account{{ID: 1, Name: "small"}, {ID: math.MaxUint64, Name: "large"}} {
+			var out []account
+			assert.Nil(t, db.Select(&out).Where("id = ?", expected.ID).Exec(ctx))
+			assert.Equal(t, []account{expected}, out)
+		}
+	})
+}
+
+func TestGoogleSheetRowStore_RawInput(t *testing.T) {
+	config := GoogleSheetRowStoreConfig{
+		Columns:            []string{rowIdxCol, "name", "age", "dob"},
+		ColumnsWithFormula: []string{"dob"},
+		ValueInputOption:   sheets.ValueInputRaw,
+	}
+	store := &GoogleSheetRowStore{
+		wrapper:   &sheets.MockWrapper{},
+		sheetName: "sheet1",
+		colsMapping: map[string]common.ColIdx{
+			rowIdxCol: {Name: "A", Idx: 0},
+			"name":    {Name: "B", Idx: 1},
+			"age":     {Name: "C", Idx: 2},
+			"dob":     {Name: "D", Idx: 3},
+		},
+		colsWithFormula: common.NewSet(config.ColumnsWithFormula),
+		colsWithRaw:     common.NewSet(config.rawInputColumns()),
+		config:          config,
+	}
+
+	t.Run("raw_input_columns", func(t *testing.T) {
+		assert.Equal(t, []string{"name", "age"}, config.rawInputColumns())
+	})
+
+	t.Run("insert", func(t *testing.T) {
+		stmt := newGoogleSheetInsertStmt(store, nil)
+
+		row, err := stmt.convertRowToSlice(person{Name: "=1+1", Age: 10, DOB: "=TODAY()"})
+		assert.Nil(t, err)
+		assert.Equal(t, []interface{}{rowIdxFormula, "=1+1", int64(10), "=TODAY()"}, row)
+
+		rows := [][]interface{}{row, {rowIdxFormula, "name2", int64(11), "=TODAY()"}}
+		rawRows := stmt.extractRawValues(rows)
+		assert.Equal(t, [][]interface{}{
+			{rowIdxFormula, nil, nil, "=TODAY()"},
+			{rowIdxFormula, nil, nil, "=TODAY()"},
+		}, rows)
+
+		requests, err := stmt.generateRawUpdateRequests(sheets.NewA1Range("sheet1!A5:D6"), rawRows)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []sheets.BatchUpdateRowsRequest{
+			{A1Range: "sheet1!B5:B6", Values: [][]interface{}{{"=1+1"}, {"name2"}}},
+			{A1Range: "sheet1!C5:C6", Values: [][]interface{}{{int64(10)}, {int64(11)}}},
+		}, requests)
+
+		_, err = stmt.generateRawUpdateRequests(sheets.NewA1Range("sheet1!A:D"), rawRows)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("insert_without_raw_columns", func(t *testing.T) {
+		stmt := newGoogleSheetInsertStmt(&GoogleSheetRowStore{
+			config: GoogleSheetRowStoreConfig{Columns: []string{rowIdxCol, "name"}},
+		}, nil)
+		assert.Nil(t, stmt.extractRawValues([][]interface{}{{rowIdxFormula, "'name"}}))
+	})
+
+	t.Run("update", func(t *testing.T) {
+		stmt := newGoogleSheetUpdateStmt(store, map[string]interface{}{
+			"name": "2020-01-01",
+			"dob":  "=TODAY()",
+		})
+
+		requests, err := stmt.generateBatchUpdateRequests([]int64{2})
+		assert.Nil(t, err)
+		assert.Equal(t, []sheets.BatchUpdateRowsRequest{
+			{A1Range: "sheet1!D2", Values: [][]interface{}{{"=TODAY()"}}},
+		}, requests)
+
+		requests, err = stmt.generateRawBatchUpdateRequests([]int64{2})
+		assert.Nil(t, err)
+		assert.Equal(t, []sheets.BatchUpdateRowsRequest{
+			{A1Range: "sheet1!B2", Values: [][]interface{}{{"2020-01-01"}}},
+		}, requests)
+	})
+}
+
+func TestGoogleSheetInsertStmt_RawInputFailure(t *testing.T) {
+	rawErr := errors.New("raw error")
+	newStore := func(wrapper *sheets.MockWrapper) *GoogleSheetRowStore {
+		config := GoogleSheetRowStoreConfig{
+			Columns:             []string{rowIdxCol, "name", "age"},
+			ColumnsWithRawInput: []string{"name"},
+		}
+		return &GoogleSheetRowStore{
+			wrapper:   wrapper,
+			sheetName: "sheet1",
+			colsMapping: map[string]common.ColIdx{
+				rowIdxCol: {Name: "A", Idx: 0},
+				"name":    {Name: "B", Idx: 1},
+				"age":     {Name: "C", Idx: 2},
+			},
+			colsWithFormula: common.NewSet([]string{}),
+			colsWithRaw:     common.NewSet(config.rawInputColumns()),
+			config:          config,
+		}
+	}
+
+	t.Run("appended_rows_cleared", func(t *testing.T) {
+		store := newStore(&sheets.MockWrapper{
+			OverwriteRowsResult:  sheets.InsertRowsResult{UpdatedRange: sheets.NewA1Range("sheet1!A5:C5")},
+			BatchUpdateRowsError: rawErr,
+		})
+		err := store.Insert(person{Name: "name1", Age: 10}).Exec(context.Background())
+		assert.ErrorIs(t, err, rawErr)
+		assert.Contains(t, err.Error(), "the appended rows have been cleared")
+	})
+
+	t.Run("appended_rows_remaining", func(t *testing.T) {
+		store := newStore(&sheets.MockWrapper{
+			OverwriteRowsResult:  sheets.InsertRowsResult{UpdatedRange: sheets.NewA1Range("sheet1!A5:C5")},
+			BatchUpdateRowsError: rawErr,
+			ClearError:           errors.New("clear error"),
+		})
+		err := store.Insert(person{Name: "name1", Age: 10}).Exec(context.Background())
+		assert.ErrorIs(t, err, rawErr)
+		assert.Contains(t, err.Error(), "rows appended partially in sheet1!A5:C5")
+	})
+}
+
+func TestSelectStmt_Exec_OutputTypes(t *testing.T) {
+	newStore := func(rows [][]interface{}) *GoogleSheetRowStore {
+		return &GoogleSheetRowStore{
+			wrapper:     &sheets.MockWrapper{QueryRowsResult: sheets.QueryRowsResult{Rows: rows}},
+			sheetName:   "sheet1",
+			colsMapping: map[string]common.ColIdx{rowIdxCol: {Name: "A", Idx: 0}, "name": {Name: "B", Idx: 1}, "age": {Name: "C", Idx: 2}},
+			config:      GoogleSheetRowStoreConfig{Columns: []string{"name", "age"}},
+		}
+	}
+
+	t.Run("maps", func(t *testing.T) {
+		store := newStore([][]interface{}{{"name1", float64(10)}, {"name2", float64(11)}})
+
+		var out []map[string]interface{}
+		err := newGoogleSheetSelectStmt(store, &out, []string{"name", "age"}).Exec(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, []map[string]interface{}{
+			{"name": "name1", "age": float64(10)},
+			{"name": "name2", "age": float64(11)},
+		}, out)
+	})
+
+	t.Run("scalars", func(t *testing.T) {
+		store := newStore([][]interface{}{{float64(10)}, {float64(11)}})
+
+		var ages []int
+		err := newGoogleSheetSelectStmt(store, &ages, []string{"age"}).Exec(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, []int{10, 11}, ages)
+
+		store = newStore([][]interface{}{{"name1"}, {"name2"}})
+
+		var names []string
+		err = newGoogleSheetSelectStmt(store, &names, []string{"name"}).Exec(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"name1", "name2"}, names)
+	})
+
+	t.Run("scalars_multiple_columns", func(t *testing.T) {
+		store := newStore([][]interface{}{{"name1", float64(10)}})
+
+		var names []string
+		err := newGoogleSheetSelectStmt(store, &names, []string{"name", "age"}).Exec(context.Background())
+		assert.NotNil(t, err)
+	})
+
+	t.Run("raw_rows", func(t *testing.T) {
+		store := newStore([][]interface{}{{"name1", float64(10)}, {"name2", nil}})
+
+		var out [][]interface{}
+		err := newGoogleSheetSelectStmt(store, &out, []string{"name", "age"}).Exec(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, [][]interface{}{{"name1", float64(10)}, {"name2", nil}}, out)
+	})
+
+	t.Run("select_one", func(t *testing.T) {
+		store := newStore([][]interface{}{{"name1", float64(10)}})
+
+		var out person
+		stmt := store.SelectOne(&out, "name", "age").Where("age = ?", 10)
+		assert.Nil(t, stmt.Exec(context.Background()))
+		assert.Equal(t, person{Name: "name1", Age: 10}, out)
+
+		query, err := stmt.queryBuilder.Generate()
+		assert.Nil(t, err)
+		assert.Equal(t, "select B, C where A is not null AND C = 10  limit 1", query)
+	})
+
+	t.Run("select_one_scalar", func(t *testing.T) {
+		store := newStore([][]interface{}{{float64(10)}})
+
+		var age int64
+		assert.Nil(t, store.SelectOne(&age, "age").Exec(context.Background()))
+		assert.Equal(t, int64(10), age)
+	})
+
+	t.Run("select_one_not_found", func(t *testing.T) {
+		store := newStore([][]interface{}{})
+
+		var out person
+		err := store.SelectOne(&out, "name", "age").Exec(context.Background())
+		assert.ErrorIs(t, err, models.ErrRowNotFound)
+	})
+
+	t.Run("select_one_invalid_output", func(t *testing.T) {
+		store := newStore([][]interface{}{})
+
+		var out person
+		assert.NotNil(t, store.SelectOne(out, "name").Exec(context.Background()))
+		assert.NotNil(t, store.SelectOne(nil, "name").Exec(context.Background()))
+		assert.NotNil(t, store.SelectOne((*person)(nil), "name").Exec(context.Background()))
+	})
+
+	t.Run("first", func(t *testing.T) {
+		store := newStore([][]interface{}{{"name1", float64(10)}})
+
+		var out person
+		stmt := store.First(&out, "name", "age")
+		assert.Nil(t, stmt.Exec(context.Background()))
+		assert.Equal(t, person{Name: "name1", Age: 10}, out)
+
+		query, err := stmt.queryBuilder.Generate()
+		assert.Nil(t, err)
+		assert.Equal(t, "select B, C where A is not null order by A ASC limit 1", query)
+
+		stmt = store.First(&out, "name", "age").OrderBy([]models.ColumnOrderBy{{Column: "age", OrderBy: models.OrderByDesc}})
+		query, err = stmt.queryBuilder.Generate()
+		assert.Nil(t, err)
+		assert.Equal(t, "select B, C where A is not null order by C DESC", query)
+	})
+}
+
+func TestSelectStmt_ComputedColumns(t *testing.T) {
+	newStore := func(rows [][]interface{}) *GoogleSheetRowStore {
+		return &GoogleSheetRowStore{
+			wrapper:   &sheets.MockWrapper{QueryRowsResult: sheets.QueryRowsResult{Rows: rows}},
+			sheetName: "sheet1",
+			colsMapping: map[string]common.ColIdx{
+				rowIdxCol: {Name: "A", Idx: 0},
+				"name":    {Name: "B", Idx: 1},
+				"price":   {Name: "C", Idx: 2},
+				"qty":     {Name: "D", Idx: 3},
+			},
+			config: GoogleSheetRowStoreConfig{Columns: []string{rowIdxCol, "name", "price", "qty"}},
+		}
+	}
+
+	type item struct {
+		Name  string  `db:"name_upper"`
+		Total float64 `db:"total"`
+	}
+
+	t.Run("parse_select_column", func(t *testing.T) {
+		expr, alias := parseSelectColumn("upper(name) AS name_upper")
+		assert.Equal(t, "upper(name)", expr)
+		assert.Equal(t, "name_upper", alias)
+
+		expr, alias = parseSelectColumn(" price * qty as total ")
+		assert.Equal(t, "price * qty", expr)
+		assert.Equal(t, "total", alias)
+
+		expr, alias = parseSelectColumn("name")
+		assert.Equal(t, "name", expr)
+		assert.Equal(t, "", alias)
+
+		expr, alias = parseSelectColumn("basename")
+		assert.Equal(t, "basename", expr)
+		assert.Equal(t, "", alias)
+	})
+
+	t.Run("aliases", func(t *testing.T) {
+		store := newStore([][]interface{}{{"NAME1", float64(20)}})
+
+		var out []item
+		stmt := newGoogleSheetSelectStmt(store, &out, []string{"upper(name) AS name_upper", "price * qty AS total"}).
+			OrderBy([]models.ColumnOrderBy{{Column: "total", OrderBy: models.OrderByDesc}})
+
+		query, err := stmt.queryBuilder.Generate()
+		assert.Nil(t, err)
+		assert.Equal(t, "select upper(B), C * D where A is not null order by C * D DESC label upper(B) 'name_upper', C * D 'total'", query)
+
+		assert.Nil(t, stmt.Exec(context.Background()))
+		assert.Equal(t, []item{{Name: "NAME1", Total: 20}}, out)
+	})
+
+	t.Run("distinct", func(t *testing.T) {
+		store := newStore([][]interface{}{{"name1", float64(2)}, {"name2", float64(1)}})
+
+		var out []string
+		stmt := newGoogleSheetSelectStmt(store, &out, []string{"name"}).Distinct()
+
+		query, err := stmt.queryBuilder.Generate()
+		assert.Nil(t, err)
+		assert.Equal(t, "select B, COUNT(A) where A is not null group by B", query)
+
+		assert.Nil(t, stmt.Exec(context.Background()))
+		assert.Equal(t, []string{"name1", "name2"}, out)
+
+		_, err = stmt.Rows(context.Background())
+		assert.NotNil(t, err)
+	})
+
+	t.Run("invalid_alias", func(t *testing.T) {
+		stmt := newGoogleSheetSelectStmt(newStore(nil), nil, []string{"name"})
+		stmt.queryBuilder.labels = []string{"it's"}
+
+		_, err := stmt.queryBuilder.Generate()
+		assert.NotNil(t, err)
+	})
+}
+
+func TestSelectStmt_FormatOptionsMetadata(t *testing.T) {
+	store := &GoogleSheetRowStore{
+		wrapper: &sheets.MockWrapper{QueryRowsResult: sheets.QueryRowsResult{
+			Rows: [][]interface{}{{"name1", "2020-01-01"}},
+			Columns: []sheets.QueryRowsColumn{
+				{ID: "B", Label: "name", Type: "string"},
+				{ID: "C", Label: "birthday", Type: "date", Pattern: "yyyy-mm-dd"},
+			},
+		}},
+		sheetName:   "sheet1",
+		colsMapping: map[string]common.ColIdx{rowIdxCol: {Name: "A", Idx: 0}, "name": {Name: "B", Idx: 1}, "dob": {Name: "C", Idx: 2}},
+		config:      GoogleSheetRowStoreConfig{Columns: []string{rowIdxCol, "name", "dob"}},
+	}
+
+	t.Run("successful", func(t *testing.T) {
+		var out []map[string]interface{}
+		var metadata []models.ColumnMetadata
+
+		stmt := newGoogleSheetSelectStmt(store, &out, []string{"name", "dob AS birthday"}).
+			Format("birthday", "yyyy-mm-dd").
+			Options(models.QueryOptionNoValues).
+			ColumnMetadata(&metadata)
+
+		query, err := stmt.queryBuilder.Generate()
+		assert.Nil(t, err)
+		assert.Equal(t, "select B, C where A is not null label C 'birthday' format C 'yyyy-mm-dd' options no_values", query)
+
+		assert.Nil(t, stmt.Exec(context.Background()))
+		assert.Equal(t, []map[string]interface{}{{"name": "name1", "birthday": "2020-01-01"}}, out)
+		assert.Equal(t, []models.ColumnMetadata{
+			{Name: "name", Label: "name", Type: "string"},
+			{Name: "birthday", Label: "birthday", Type: "date", Pattern: "yyyy-mm-dd"},
+		}, metadata)
+	})
+
+	t.Run("invalid_format", func(t *testing.T) {
+		stmt := newGoogleSheetSelectStmt(store, nil, []string{"name"}).Format("name", "'")
+		_, err := stmt.queryBuilder.Generate()
+		assert.NotNil(t, err)
+	})
+
+	t.Run("invalid_option", func(t *testing.T) {
+		stmt := newGoogleSheetSelectStmt(store, nil, []string{"name"}).Options("something")
+		_, err := stmt.queryBuilder.Generate()
+		assert.NotNil(t, err)
+	})
+}
+
+func TestGoogleSheetRowStore_TypedErrors(t *testing.T) {
+	newStore := func(wrapper *sheets.MockWrapper) *GoogleSheetRowStore {
+		return &GoogleSheetRowStore{
+			wrapper:   wrapper,
+			sheetName: "sheet1",
+			colsMapping: map[string]common.ColIdx{
+				rowIdxCol: {Name: "A", Idx: 0},
+				"name":    {Name: "B", Idx: 1},
+			},
+			colsWithFormula: common.NewSet([]string{}),
+			colsWithRaw:     common.NewSet([]string{}),
+			config:          GoogleSheetRowStoreConfig{Columns: []string{rowIdxCol, "name"}},
+		}
+	}
+	quotaErr := fmt.Errorf("%w: read requests", sheets.ErrQuotaExceeded)
+	permissionErr := fmt.Errorf("%w: caller does not have permission", sheets.ErrPermissionDenied)
+
+	t.Run("insert", func(t *testing.T) {
+		store := newStore(&sheets.MockWrapper{OverwriteRowsError: permissionErr})
+		err := store.Insert(person{Name: "name1"}).Exec(context.Background())
+		assert.ErrorIs(t, err, sheets.ErrPermissionDenied)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		store := newStore(&sheets.MockWrapper{QueryRowsError: quotaErr})
+		err := store.Update(map[string]interface{}{"name": "name1"}).Exec(context.Background())
+		assert.ErrorIs(t, err, sheets.ErrQuotaExceeded)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		store := newStore(&sheets.MockWrapper{
+			QueryRowsResult: sheets.QueryRowsResult{Rows: [][]interface{}{{float64(2)}}},
+			ClearError:      permissionErr,
+		})
+		err := store.Delete().Exec(context.Background())
+		assert.ErrorIs(t, err, sheets.ErrPermissionDenied)
+	})
+
+	t.Run("count", func(t *testing.T) {
+		store := newStore(&sheets.MockWrapper{QueryRowsError: quotaErr})
+		_, err := store.Count().Exec(context.Background())
+		assert.ErrorIs(t, err, sheets.ErrQuotaExceeded)
+	})
+}
+
+func TestGoogleSheetRowStore_APIErrors(t *testing.T) {
+	googleAuth, err := auth.NewServiceFromFile(fixtures.PathToFixture("service_account.json"), []string{}, auth.ServiceConfig{})
+	assert.Nil(t, err)
+
+	store, err := NewGoogleSheetRowStoreWithContext(
+		context.Background(),
+		googleAuth,
+		"spreadsheet",
+		"sheet1",
+		GoogleSheetRowStoreConfig{Columns: []string{"name", "age", "dob"}, SkipProvisioning: true},
+	)
+	assert.Nil(t, err)
+
+	gock.InterceptClient(googleAuth.HTTPClient())
+	defer gock.Off()
+
+	tests := []struct {
+		name     string
+		code     int
+		expected error
+	}{
+		{name: "permission_denied", code: http.StatusForbidden, expected: sheets.ErrPermissionDenied},
+		{name: "spreadsheet_not_found", code: http.StatusNotFound, expected: sheets.ErrSpreadsheetNotFound},
+		{name: "quota_exceeded", code: http.StatusTooManyRequests, expected: sheets.ErrQuotaExceeded},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			gock.New("https://sheets.googleapis.com").
+				Post("/v4/spreadsheets/spreadsheet/values/.*:append").
+				Reply(tc.code).
+				JSON(map[string]interface{}{
+					"error": map[string]interface{}{"code": tc.code, "message": http.StatusText(tc.code)},
+				})
+
+			err := store.Insert(person{Name: "name1"}).Exec(context.Background())
+			assert.ErrorIs(t, err, tc.expected)
+
+			var apiErr *sheets.APIError
+			assert.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, tc.code, apiErr.StatusCode)
+
+			var googleErr *googleapi.Error
+			assert.ErrorAs(t, err, &googleErr)
+			assert.Equal(t, tc.code, googleErr.Code)
+			assert.True(t, gock.IsDone())
+		})
+	}
+}
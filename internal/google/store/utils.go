@@ -2,26 +2,119 @@ package store
 
 import (
 	"context"
-	"time"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
 
+	"github.com/FreeLeh/GoFreeDB/internal/common"
+	"github.com/FreeLeh/GoFreeDB/internal/google/file"
+	"github.com/FreeLeh/GoFreeDB/internal/google/memory"
 	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
 )
 
-func ensureSheets(wrapper sheetsWrapper, spreadsheetID string, sheetName string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*60)
-	defer cancel()
+// newSheetsWrapper creates the wrapper used by the stores.
+// The custom wrapper is used as is if it is given. Otherwise, the data is stored in the memory or the file backend
+// if one is given, or in Google Sheets.
+func newSheetsWrapper(
+	auth sheets.AuthClient,
+	custom SheetsWrapper,
+	memoryBackend *memory.Backend,
+	fileBackend *file.Backend,
+	config sheets.WrapperConfig,
+) (SheetsWrapper, error) {
+	set := 0
+	for _, ok := range []bool{custom != nil, memoryBackend != nil, fileBackend != nil} {
+		if ok {
+			set++
+		}
+	}
+
+	switch {
+	case set > 1:
+		return nil, errors.New("only one of Wrapper, MemoryBackend and FileBackend can be set")
+	case custom != nil:
+		return custom, nil
+	case memoryBackend != nil:
+		return memory.NewWrapper(memoryBackend, config.ValueRenderOption), nil
+	case fileBackend != nil:
+		return file.NewWrapper(fileBackend, config.ValueRenderOption), nil
+	}
+
+	wrapper, err := sheets.NewWrapperWithConfig(auth, config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating sheets wrapper: %w", err)
+	}
+	return wrapper, nil
+}
+
+// ensureSheets creates the sheet with the given grid size, in case it does not exist yet.
+func ensureSheets(
+	ctx context.Context,
+	wrapper SheetsWrapper,
+	spreadsheetID string,
+	sheetName string,
+	size sheets.GridSize,
+) error {
+	sheetNameToID, err := wrapper.GetSheetNameToID(ctx, spreadsheetID)
+	if err != nil {
+		return fmt.Errorf("error getting the existing sheets: %w", err)
+	}
+	if _, ok := sheetNameToID[sheetName]; ok {
+		return nil
+	}
+
+	err = wrapper.CreateSheetWithGridSize(ctx, spreadsheetID, sheetName, size)
+	// The sheet may have been created by another store instance after the existence check above.
+	if errors.Is(err, sheets.ErrConflict) {
+		return nil
+	}
+	return err
+}
 
-	return wrapper.CreateSheet(ctx, spreadsheetID, sheetName)
+// isRawRowType returns true if the select statement output element type receives each row as a slice of values.
+func isRawRowType(t reflect.Type) bool {
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Uint8
+}
+
+// isScalarType returns true if the select statement output element type receives a single column value.
+// Note that []byte and math/big types are considered as a scalar value.
+func isScalarType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if common.IsBigNumberType(t) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Interface, reflect.Array:
+		return false
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	default:
+		return true
+	}
+}
+
+// getA1RowNumber returns the row number of the given A1 notation cell (e.g. 10 for "AB10").
+func getA1RowNumber(cell string) (int64, error) {
+	idx := strings.IndexFunc(cell, unicode.IsDigit)
+	if idx == -1 {
+		return 0, fmt.Errorf("cell %q does not have a row number", cell)
+	}
+	return strconv.ParseInt(cell[idx:], 10, 64)
 }
 
 func findScratchpadLocation(
-	wrapper sheetsWrapper,
+	ctx context.Context,
+	wrapper SheetsWrapper,
 	spreadsheetID string,
 	scratchpadSheetName string,
 ) (sheets.A1Range, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*60)
-	defer cancel()
-
 	result, err := wrapper.OverwriteRows(
 		ctx,
 		spreadsheetID,
@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
+	"github.com/stretchr/testify/assert"
+)
+
+type createSheetWrapper struct {
+	sheets.MockWrapper
+	created []string
+	sizes   []sheets.GridSize
+}
+
+func (w *createSheetWrapper) CreateSheetWithGridSize(ctx context.Context, spreadsheetID string, sheetName string, size sheets.GridSize) error {
+	w.created = append(w.created, sheetName)
+	w.sizes = append(w.sizes, size)
+	return w.CreateSheetError
+}
+
+func TestGetA1RowNumber(t *testing.T) {
+	row, err := getA1RowNumber("A10")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10), row)
+
+	row, err = getA1RowNumber("AB2")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), row)
+
+	_, err = getA1RowNumber("AB")
+	assert.NotNil(t, err)
+}
+
+func TestEnsureSheets(t *testing.T) {
+	size := sheets.GridSize{ColumnCount: kvColumnCount}
+
+	t.Run("sheet_exists", func(t *testing.T) {
+		wrapper := &createSheetWrapper{}
+		wrapper.GetSheetNameToIDResult = map[string]int64{"sheet1": 1}
+
+		assert.Nil(t, ensureSheets(context.Background(), wrapper, "spreadsheet", "sheet1", size))
+		assert.Empty(t, wrapper.created)
+	})
+
+	t.Run("sheet_missing", func(t *testing.T) {
+		wrapper := &createSheetWrapper{}
+		wrapper.GetSheetNameToIDResult = map[string]int64{"other": 1}
+
+		assert.Nil(t, ensureSheets(context.Background(), wrapper, "spreadsheet", "sheet1", size))
+		assert.Equal(t, []string{"sheet1"}, wrapper.created)
+		assert.Equal(t, []sheets.GridSize{size}, wrapper.sizes)
+	})
+
+	t.Run("created_concurrently", func(t *testing.T) {
+		wrapper := &createSheetWrapper{}
+		wrapper.CreateSheetError = fmt.Errorf("%w: sheet already exists", sheets.ErrConflict)
+
+		assert.Nil(t, ensureSheets(context.Background(), wrapper, "spreadsheet", "sheet1", size))
+	})
+
+	t.Run("get_sheets_error", func(t *testing.T) {
+		wrapper := &createSheetWrapper{}
+		wrapper.GetSheetNameToIDError = fmt.Errorf("%w: no access", sheets.ErrPermissionDenied)
+
+		err := ensureSheets(context.Background(), wrapper, "spreadsheet", "sheet1", size)
+		assert.ErrorIs(t, err, sheets.ErrPermissionDenied)
+		assert.Empty(t, wrapper.created)
+	})
+
+	t.Run("create_sheet_error", func(t *testing.T) {
+		wrapper := &createSheetWrapper{}
+		wrapper.CreateSheetError = errors.New("network error")
+
+		assert.NotNil(t, ensureSheets(context.Background(), wrapper, "spreadsheet", "sheet1", size))
+	})
+}
@@ -1,5 +1,7 @@
 package models
 
+import "errors"
+
 // OrderBy defines the type of column ordering used for GoogleSheetRowStore.Select().
 type OrderBy string
 
@@ -14,3 +16,38 @@ type ColumnOrderBy struct {
 	Column  string
 	OrderBy OrderBy
 }
+
+// QueryOption defines the options clause used for GoogleSheetRowStore.Select().
+type QueryOption string
+
+const (
+	// QueryOptionNoFormat returns only the underlying values without the formatted values.
+	// This makes the query result independent of the spreadsheet locale and faster for large tables.
+	QueryOptionNoFormat QueryOption = "no_format"
+
+	// QueryOptionNoValues returns only the formatted values without the underlying values.
+	QueryOptionNoValues QueryOption = "no_values"
+)
+
+// ColumnMetadata describes a column returned by GoogleSheetRowStore.Select().
+type ColumnMetadata struct {
+	// Name is the selected column name, or its alias if it is provided.
+	Name string
+
+	// Label is the column label returned by Google Sheets, either from the header row or from the alias.
+	Label string
+
+	// Type is one of "boolean", "number", "string", "date", "datetime" and "timeofday".
+	Type string
+
+	// Pattern is the number format pattern of the column (e.g. "General" or "yyyy-mm-dd"), if any.
+	Pattern string
+}
+
+var (
+	// ErrRowNotFound is returned only for the row store when selecting a single row and there is no matching row.
+	ErrRowNotFound = errors.New("error row not found")
+
+	// ErrInserterClosed is returned when using a row store inserter after it has been closed.
+	ErrInserterClosed = errors.New("error inserter closed")
+)
@@ -15,8 +15,10 @@ type (
 )
 
 var (
-	NewGoogleSheetKVStore   = store.NewGoogleSheetKVStore
-	NewGoogleSheetKVStoreV2 = store.NewGoogleSheetKVStoreV2
+	NewGoogleSheetKVStore              = store.NewGoogleSheetKVStore
+	NewGoogleSheetKVStoreWithContext   = store.NewGoogleSheetKVStoreWithContext
+	NewGoogleSheetKVStoreV2            = store.NewGoogleSheetKVStoreV2
+	NewGoogleSheetKVStoreV2WithContext = store.NewGoogleSheetKVStoreV2WithContext
 
 	KVModeDefault    = models.KVModeDefault
 	KVModeAppendOnly = models.KVModeAppendOnly
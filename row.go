@@ -1,6 +1,9 @@
 package freedb
 
 import (
+	"github.com/FreeLeh/GoFreeDB/internal/google/file"
+	"github.com/FreeLeh/GoFreeDB/internal/google/memory"
+	"github.com/FreeLeh/GoFreeDB/internal/google/sheets"
 	"github.com/FreeLeh/GoFreeDB/internal/google/store"
 	"github.com/FreeLeh/GoFreeDB/internal/models"
 )
@@ -13,14 +16,110 @@ type (
 	GoogleSheetInsertStmt = store.GoogleSheetInsertStmt
 	GoogleSheetUpdateStmt = store.GoogleSheetUpdateStmt
 	GoogleSheetDeleteStmt = store.GoogleSheetDeleteStmt
+	GoogleSheetCountStmt  = store.GoogleSheetCountStmt
 
-	ColumnOrderBy = models.ColumnOrderBy
-	OrderBy       = models.OrderBy
+	Explanation = store.Explanation
+
+	GoogleSheetRowIterator = store.GoogleSheetRowIterator
+
+	GoogleSheetInserter       = store.GoogleSheetInserter
+	GoogleSheetInserterConfig = store.GoogleSheetInserterConfig
+
+	BulkProgress   = store.BulkProgress
+	BulkError      = store.BulkError
+	BulkChunkError = store.BulkChunkError
+
+	ColumnOrderBy  = models.ColumnOrderBy
+	OrderBy        = models.OrderBy
+	ColumnMetadata = models.ColumnMetadata
+	QueryOption    = models.QueryOption
+
+	ValueInputOption     = sheets.ValueInputOption
+	ValueRenderOption    = sheets.ValueRenderOption
+	DateTimeRenderOption = sheets.DateTimeRenderOption
+
+	RetryPolicy       = sheets.RetryPolicy
+	RateLimiter       = sheets.RateLimiter
+	RateLimiterConfig = sheets.RateLimiterConfig
+	Endpoints         = sheets.Endpoints
+	MemoryBackend     = memory.Backend
+	FileBackend       = file.Backend
+	FileFormat        = file.Format
+	APIError          = sheets.APIError
+	QueryError        = sheets.QueryError
+	QueryWarning      = sheets.QueryWarning
+
+	SheetsWrapper          = store.SheetsWrapper
+	Wrapper                = sheets.Wrapper
+	WrapperConfig          = sheets.WrapperConfig
+	AuthClient             = sheets.AuthClient
+	A1Range                = sheets.A1Range
+	GridSize               = sheets.GridSize
+	InsertRowsResult       = sheets.InsertRowsResult
+	UpdateRowsResult       = sheets.UpdateRowsResult
+	BatchUpdateRowsRequest = sheets.BatchUpdateRowsRequest
+	BatchUpdateRowsResult  = sheets.BatchUpdateRowsResult
+	QueryRowsResult        = sheets.QueryRowsResult
+	QueryRowsColumn        = sheets.QueryRowsColumn
+
+	Interceptor     = store.Interceptor
+	Invoker         = store.Invoker
+	Operation       = store.Operation
+	OperationKind   = store.OperationKind
+	Attribute       = store.Attribute
+	Tracer          = store.Tracer
+	Span            = store.Span
+	MetricsRecorder = store.MetricsRecorder
+
+	CacheConfig = store.CacheConfig
 )
 
 var (
-	NewGoogleSheetRowStore = store.NewGoogleSheetRowStore
+	NewGoogleSheetRowStore            = store.NewGoogleSheetRowStore
+	NewGoogleSheetRowStoreWithContext = store.NewGoogleSheetRowStoreWithContext
+
+	DefaultRetryPolicy = sheets.DefaultRetryPolicy
+	IsRetryableError   = sheets.IsRetryableError
+	NewRateLimiter     = sheets.NewRateLimiter
+	NewMemoryBackend   = memory.NewBackend
+	NewFileBackend     = file.NewBackend
+
+	NewWrapper           = sheets.NewWrapper
+	NewWrapperWithConfig = sheets.NewWrapperWithConfig
+	NewA1Range           = sheets.NewA1Range
+
+	NewTracingInterceptor = store.NewTracingInterceptor
+	NewMetricsInterceptor = store.NewMetricsInterceptor
 
 	OrderByAsc  = models.OrderByAsc
 	OrderByDesc = models.OrderByDesc
+
+	QueryOptionNoFormat = models.QueryOptionNoFormat
+	QueryOptionNoValues = models.QueryOptionNoValues
+
+	ErrRowNotFound    = models.ErrRowNotFound
+	ErrInserterClosed = models.ErrInserterClosed
+
+	ErrInvalidQuery        = sheets.ErrInvalidQuery
+	ErrPermissionDenied    = sheets.ErrPermissionDenied
+	ErrSpreadsheetNotFound = sheets.ErrSpreadsheetNotFound
+	ErrSheetNotFound       = sheets.ErrSheetNotFound
+	ErrQuotaExceeded       = sheets.ErrQuotaExceeded
+	ErrInvalidRange        = sheets.ErrInvalidRange
+	ErrConflict            = sheets.ErrConflict
+	ErrRateLimitExceeded   = sheets.ErrRateLimitExceeded
+
+	ValueInputUserEntered         = sheets.ValueInputUserEntered
+	ValueInputRaw                 = sheets.ValueInputRaw
+	ValueRenderFormatted          = sheets.ValueRenderFormatted
+	ValueRenderUnformatted        = sheets.ValueRenderUnformatted
+	ValueRenderFormula            = sheets.ValueRenderFormula
+	DateTimeRenderSerialNumber    = sheets.DateTimeRenderSerialNumber
+	DateTimeRenderFormattedString = sheets.DateTimeRenderFormattedString
+
+	FileFormatCSV  = file.FormatCSV
+	FileFormatXLSX = file.FormatXLSX
+
+	OperationKindStatement = store.OperationKindStatement
+	OperationKindWrapper   = store.OperationKindWrapper
 )